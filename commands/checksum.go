@@ -0,0 +1,128 @@
+package root
+
+import (
+	"bytes"
+	"cli/internal/exception"
+	"cli/internal/fs/checksum"
+	"cli/internal/i18n"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var checksumCmd = &cobra.Command{
+	Use:   "checksum",
+	Short: "Calculate and verify file and directory checksums",
+}
+
+var checksumDirectoryCmd = &cobra.Command{
+	Use:   "directory <path>",
+	Short: "Calculate a deterministic digest of an entire directory",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		modes, _ := cmd.Flags().GetBool("modes")
+		empty, _ := cmd.Flags().GetBool("empty")
+
+		digest, e := checksum.Directory(args[0], checksum.Algorithm(settings.Hasher), checksum.DirectoryOptions{
+			Excludes: settings.Excludes,
+			Modes:    modes,
+			Empty:    empty,
+		})
+		if e != nil {
+			return e
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%s:%s  %s\n", settings.Hasher, digest, args[0])
+
+		return nil
+	},
+}
+
+var checksumVerifyCmd = &cobra.Command{
+	Use:   "verify <file> <digest>",
+	Short: "Verify a file against an expected digest",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		algorithm := checksum.Algorithm(settings.Hasher)
+		if label, _, labeled := strings.Cut(args[1], ":"); labeled && !(cmd.Flags().Changed("hasher")) {
+			algorithm = checksum.Algorithm(strings.ToLower(label))
+		}
+
+		valid, e := checksum.Verify(args[0], args[1], algorithm)
+		if e != nil {
+			return e
+		} else if !(valid) {
+			return exception.New(exception.EVERIFYMISMATCH, "verify", args[0], nil)
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), i18n.T(i18n.ChecksumVerified, args[0]))
+
+		return nil
+	},
+}
+
+var checksumHMACCmd = &cobra.Command{
+	Use:   "hmac <path>",
+	Short: "Calculate an authenticated digest of a file or directory",
+	Long: `Calculate an authenticated (HMAC) digest of a file or directory.
+
+The key is read from --key-file, or from the CLI_HMAC_KEY environment variable.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key := []byte(os.Getenv("CLI_HMAC_KEY"))
+		if path, _ := cmd.Flags().GetString("key-file"); path != "" {
+			buffer, e := os.ReadFile(path)
+			if e != nil {
+				return e
+			}
+
+			key = bytes.TrimRight(buffer, "\r\n")
+		}
+
+		algorithm := checksum.Algorithm(settings.Hasher)
+
+		info, e := os.Stat(args[0])
+		if e != nil {
+			return e
+		}
+
+		var digest string
+		if info.IsDir() {
+			digest, e = checksum.Directory(args[0], algorithm, checksum.DirectoryOptions{Excludes: settings.Excludes, Key: key})
+		} else {
+			digest, e = checksum.HMAC(args[0], key, algorithm)
+		}
+
+		if e != nil {
+			return e
+		}
+
+		if expected, _ := cmd.Flags().GetString("verify"); expected != "" {
+			expected = strings.TrimPrefix(expected, algorithm.Keyed()+":")
+			if !(checksum.Equal(digest, strings.ToLower(expected))) {
+				return exception.New(exception.EVERIFYMISMATCH, "hmac", args[0], nil)
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), i18n.T(i18n.ChecksumVerified, args[0]))
+
+			return nil
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "%s:%s  %s\n", algorithm.Keyed(), digest, args[0])
+
+		return nil
+	},
+}
+
+func init() {
+	checksumDirectoryCmd.Flags().Bool("modes", false, "include permission bits in the digest")
+	checksumDirectoryCmd.Flags().Bool("empty", false, "include empty directories in the digest")
+
+	checksumHMACCmd.Flags().String("key-file", "", "file holding the HMAC key (env CLI_HMAC_KEY)")
+	checksumHMACCmd.Flags().String("verify", "", "verify against an expected authenticated digest rather than printing it")
+
+	checksumCmd.AddCommand(checksumDirectoryCmd, checksumVerifyCmd, checksumHMACCmd)
+	rootCmd.AddCommand(checksumCmd)
+}
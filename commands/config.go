@@ -0,0 +1,103 @@
+package root
+
+import (
+	"cli/internal/config"
+	"cli/internal/daemon"
+	"cli/internal/exception"
+	"cli/internal/i18n"
+	"cli/internal/prune"
+	"cli/internal/render"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration file",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		locate(cmd)
+
+		return nil
+	},
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Validate the configuration file and the ignore files it references",
+	Long: `Validate the configuration file and the ignore files it references, reporting unknown
+keys, bad globs, and conflicting options with their positions.
+
+With --rules, a daemon's alerting rules file is validated as well; with --older-than,
+--larger-than, or --match, a prune's retention policy. The default configuration file
+may then be missing.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+
+		others := flags.Changed("rules") || flags.Changed("older-than") || flags.Changed("larger-than") || flags.Changed("match")
+
+		validated := []string{configuration}
+		issues, e := config.Validate(configuration)
+		if errors.Is(e, os.ErrNotExist) && configuration == config.Filename && others {
+			validated = nil
+		} else if e != nil {
+			return e
+		}
+
+		if path, _ := flags.GetString("rules"); path != "" {
+			validated = append(validated, path)
+
+			found, e := daemon.Validate(path)
+			if e != nil {
+				return e
+			}
+
+			issues = append(issues, found...)
+		}
+
+		if value, _ := flags.GetString("older-than"); value != "" {
+			if _, e := prune.ParseAge(value); e != nil {
+				issues = append(issues, config.Issue{File: "--older-than", Message: e.Error()})
+			}
+		}
+
+		if value, _ := flags.GetString("larger-than"); value != "" {
+			if _, e := render.ParseBytes(value); e != nil {
+				issues = append(issues, config.Issue{File: "--larger-than", Message: e.Error()})
+			}
+		}
+
+		match, _ := flags.GetStringSlice("match")
+		if e := (&prune.Policy{Match: match}).Validate(); e != nil {
+			issues = append(issues, config.Issue{File: "--match", Message: e.Error()})
+		}
+
+		for _, issue := range issues {
+			fmt.Fprintln(cmd.OutOrStdout(), issue)
+		}
+
+		if len(issues) > 0 {
+			return exception.New(exception.ECONFIG, "validate", strings.Join(validated, ", "), errors.New(i18n.T(i18n.ConfigIssues, len(issues))))
+		}
+
+		for _, name := range validated {
+			fmt.Fprintln(cmd.OutOrStdout(), i18n.T(i18n.ConfigValid, name))
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	configValidateCmd.Flags().String("rules", "", "daemon alerting rules file to validate")
+	configValidateCmd.Flags().String("older-than", "", "prune age condition to validate")
+	configValidateCmd.Flags().String("larger-than", "", "prune size condition to validate")
+	configValidateCmd.Flags().StringSlice("match", nil, "prune glob patterns to validate")
+
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,276 @@
+package root
+
+import (
+	"cli/internal/checklist"
+	"cli/internal/exception"
+	"cli/internal/fs/tree"
+	"cli/internal/fs/volume"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"cli/internal/report"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var copyCmd = &cobra.Command{
+	Use:   "copy <source> <destination>...",
+	Short: "Copy a tree to one or more destinations",
+	Long: `Copy a tree's directories and files to one or more destinations.
+
+Copies to several destinations are transactional per destination: a destination whose
+copy fails is rolled back, without affecting the others, and a consolidated report of
+every destination's outcome is written.
+
+Modes:
+  copy       never overwrite existing files (default)
+  replicate  overwrite existing files
+  replace    atomically replace the destination`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		options := []tree.Option{}
+		if all, _ := cmd.Flags().GetBool("preserve-all"); all {
+			metadata, contexts = true, tree.ContextsPreserve
+			options = append(options, tree.WithPreserveCapabilities())
+		}
+
+		if handle, _ := cmd.Flags().GetBool("handle-immutable"); handle {
+			options = append(options, tree.WithHandleImmutable())
+		}
+
+		if special, _ := cmd.Flags().GetBool("preserve-special"); special {
+			options = append(options, tree.WithPreserveSpecial())
+		}
+
+		if specification, _ := cmd.Flags().GetString("chown"); specification != "" {
+			owner, e := tree.ParseOwner(specification)
+			if e != nil {
+				return e
+			}
+
+			options = append(options, tree.WithChown(owner))
+		}
+
+		chmod, _ := cmd.Flags().GetStringSlice("chmod")
+		for _, specification := range append(append([]string{}, settings.Modes...), chmod...) {
+			rule, e := tree.ParseRule(specification)
+			if e != nil {
+				return e
+			}
+
+			options = append(options, tree.WithRules(rule))
+		}
+
+		if policy, _ := cmd.Flags().GetString("unreadable"); tree.Unreadable(policy).Valid() {
+			options = append(options, tree.WithUnreadable(tree.Unreadable(policy)))
+		} else {
+			return fmt.Errorf("unsupported unreadable policy: %s", policy)
+		}
+
+		var budget int64
+		if value, _ := cmd.Flags().GetString("max-bytes"); value != "" {
+			limit, e := render.ParseBytes(value)
+			if e != nil {
+				return fmt.Errorf("invalid --max-bytes: %w", e)
+			}
+
+			priority, _ := cmd.Flags().GetString("priority")
+			if !(tree.Priority(priority).Valid()) {
+				return fmt.Errorf("unsupported copy priority: %s", priority)
+			}
+
+			budget = limit
+			options = append(options, tree.WithMaxBytes(limit, tree.Priority(priority)))
+		}
+
+		if selecting, _ := cmd.Flags().GetBool("select"); selecting {
+			keep, e := choose(args[0])
+			if e != nil {
+				return e
+			}
+
+			options = append(options, tree.WithFilter(keep))
+		}
+
+		t, e := walk(cmd, args[:1], options...)
+		if e != nil {
+			return e
+		}
+
+		mode, _ := cmd.Flags().GetString("mode")
+		operation, valid := operations[mode]
+		if !(valid) {
+			return fmt.Errorf("unsupported copy mode: %s", mode)
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		dry, _ := cmd.Flags().GetBool("preflight")
+
+		destinations := args[1:]
+		if len(destinations) == 1 {
+			p, e := volume.Check(t.Path, destinations[0], t.CountFiles(), uint64(copied(t, budget)))
+			if e != nil {
+				return e
+			}
+
+			if dry {
+				return write(cmd, p)
+			}
+
+			if e := preflight(cmd, p, force); e != nil {
+				return e
+			}
+
+			e = guard(func() {
+				operation(t, destinations[0])
+			})
+		} else {
+			e = fanout(cmd, t, destinations, mode, budget, force, dry)
+		}
+
+		for _, path := range t.Skipped() {
+			fmt.Fprintln(cmd.ErrOrStderr(), i18n.T(i18n.CopySkipped, path))
+		}
+
+		for _, path := range t.Omitted() {
+			fmt.Fprintln(cmd.ErrOrStderr(), i18n.T(i18n.CopyOmitted, path, render.Bytes(budget)))
+		}
+
+		return e
+	},
+}
+
+// operations are the tree's copy operations, by mode.
+var operations = map[string]func(n *tree.Node, destination string){
+	"copy":      (*tree.Node).Copy,
+	"replicate": (*tree.Node).Replicate,
+	"replace":   (*tree.Node).Replace,
+}
+
+// fanout copies t, within the --max-bytes budget, to every destination, each transactionally, and writes the consolidated report;
+// with dry, only every destination's preflight report is written.
+func fanout(cmd *cobra.Command, t *tree.Node, destinations []string, mode string, budget int64, force, dry bool) error {
+	f := &report.Fanout{Source: t.Path, Mode: mode, Files: t.CountFiles(), Bytes: t.CountBytes()}
+	for _, destination := range destinations {
+		p, e := volume.Check(t.Path, destination, t.CountFiles(), uint64(copied(t, budget)))
+		if e == nil && dry {
+			e = write(cmd, p)
+			if e == nil {
+				continue
+			}
+		}
+
+		if e == nil {
+			e = preflight(cmd, p, force)
+		}
+
+		if e == nil {
+			e = t.Transact(destination, operations[mode])
+		}
+
+		f.Add(destination, e)
+	}
+
+	if dry && f.Failed() == 0 {
+		return nil
+	}
+
+	if e := write(cmd, f); e != nil {
+		return e
+	}
+
+	if failed := f.Failed(); failed > 0 {
+		return fmt.Errorf("%d of %d destination(s) failed and were rolled back", failed, len(destinations))
+	}
+
+	return nil
+}
+
+// choose walks source, without hashing, for its files to be selected interactively in the terminal's
+// checklist, returning the filter of the selected files.
+func choose(source string) (func(n *tree.Node) bool, error) {
+	if !(render.Terminal(os.Stdin)) || !(render.Terminal(os.Stderr)) {
+		return nil, errors.New("--select requires a terminal")
+	}
+
+	t, e := tree.Walk(source, append(options(), tree.WithoutHashing())...)
+	if e != nil && !(errors.Is(e, tree.ExceptionWalkPartial)) {
+		return nil, e
+	}
+
+	c := checklist.New(t)
+	if confirmed, e := c.Run(os.Stdin, os.Stderr, render.Detect(os.Stderr, plain)); e != nil {
+		return nil, e
+	} else if !(confirmed) {
+		return nil, errors.New("selection cancelled")
+	}
+
+	selected := c.Selected()
+	if len(selected) == 0 {
+		return nil, errors.New("no files selected")
+	}
+
+	return func(n *tree.Node) bool {
+		return selected[n.Path]
+	}, nil
+}
+
+// copied returns the bytes a copy of t writes at most: its total, or the --max-bytes budget if smaller.
+func copied(t *tree.Node, budget int64) int64 {
+	if budget > 0 {
+		return min(t.CountBytes(), budget)
+	}
+
+	return t.CountBytes()
+}
+
+// preflight checks the destination's volume before a copy: copies that would exhaust the volume,
+// or more than half of a volatile (tmpfs, overlay) volume, require force. Volatile destinations
+// are always warned about.
+func preflight(cmd *cobra.Command, p *volume.Preflight, force bool) error {
+	if p.Volatile() {
+		fmt.Fprintln(cmd.ErrOrStderr(), i18n.T(i18n.CopyVolatile, p.Destination, p.DestinationVolume.Type, render.Bytes(int64(p.DestinationVolume.Available)), render.Bytes(int64(p.Bytes))))
+	}
+
+	if !(p.Sufficient()) && !(force) {
+		return exception.New(exception.ECAPACITY, "copy", p.Destination, errors.New(i18n.T(i18n.CopyForce, render.Bytes(int64(p.Bytes)), p.Source)))
+	}
+
+	return nil
+}
+
+// guard converts a panic of fn, as raised by the tree's copy operations, into an error.
+func guard(fn func()) (e error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if exception, valid := r.(error); valid {
+				e = exception
+			} else {
+				e = fmt.Errorf("%v", r)
+			}
+		}
+	}()
+
+	fn()
+
+	return nil
+}
+
+func init() {
+	copyCmd.Flags().String("mode", "copy", "copy mode: copy, replicate, replace")
+	copyCmd.Flags().Bool("select", false, "choose the files copied interactively, in a checklist of the source tree")
+	copyCmd.Flags().String("max-bytes", "", "budget of file contents copied, e.g. 4GB; files beyond it are omitted and reported")
+	copyCmd.Flags().String("priority", string(tree.PrioritySmallest), "order in which files claim the --max-bytes budget: smallest, largest, newest, oldest, path")
+	copyCmd.Flags().Bool("force", false, "copy even if the destination's capacity would be exhausted")
+	copyCmd.Flags().Bool("preserve-all", false, "preserve all metadata: file flags, extended attributes and capabilities, and security contexts")
+	copyCmd.Flags().Bool("handle-immutable", false, "clear, then reapply, the immutable and append-only flags of a replaced destination")
+	copyCmd.Flags().Bool("preserve-special", false, "keep the setuid, setgid, and sticky bits of copied files, which are stripped by default")
+	copyCmd.Flags().String("unreadable", "fail", "policy for files that can't be read: fail, skip, record (skip with a warning)")
+	copyCmd.Flags().String("chown", "", "owner assigned to everything copied, as user, user:group, or :group (names or IDs)")
+	copyCmd.Flags().StringSlice("chmod", nil, "permissions rule(s) PATTERN=MODE, e.g. '*.sh=0755', 'secrets/*=0600', 'secrets/=0700'; the last match wins")
+	copyCmd.Flags().Bool("preflight", false, "only report the source and destination volumes' capacity, without copying")
+
+	rootCmd.AddCommand(copyCmd)
+}
@@ -0,0 +1,138 @@
+package root
+
+import (
+	"cli/internal/daemon"
+	"cli/internal/fs/tree"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon [path]",
+	Short: "Periodically walk a tree, alerting when its rules' size thresholds are crossed",
+	Long: `Periodically walk a tree and evaluate alerting rules after every walk.
+
+Rules are read from a YAML file, e.g.
+
+  rules:
+    - name: logs-growth
+      path: var/log
+      growth: 10     # percent
+      window: 1h
+    - name: huge-files
+      max-file-size: 2GB
+
+Alerts are logged to stderr when they start and stop firing, and optionally posted
+to a webhook and written as Prometheus textfile metrics.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, _ := cmd.Flags().GetString("rules")
+
+		rules, e := daemon.Load(path)
+		if e != nil {
+			return e
+		}
+
+		d := &daemon.Daemon{
+			Rules:     rules,
+			Notifiers: []daemon.Notifier{&daemon.Log{W: cmd.ErrOrStderr()}},
+			Walk: func() (*tree.Node, error) {
+				return walk(cmd, args)
+			},
+			Errors: func(e error) {
+				fmt.Fprintln(cmd.ErrOrStderr(), e)
+			},
+		}
+
+		d.Interval, _ = cmd.Flags().GetDuration("interval")
+		if d.Interval <= 0 {
+			return fmt.Errorf("--interval must be positive")
+		}
+
+		if path, _ := cmd.Flags().GetString("ledger"); path != "" {
+			if d.Ledger, e = daemon.Open(path); e != nil {
+				return e
+			}
+		}
+
+		if url, _ := cmd.Flags().GetString("webhook"); url != "" {
+			d.Notifiers = append(d.Notifiers, &daemon.Webhook{URL: url})
+		}
+
+		if metrics, _ := cmd.Flags().GetString("metrics"); metrics != "" {
+			d.Notifiers = append(d.Notifiers, &daemon.Metrics{Path: metrics})
+		}
+
+		if address, _ := cmd.Flags().GetString("debug-addr"); address != "" {
+			tracking = true
+			d.Progress = current.Load
+
+			listener, e := net.Listen("tcp", address)
+			if e != nil {
+				return e
+			}
+
+			server := &http.Server{Handler: d.Handler(), ReadHeaderTimeout: 10 * time.Second}
+			defer server.Close()
+
+			go func() {
+				if e := server.Serve(listener); e != nil && !(errors.Is(e, http.ErrServerClosed)) {
+					fmt.Fprintln(cmd.ErrOrStderr(), e)
+				}
+			}()
+		}
+
+		return d.Run(cmd.Context())
+	},
+}
+
+var mismatchesCmd = &cobra.Command{
+	Use:   "mismatches <ledger>",
+	Short: "Report the files whose checksums changed across daemon walks, as a heatmap over time",
+	Long: `Report the files of a daemon --ledger whose checksums changed between walks.
+
+A mismatch is transient when the file's size and modification time didn't change along with
+its checksum: files with repeated transient mismatches are suspects of failing hardware or an
+incoherent NFS cache, distinguished from files that were legitimately rewritten.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		bucket, _ := cmd.Flags().GetDuration("bucket")
+		buckets, _ := cmd.Flags().GetInt("buckets")
+		repeated, _ := cmd.Flags().GetInt("repeated")
+		if bucket <= 0 || buckets <= 0 || repeated <= 0 {
+			return fmt.Errorf("--bucket, --buckets, and --repeated must be positive")
+		}
+
+		l, e := daemon.Open(args[0])
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, l.Heatmap(bucket, buckets, repeated))
+	},
+}
+
+func init() {
+	daemonCmd.Flags().String("rules", "", "alerting rules file")
+	daemonCmd.Flags().Duration("interval", 15*time.Minute, "time between walks")
+	daemonCmd.Flags().String("webhook", "", "URL alert transitions are posted to as JSON")
+	daemonCmd.Flags().String("metrics", "", "file the firing alerts are written to, in the Prometheus textfile format")
+
+	daemonCmd.Flags().String("ledger", "", "file the checksum verification results of every walk are persisted to")
+
+	daemonCmd.Flags().String("debug-addr", "", "address serving net/http/pprof and a status page, e.g. localhost:6060")
+
+	daemonCmd.MarkFlagRequired("rules")
+
+	mismatchesCmd.Flags().Duration("bucket", 24*time.Hour, "time span of each heatmap cell")
+	mismatchesCmd.Flags().Int("buckets", 30, "heatmap cells, ending at the ledger's last walk")
+	mismatchesCmd.Flags().Int("repeated", 2, "transient mismatches of a file that make it a suspect")
+
+	daemonCmd.AddCommand(mismatchesCmd)
+	rootCmd.AddCommand(daemonCmd)
+}
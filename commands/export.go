@@ -0,0 +1,62 @@
+package root
+
+import (
+	"cli/internal/export"
+	"cli/internal/fs/tree"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a tree into another layout",
+}
+
+var exportStoreCmd = &cobra.Command{
+	Use:   "store <source> <destination>",
+	Short: "Export a tree's top-level entries as content-addressed, Nix store-style paths, with a mapping manifest",
+	Long: `Export each top-level entry of a tree to <destination>/<hash>-<name>, where the hash, in
+Nix's base-32, digests the entry's name and content. Exports are reproducible and
+collision-free: identical entries always get the same store path, which is reused if
+present, and different entries never share one.
+
+The manifest mapping each entry to its store path is written to --manifest, by default
+<destination>/manifest.json, and output in the selected format.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Store digests every byte as it exports it; the walk doesn't hash files, so they're not read twice.
+		t, e := walk(cmd, args[:1], tree.WithoutHashing())
+		if e != nil {
+			return e
+		}
+
+		dry, _ := cmd.Flags().GetBool("dry-run")
+
+		m, e := export.Store(t, args[1], dry)
+		if e != nil {
+			return e
+		}
+
+		if !(dry) {
+			path, _ := cmd.Flags().GetString("manifest")
+			if path == "" {
+				path = filepath.Join(args[1], "manifest.json")
+			}
+
+			if e := m.Write(path); e != nil {
+				return e
+			}
+		}
+
+		return write(cmd, m)
+	},
+}
+
+func init() {
+	exportStoreCmd.Flags().Bool("dry-run", false, "only compute the manifest, without writing")
+	exportStoreCmd.Flags().String("manifest", "", "path of the manifest document (default <destination>/manifest.json)")
+
+	exportCmd.AddCommand(exportStoreCmd)
+	rootCmd.AddCommand(exportCmd)
+}
@@ -0,0 +1,114 @@
+package root
+
+import (
+	"bufio"
+	"cli/internal/find"
+	"cli/internal/fs/tree"
+	"cli/internal/snapshot"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var findCmd = &cobra.Command{
+	Use:   "find [--snapshot FILE] [path...] [expression]",
+	Short: "Find files with find(1)-compatible arguments, over a walk or a snapshot",
+	Long: `Find files with find(1)-compatible arguments, as a drop-in for basic invocations:
+
+  -name PATTERN, -iname PATTERN   base name matches the glob
+  -type f|d|l                     file, directory, or symbolic link
+  -size [+-]N[cwbkMG]             size in 512-byte blocks, or the given unit, rounded up
+  -mtime [+-]N                    modified N 24-hour periods ago, rounded down
+  -maxdepth N                     descend at most N levels below the starting points
+  -not, !                         negate the following test
+  -print, -print0                 print newline- or NUL-terminated paths (-print is implied)
+
+Tests are combined by AND. With --snapshot FILE, the snapshot is queried instead of
+walking, the starting points being paths within it.`,
+	DisableFlagParsing: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		for _, argument := range args {
+			if argument == "--help" || argument == "-help" || argument == "-h" {
+				return cmd.Help()
+			}
+		}
+
+		// Flag parsing is disabled for find's single-dash primaries, so the global flags are parsed here.
+		globals, args := separate(cmd, args)
+		if e := cmd.Flags().Parse(globals); e != nil {
+			return e
+		} else if e := resolve(cmd); e != nil {
+			return e
+		}
+
+		q, e := find.Parse(args)
+		if e != nil {
+			return e
+		}
+
+		var s *snapshot.Snapshot
+		if q.Snapshot != "" {
+			if s, e = snapshot.Read(q.Snapshot); e != nil {
+				return e
+			}
+		}
+
+		w := bufio.NewWriter(cmd.OutOrStdout())
+		defer w.Flush()
+
+		now := time.Now()
+		for _, path := range q.Paths {
+			var start *tree.Node
+			if s != nil {
+				if start = s.Tree.At(path); start == nil {
+					fmt.Fprintf(os.Stderr, "find: %s: No such file or directory in %s\n", path, q.Snapshot)
+					continue
+				}
+			} else {
+				// Finding reads no content, so the walk doesn't hash files.
+				extra := []tree.Option{tree.WithoutHashing()}
+				if q.MaxDepth > 0 {
+					extra = append(extra, tree.WithMaxDepth(q.MaxDepth))
+				}
+
+				if start, e = walk(cmd, []string{path}, extra...); e != nil {
+					return e
+				}
+			}
+
+			if e := q.Print(w, path, start, now); e != nil {
+				return e
+			}
+		}
+
+		return nil
+	},
+}
+
+// separate splits the find command's arguments into global flags, which are double-dashed (--snapshot aside),
+// and find's own arguments.
+func separate(cmd *cobra.Command, args []string) (globals, rest []string) {
+	for i := 0; i < len(args); i++ {
+		name, _, valued := strings.Cut(strings.TrimPrefix(args[i], "--"), "=")
+		flag := cmd.Flags().Lookup(name)
+		if !(strings.HasPrefix(args[i], "--")) || name == "snapshot" || flag == nil {
+			rest = append(rest, args[i])
+			continue
+		}
+
+		globals = append(globals, args[i])
+		if !(valued) && flag.NoOptDefVal == "" && i+1 < len(args) {
+			i++
+			globals = append(globals, args[i])
+		}
+	}
+
+	return
+}
+
+func init() {
+	rootCmd.AddCommand(findCmd)
+}
@@ -0,0 +1,29 @@
+package root
+
+import (
+	"cli/internal/report"
+
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [path]",
+	Short: "Report security-relevant paths of a tree, such as binaries granted file capabilities or immutable files",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		metadata = true
+
+		s, e := source(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, report.Lint(s.Tree))
+	},
+}
+
+func init() {
+	lintCmd.Flags().String("snapshot", "", "lint a snapshot document rather than walking a path")
+
+	rootCmd.AddCommand(lintCmd)
+}
@@ -0,0 +1,71 @@
+package root
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/permissions"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var permissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Capture and check baselines of a tree's modes and owners, for hardening checks",
+}
+
+var permissionsBaselineCmd = &cobra.Command{
+	Use:   "baseline [path]",
+	Short: "Write the baseline of the modes and owners of a tree's files and directories, ignoring content",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		forensic = true
+
+		t, e := walk(cmd, args, tree.WithoutHashing())
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, permissions.Capture(t, time.Now()))
+	},
+}
+
+var permissionsCheckCmd = &cobra.Command{
+	Use:   "check <baseline> [path]",
+	Short: "Report the mode and owner drift of a tree from a baseline, failing if anything drifted",
+	Long: `Report the mode and owner drift of a tree from a baseline, ignoring content: changed modes,
+owners, and types, and entries added or removed since the baseline was captured. Modes granting
+permissions, or setuid, setgid, or sticky bits, the baseline didn't are marked as widened.
+
+The path defaults to the baseline's root.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, e := permissions.Read(args[0])
+		if e != nil {
+			return e
+		}
+
+		forensic = true
+
+		root := b.Root
+		if len(args) > 1 {
+			root = args[1]
+		}
+
+		t, e := walk(cmd, []string{root}, tree.WithoutHashing())
+		if e != nil {
+			return e
+		}
+
+		d := b.Check(t)
+		if e := write(cmd, d); e != nil {
+			return e
+		}
+
+		return d.Err()
+	},
+}
+
+func init() {
+	permissionsCmd.AddCommand(permissionsBaselineCmd, permissionsCheckCmd)
+	rootCmd.AddCommand(permissionsCmd)
+}
@@ -0,0 +1,105 @@
+package root
+
+import (
+	"cli/internal/fs/remove"
+	"cli/internal/prune"
+	"cli/internal/render"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune <path>",
+	Short: "Delete the files of a tree selected by an age, size, and glob retention policy",
+	Long: `Delete the files of a tree selected by a retention policy. A file is selected when it
+matches every given condition.
+
+Prunes are dry runs unless --execute is given. With --journal, every selected file is
+journaled before any is deleted, and each deletion is journaled as it happens.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+
+		policy := &prune.Policy{}
+		policy.Match, _ = flags.GetStringSlice("match")
+		if e := policy.Validate(); e != nil {
+			return e
+		}
+
+		if value, _ := flags.GetString("older-than"); value != "" {
+			age, e := prune.ParseAge(value)
+			if e != nil {
+				return e
+			}
+
+			policy.OlderThan = age
+		}
+
+		if value, _ := flags.GetString("larger-than"); value != "" {
+			size, e := render.ParseBytes(value)
+			if e != nil {
+				return e
+			}
+
+			policy.LargerThan = size
+		}
+
+		if policy.Empty() {
+			return errors.New("refusing to prune without a policy: give --older-than, --larger-than, or --match")
+		}
+
+		t, e := walk(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		p := prune.Plan(t, policy, time.Now())
+
+		if execute, _ := flags.GetBool("execute"); execute {
+			var journal *prune.Journal
+			if path, _ := flags.GetString("journal"); path != "" {
+				if journal, e = prune.OpenJournal(path); e != nil {
+					return e
+				}
+
+				defer journal.Close()
+			}
+
+			unlink := os.Remove
+			if neverFollow {
+				root, e := remove.Open(t.Path)
+				if e != nil {
+					return e
+				}
+
+				unlink = root.Remove
+			}
+
+			if e := p.Execute(journal, unlink); e != nil {
+				return e
+			}
+		}
+
+		if path, _ := flags.GetString("audit-log"); path != "" {
+			if e := p.Audit(path, os.Args[1:]); e != nil {
+				return e
+			}
+		}
+
+		return write(cmd, p)
+	},
+}
+
+func init() {
+	pruneCmd.Flags().String("older-than", "", "select files last modified longer ago than this, e.g. 30d, 2w, 12h")
+	pruneCmd.Flags().String("larger-than", "", "select files larger than this, e.g. 100MB")
+	pruneCmd.Flags().StringSlice("match", nil, "select files whose name or relative path matches a glob pattern")
+	pruneCmd.Flags().Bool("execute", false, "delete the selected files, rather than only reporting them")
+	pruneCmd.Flags().String("journal", "", "journal file of planned and performed deletions (JSON lines)")
+	pruneCmd.Flags().String("audit-log", "", "audit log each prune is appended to")
+
+	rootCmd.AddCommand(pruneCmd)
+}
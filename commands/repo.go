@@ -0,0 +1,94 @@
+package root
+
+import (
+	"cli/internal/repo"
+	"cli/internal/snapshot"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+var repoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Snapshot, verify, and export repo tool manifest layouts of several sub-repositories",
+}
+
+var repoSnapshotCmd = &cobra.Command{
+	Use:   "snapshot <manifest> [checkout]",
+	Short: "Write a combined snapshot of a checkout's manifest projects, annotated with their names and revisions",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, e := repo.Read(args[0])
+		if e != nil {
+			return e
+		}
+
+		root := checkout(args)
+
+		t, e := walk(cmd, []string{root}, m.Options(root)...)
+		if e != nil {
+			return e
+		}
+
+		m.Annotate(t)
+
+		return write(cmd, snapshot.New(t, effective()))
+	},
+}
+
+var repoVerifyCmd = &cobra.Command{
+	Use:   "verify <manifest> [checkout]",
+	Short: "Verify a checkout against a manifest's expected layout",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, e := repo.Read(args[0])
+		if e != nil {
+			return e
+		}
+
+		v := repo.Verify(m, checkout(args))
+		if e := write(cmd, v); e != nil {
+			return e
+		}
+
+		return v.Err()
+	},
+}
+
+var repoExportCmd = &cobra.Command{
+	Use:   "export [path]",
+	Short: "Write the manifest of the git checkouts under a path, pinned to their checked out commits",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		m, e := repo.Discover(target(args))
+		if e != nil {
+			return e
+		}
+
+		fmt.Fprint(cmd.OutOrStdout(), m.XML())
+
+		return nil
+	},
+}
+
+// checkout returns the checkout argument of a repo command, defaulting to the parent of the manifest's .repo
+// directory, as the repo tool lays checkouts out, or else the working directory.
+func checkout(args []string) string {
+	if len(args) > 1 {
+		return args[1]
+	}
+
+	for directory := filepath.Dir(args[0]); directory != filepath.Dir(directory); directory = filepath.Dir(directory) {
+		if filepath.Base(directory) == ".repo" {
+			return filepath.Dir(directory)
+		}
+	}
+
+	return "."
+}
+
+func init() {
+	repoCmd.AddCommand(repoSnapshotCmd, repoVerifyCmd, repoExportCmd)
+	rootCmd.AddCommand(repoCmd)
+}
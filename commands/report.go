@@ -0,0 +1,230 @@
+package root
+
+import (
+	"cli/internal/cdc"
+	"cli/internal/fs/tree"
+	"cli/internal/render"
+	"cli/internal/report"
+	"cli/internal/snapshot"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	grouping string
+	depth    int
+)
+
+var summaryCmd = &cobra.Command{
+	Use:   "summary [path]",
+	Short: "Summarize the files, directories, and bytes of a tree",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		g, e := report.Parse(grouping)
+		if e != nil {
+			return e
+		}
+
+		s, e := source(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, report.Summarize(s.Tree, g))
+	},
+}
+
+var duCmd = &cobra.Command{
+	Use:   "du [path]",
+	Short: "Report the cumulative disk usage of a tree's directories",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		g, e := report.Parse(grouping)
+		if e != nil {
+			return e
+		}
+
+		s, e := source(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, report.Du(s.Tree, depth, g))
+	},
+}
+
+var ownersCmd = &cobra.Command{
+	Use:   "owners [path]",
+	Short: "Report the files and bytes owned per user and group",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, e := source(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, report.ByOwner(s.Tree))
+	},
+}
+
+var activityCmd = &cobra.Command{
+	Use:   "activity [path]",
+	Short: "Report a heatmap of recently modified files per top-level directory",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, e := source(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, report.Heatmap(s.Tree, s.Meta.Created))
+	},
+}
+
+var duplicatesCmd = &cobra.Command{
+	Use:   "duplicates [path]",
+	Short: "Report files of identical content, separating reflinked clones from true duplicates",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s, e := source(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, report.Dedupe(s.Tree))
+	},
+}
+
+var chunksCmd = &cobra.Command{
+	Use:   "chunks [path]",
+	Short: "Estimate how much a tree would deduplicate with content-defined (FastCDC) chunking",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var sizes [3]int64
+		for i, flag := range []string{"min-chunk", "average-chunk", "max-chunk"} {
+			value, _ := cmd.Flags().GetString(flag)
+
+			size, e := render.ParseBytes(value)
+			if e != nil {
+				return fmt.Errorf("invalid --%s: %w", flag, e)
+			}
+
+			sizes[i] = size
+		}
+
+		c, e := cdc.New(int(sizes[0]), int(sizes[1]), int(sizes[2]))
+		if e != nil {
+			return e
+		}
+
+		t, e := walk(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, report.Chunk(t, c))
+	},
+}
+
+var compressionCmd = &cobra.Command{
+	Use:   "compression [path]",
+	Short: "Estimate a tree's zstd-compressed size per level, from a sample of each file's blocks",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, _ := cmd.Flags().GetString("group-by")
+		g, e := report.Parse(value)
+		if e != nil {
+			return e
+		}
+
+		value, _ = cmd.Flags().GetString("block-size")
+		block, e := render.ParseBytes(value)
+		if e != nil {
+			return fmt.Errorf("invalid --block-size: %w", e)
+		}
+
+		blocks, _ := cmd.Flags().GetInt("blocks")
+		levels, _ := cmd.Flags().GetStringSlice("level")
+
+		t, e := walk(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		c, e := report.Compress(t, g, levels, block, blocks)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, c)
+	},
+}
+
+var estimateCmd = &cobra.Command{
+	Use:   "estimate [path]",
+	Short: "Estimate a tree's totals from a sampled fraction of its directories",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fraction, _ := cmd.Flags().GetFloat64("fraction")
+		if fraction <= 0 || fraction > 1 {
+			return fmt.Errorf("invalid sampling fraction: %v (expected 0 < fraction <= 1)", fraction)
+		}
+
+		seed, _ := cmd.Flags().GetInt64("seed")
+		if !(cmd.Flags().Changed("seed")) {
+			seed = time.Now().UnixNano()
+		}
+
+		s, e := tree.Sampled(target(args), fraction, seed, options()...)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, report.Estimated(target(args), s))
+	},
+}
+
+// source returns the snapshot a report is computed from: the --snapshot document, if given, or a
+// snapshot of a walk of the path argument.
+func source(cmd *cobra.Command, args []string) (*snapshot.Snapshot, error) {
+	if path, _ := cmd.Flags().GetString("snapshot"); path != "" {
+		return snapshot.Read(path)
+	}
+
+	t, e := walk(cmd, args)
+	if e != nil {
+		return nil, e
+	}
+
+	return snapshot.New(t, effective()), nil
+}
+
+func init() {
+	for _, command := range []*cobra.Command{summaryCmd, duCmd} {
+		command.Flags().StringVar(&grouping, "group-by", "none", "group rows by: none, extension, directory, tag, owner")
+	}
+
+	for _, command := range []*cobra.Command{summaryCmd, duCmd, ownersCmd, activityCmd, duplicatesCmd} {
+		command.Flags().String("snapshot", "", "compute the report from a snapshot document rather than walking a path")
+		rootCmd.AddCommand(command)
+	}
+
+	estimateCmd.Flags().Float64("fraction", 0.1, "fraction of directories whose files are sized")
+	estimateCmd.Flags().Int64("seed", 0, "seed of the directory sampling, for reproducible estimates (default random)")
+	rootCmd.AddCommand(estimateCmd)
+
+	chunksCmd.Flags().String("min-chunk", "2KiB", "smallest chunk size")
+	chunksCmd.Flags().String("average-chunk", "8KiB", "average chunk size; a power of two")
+	chunksCmd.Flags().String("max-chunk", "64KiB", "largest chunk size")
+	rootCmd.AddCommand(chunksCmd)
+
+	compressionCmd.Flags().String("group-by", "extension", "group rows by: none, extension, directory, tag, owner")
+	compressionCmd.Flags().StringSlice("level", report.Levels(), "zstd compression level(s) to estimate: fastest, default, better, best")
+	compressionCmd.Flags().String("block-size", "64KiB", "size of each sampled block")
+	compressionCmd.Flags().Int("blocks", 16, "blocks sampled per file, evenly spaced; smaller files are compressed whole")
+	rootCmd.AddCommand(compressionCmd)
+
+	duCmd.Flags().IntVar(&depth, "depth", 1, "directory depth to report (0 = unlimited)")
+}
@@ -1,26 +1,603 @@
 package root
 
 import (
+	"cli/internal/config"
+	"cli/internal/exception"
+	"cli/internal/filter"
+	"cli/internal/fs/checksum"
+	"cli/internal/fs/concurrency"
+	"cli/internal/fs/remove"
+	"cli/internal/fs/tree"
+	"cli/internal/fs/workspace"
+	"cli/internal/i18n"
+	"cli/internal/progress"
+	"cli/internal/render"
+	"cli/internal/snapshot"
+	"cli/internal/usage"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"runtime"
+	"sync/atomic"
+	"syscall"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	configuration string
+	profile       string
+	lang          string
+	plain         bool
+	omit          bool
+	metadata      bool
+	readonly      bool
+	forensic      bool
+	neverFollow   bool
+	stats         string
+	contexts      tree.Contexts
+	where         *filter.Filter
+	errorFormat   string
+
+	// meter measures the command's resource usage, reported with --stats.
+	meter *usage.Meter
+
+	// hashing and copying are the concurrency controllers of the walks' hashes and copies' writes.
+	hashing *concurrency.Controller
+	copying *concurrency.Controller
+
+	// tracking tracks the progress of walks even when none is drawn; current is that of the latest walk.
+	tracking bool
+	current  atomic.Pointer[progress.Progress]
+
+	// settings is the effective profile: the selected configuration profile overridden by flags.
+	settings config.Profile
+)
+
 var rootCmd = &cobra.Command{
-	Use:   "stringer",
-	Short: "stringer - a simple CLI to transform and inspect strings",
-	Long: `stringer is a super fancy CLI (kidding)
-   
-One can use stringer to modify or inspect strings straight from the terminal`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Use:   "cli [path]",
+	Short: "cli - a simple CLI to walk and inspect file-system trees",
+	Long: `cli walks a directory and reports its file-system tree
 
+One can use cli to inspect, hash, and copy file-system trees straight from the terminal`,
+	Args:          cobra.MaximumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return resolve(cmd)
 	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, e := walk(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, t)
+	},
+}
+
+func init() {
+	flags := rootCmd.PersistentFlags()
+
+	flags.StringVar(&configuration, "config", config.Filename, "configuration file (env CLI_CONFIG)")
+	flags.StringVar(&profile, "profile", "", "named configuration profile (env CLI_PROFILE)")
+	flags.StringVar(&lang, "lang", "", "language of human-facing output: en, es, de (env LANG)")
+	flags.Bool("omit-empty", true, "omit empty node attributes from json and yaml output")
+	flags.StringVar(&errorFormat, "error-format", "text", "error output format: text, json")
+	flags.StringVar(&stats, "stats", "", "report wall and CPU time, peak RSS, and throughput on stderr: text, json")
+	flags.Lookup("stats").NoOptDefVal = "text"
+	flags.String("progress", "auto", "progress reporting on stderr: auto, none, bar, lines, json")
+	flags.Bool("prescan", false, "pre-scan file and byte totals, so progress shows a percentage and ETA")
+	flags.BoolVar(&plain, "plain", false, "plain output: no box-drawing characters, colors, or animations")
+	flags.StringSlice("exclude", nil, "glob pattern(s) of paths to exclude")
+	flags.String("where", "", "walk only files matching a filter, e.g. 'tag=runtime,size<10MB,owner=app,glob=*.so'")
+	flags.Bool("standard-exclusions", false, "exclude CACHEDIR.TAG-tagged directories and nodump-flagged paths")
+	flags.Bool("assert-readonly", false, "assert the walk performs no writes, verifying files unmodified and refusing copies")
+	flags.Bool("never-follow", false, "never traverse or delete through symbolic links while deleting: prunes, replaced destinations, and rolled-back copies")
+	flags.Bool("forensic", false, "capture access, modification, change, and birth times at nanosecond precision")
+	flags.Bool("metadata", false, "capture file flags and extended attribute names, and preserve them on copy")
+	flags.String("contexts", "none", "SELinux security contexts: none, preserve (restore on copy), default (apply the destination's label)")
+	flags.String("hasher", "", "file checksum algorithm")
+	flags.StringSlice("digests", nil, "additional file digest algorithm(s), calculated in the same read pass")
+	flags.StringSlice("format", nil, "output format(s): json, yaml, text, jsonl-flat, tree-json, json-indexed")
+	flags.Int("max-depth", 0, "maximum directory depth to descend (0 = unlimited)")
+	flags.Int("max-files", 0, "maximum number of files to walk (0 = unlimited)")
+	flags.Int("max-entries", 0, "don't descend into directories of more entries than this (0 = unlimited)")
+	flags.StringSlice("skip-marker", nil, "don't descend into directories holding a file of this name, e.g. .skipinventory")
+	flags.Int64("sample-threshold", 0, "only fingerprint head and tail of files larger than this many bytes (0 = full digests)")
+	flags.Int64("sample-size", 4<<20, "bytes hashed from each of a sampled file's head and tail")
+	flags.Int64("parallel-threshold", 0, "tree-hash files larger than this many bytes, hashing chunks in parallel (0 = sequential digests)")
+	flags.Int64("chunk-size", 4<<20, "bytes per chunk of a parallel tree-hashed file")
+	flags.Int("workers", 0, "files hashed and copied at once (0 = adapt to the storage's latency and throughput)")
+}
+
+// locate applies environment overrides to the configuration file path and profile name.
+func locate(cmd *cobra.Command) {
+	flags := cmd.Flags()
+
+	if value, valid := os.LookupEnv("CLI_CONFIG"); valid && !(flags.Changed("config")) {
+		configuration = value
+	}
+
+	if value, valid := os.LookupEnv("CLI_PROFILE"); valid && !(flags.Changed("profile")) {
+		profile = value
+	}
+
+	if flags.Changed("lang") {
+		i18n.Set(i18n.Parse(lang))
+	}
+}
+
+// resolve loads the configuration file, selects the profile, and applies flag overrides to settings.
+func resolve(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+
+	locate(cmd)
+
+	c, e := config.Load(configuration)
+	if e != nil {
+		return e
+	}
+
+	settings, e = c.Select(profile)
+	if e != nil {
+		return e
+	}
+
+	if flags.Changed("exclude") {
+		settings.Excludes, _ = flags.GetStringSlice("exclude")
+	}
+
+	if settings.Excludes, e = settings.Patterns(); e != nil {
+		return e
+	}
+
+	if flags.Changed("standard-exclusions") {
+		settings.StandardExclusions, _ = flags.GetBool("standard-exclusions")
+	}
+
+	if flags.Changed("hasher") {
+		settings.Hasher, _ = flags.GetString("hasher")
+	}
+
+	if flags.Changed("digests") {
+		settings.Digests, _ = flags.GetStringSlice("digests")
+	}
+
+	for _, digest := range settings.Digests {
+		if !(checksum.Algorithm(digest).Valid()) {
+			return exception.New(exception.EALGORITHM, "resolve", "", fmt.Errorf("%q", digest))
+		}
+	}
+
+	if flags.Changed("format") {
+		settings.Formats, _ = flags.GetStringSlice("format")
+	}
+
+	if flags.Changed("max-depth") {
+		settings.Limits.MaxDepth, _ = flags.GetInt("max-depth")
+	}
+
+	if flags.Changed("max-files") {
+		settings.Limits.MaxFiles, _ = flags.GetInt("max-files")
+	}
+
+	if flags.Changed("max-entries") {
+		settings.Limits.MaxEntries, _ = flags.GetInt("max-entries")
+	}
+
+	if flags.Changed("skip-marker") {
+		settings.SkipMarkers, _ = flags.GetStringSlice("skip-marker")
+	}
+
+	if flags.Changed("sample-threshold") {
+		settings.Sampling.Threshold, _ = flags.GetInt64("sample-threshold")
+	}
+
+	if flags.Changed("sample-size") || settings.Sampling.Size == 0 {
+		settings.Sampling.Size, _ = flags.GetInt64("sample-size")
+	}
+
+	if flags.Changed("parallel-threshold") {
+		settings.Parallel.Threshold, _ = flags.GetInt64("parallel-threshold")
+	}
+
+	if flags.Changed("chunk-size") || settings.Parallel.Chunk == 0 {
+		settings.Parallel.Chunk, _ = flags.GetInt64("chunk-size")
+	}
+
+	if settings.Parallel.Threshold > 0 && settings.Parallel.Chunk <= 0 {
+		return fmt.Errorf("invalid chunk size: %d", settings.Parallel.Chunk)
+	}
+
+	if flags.Changed("workers") {
+		settings.Parallel.Workers, _ = flags.GetInt("workers")
+	}
+
+	if settings.Parallel.Workers < 0 {
+		return fmt.Errorf("invalid worker count: %d", settings.Parallel.Workers)
+	}
+
+	hashing, copying = controller("hash"), controller("copy")
+
+	omit, _ = flags.GetBool("omit-empty")
+	metadata, _ = flags.GetBool("metadata")
+	readonly, _ = flags.GetBool("assert-readonly")
+	forensic, _ = flags.GetBool("forensic")
+	neverFollow, _ = flags.GetBool("never-follow")
+	workspace.NeverFollow = neverFollow
+
+	where = nil
+	if expression, _ := flags.GetString("where"); expression != "" {
+		if where, e = filter.Parse(expression); e != nil {
+			return e
+		}
+	}
+
+	if stats != "" && stats != "text" && stats != "json" {
+		return fmt.Errorf("unsupported stats format: %s", stats)
+	}
+
+	if policy, _ := flags.GetString("contexts"); tree.Contexts(policy).Valid() {
+		contexts = tree.Contexts(policy)
+	} else {
+		return fmt.Errorf("unsupported contexts policy: %s", policy)
+	}
+
+	if len(settings.Formats) == 0 {
+		settings.Formats = []string{"json"}
+	}
+
+	if settings.Hasher == "" {
+		settings.Hasher = string(checksum.AlgorithmSHA256)
+	}
+
+	if !(checksum.Algorithm(settings.Hasher).Valid()) {
+		return exception.New(exception.EALGORITHM, "resolve", "", fmt.Errorf("%q", settings.Hasher))
+	}
+
+	return nil
+}
+
+// digests returns the additional digest algorithms of the effective settings.
+func digests() []checksum.Algorithm {
+	algorithms := make([]checksum.Algorithm, 0, len(settings.Digests))
+	for _, digest := range settings.Digests {
+		algorithms = append(algorithms, checksum.Algorithm(digest))
+	}
+
+	return algorithms
+}
+
+// options returns the tree walk options of the effective settings.
+func options() []tree.Option {
+	o := []tree.Option{
+		tree.WithExcludes(settings.Excludes...),
+		tree.WithAlgorithm(checksum.Algorithm(settings.Hasher)),
+		tree.WithDigests(digests()...),
+		tree.WithMaxDepth(settings.Limits.MaxDepth),
+		tree.WithMaxFiles(settings.Limits.MaxFiles),
+		tree.WithSampling(settings.Sampling.Threshold, settings.Sampling.Size),
+		tree.WithParallelHashing(settings.Parallel.Threshold, settings.Parallel.Chunk),
+		tree.WithTags(settings.Tags),
+		tree.WithEncoding(tree.Encoding{OmitEmpty: omit}),
+		tree.WithConcurrency(hashing),
+		tree.WithCopyConcurrency(copying),
+	}
+
+	if settings.StandardExclusions {
+		o = append(o, tree.WithStandardExclusions())
+	}
+
+	for _, marker := range settings.SkipMarkers {
+		o = append(o, tree.WithSkipDirIf(tree.SkipMarker(marker)))
+	}
+
+	if settings.Limits.MaxEntries > 0 {
+		o = append(o, tree.WithSkipDirIf(tree.SkipLarger(settings.Limits.MaxEntries)))
+	}
+
+	if where != nil {
+		o = append(o, tree.WithFilter(where.Match))
+	}
+
+	if metadata {
+		o = append(o, tree.WithMetadata())
+	}
+
+	if readonly {
+		o = append(o, tree.WithReadOnly())
+	}
+
+	if forensic {
+		o = append(o, tree.WithForensic())
+	}
+
+	if neverFollow {
+		o = append(o, tree.WithNeverFollow())
+	}
+
+	if contexts != tree.ContextsNone {
+		o = append(o, tree.WithContexts(contexts))
+	}
+
+	return o
+}
+
+// recorded represents the effective settings that affect what a walk records, digested into the meta of
+// snapshots so that only those walked alike compare; output-only settings, e.g. formats and workers, are
+// left out.
+type recorded struct {
+	Excludes           []string            `json:"excludes"`
+	StandardExclusions bool                `json:"standard-exclusions"`
+	SkipMarkers        []string            `json:"skip-markers"`
+	Hasher             string              `json:"hasher"`
+	Digests            []string            `json:"digests"`
+	Limits             config.Limits       `json:"limits"`
+	Sampling           config.Sampling     `json:"sampling"`
+	Threshold          int64               `json:"parallel-threshold"`
+	Chunk              int64               `json:"chunk-size"`
+	Tags               map[string][]string `json:"tags"`
+	Where              string              `json:"where"`
+	Metadata           bool                `json:"metadata"`
+	Forensic           bool                `json:"forensic"`
+	Contexts           tree.Contexts       `json:"contexts"`
+	OmitEmpty          bool                `json:"omit-empty"`
+	ReadOnly           bool                `json:"read-only"`
+}
+
+// effective returns the recorded settings of the command.
+func effective() recorded {
+	r := recorded{
+		Excludes:           settings.Excludes,
+		StandardExclusions: settings.StandardExclusions,
+		SkipMarkers:        settings.SkipMarkers,
+		Hasher:             settings.Hasher,
+		Digests:            settings.Digests,
+		Limits:             settings.Limits,
+		Sampling:           settings.Sampling,
+		Threshold:          settings.Parallel.Threshold,
+		Chunk:              settings.Parallel.Chunk,
+		Tags:               settings.Tags,
+		Metadata:           metadata,
+		Forensic:           forensic,
+		Contexts:           contexts,
+		OmitEmpty:          omit,
+		ReadOnly:           readonly,
+	}
+
+	// chunk and sample sizes are moot unless their thresholds enable them
+	if r.Threshold == 0 {
+		r.Chunk = 0
+	}
+
+	if r.Sampling.Threshold == 0 {
+		r.Sampling.Size = 0
+	}
+
+	if where != nil {
+		r.Where = where.Expression
+	}
+
+	return r
+}
+
+// target returns the path argument of a command, defaulting to the working directory.
+func target(args []string) string {
+	if len(args) > 0 {
+		return args[0]
+	}
+
+	return "."
+}
+
+// walk walks the command's path argument with the effective settings, along with any command-specific
+// options. Non-fatal walk errors are recorded on the tree's nodes rather than failing the command.
+func walk(cmd *cobra.Command, args []string, extra ...tree.Option) (*tree.Node, error) {
+	p, e := reporter(cmd)
+	if e != nil {
+		return nil, e
+	}
+
+	if p == nil && tracking {
+		p = progress.New(io.Discard, progress.ModeLines)
+	}
+
+	current.Store(p)
+
+	var totals *progress.Totals
+	if prescan, _ := cmd.Flags().GetBool("prescan"); prescan && p != nil {
+		if totals, e = tree.Scan(target(args), options()...); e != nil {
+			return nil, e
+		}
+	}
+
+	p.Start("hashing", totals)
+
+	t, e := tree.Walk(target(args), append(append(options(), extra...), tree.WithProgress(p))...)
+	if e != nil && !(errors.Is(e, tree.ExceptionWalkPartial)) {
+		return nil, e
+	}
+
+	p.Finish()
+
+	meter.Add(t.CountFiles(), t.CountBytes())
+
+	return t, nil
+}
+
+// reporter returns the progress reporter of the --progress flag, drawn to stderr.
+func reporter(cmd *cobra.Command) (*progress.Progress, error) {
+	mode, _ := cmd.Flags().GetString("progress")
+
+	switch progress.Mode(mode) {
+	case "auto":
+		if !(render.Terminal(os.Stderr)) {
+			return nil, nil
+		} else if s := render.Detect(os.Stderr, plain); !(s.Animated(os.Stderr)) {
+			return progress.New(os.Stderr, progress.ModeLines), nil
+		}
+
+		return progress.New(os.Stderr, progress.ModeBar), nil
+	case progress.ModeBar:
+		if plain {
+			return progress.New(os.Stderr, progress.ModeLines), nil
+		}
+
+		return progress.New(os.Stderr, progress.ModeBar), nil
+	case progress.ModeLines, progress.ModeJSON, progress.ModeNone:
+		return progress.New(os.Stderr, progress.Mode(mode)), nil
+	}
+
+	return nil, fmt.Errorf("unsupported progress mode: %s", mode)
+}
+
+// document represents an output document serializable in each of the supported formats.
+type document interface {
+	JSON() string
+	YAML() string
+}
+
+// texter represents a document with its own human-facing text rendering.
+type texter interface {
+	Text(w io.Writer, style render.Style)
+}
+
+// style returns the render.Style of the command's standard output.
+func style() render.Style {
+	return render.Detect(os.Stdout, plain)
+}
+
+// write outputs d in each of the effective settings' formats.
+func write(cmd *cobra.Command, d document) error {
+	for _, format := range settings.Formats {
+		switch format {
+		case "json":
+			fmt.Fprintln(cmd.OutOrStdout(), d.JSON())
+		case "yaml":
+			fmt.Fprintln(cmd.OutOrStdout(), d.YAML())
+		case "jsonl-flat", "tree-json", "json-indexed":
+			var t *tree.Node
+			switch v := d.(type) {
+			case *tree.Node:
+				t = v
+			case *snapshot.Snapshot:
+				t = v.Tree
+			default:
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+
+			encode := t.JSONL
+			switch format {
+			case "tree-json":
+				encode = t.TreeJSON
+			case "json-indexed":
+				encode = func(w io.Writer) error {
+					_, e := fmt.Fprintln(w, t.Indexed())
+					return e
+				}
+			}
+
+			if e := encode(cmd.OutOrStdout()); e != nil {
+				return e
+			}
+		case "text":
+			switch v := d.(type) {
+			case *tree.Node:
+				render.Tree(cmd.OutOrStdout(), v, style())
+			case *snapshot.Snapshot:
+				render.Tree(cmd.OutOrStdout(), v.Tree, style())
+			case texter:
+				v.Text(cmd.OutOrStdout(), style())
+			default:
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+		default:
+			return fmt.Errorf("unsupported format: %s", format)
+		}
+	}
+
+	return nil
+}
+
+// explain returns a human-facing explanation of well-known errors, if any.
+func explain(e error) (string, bool) {
+	switch {
+	case errors.Is(e, config.ExceptionUnknownProfile):
+		return i18n.T(i18n.ExplainUnknownProfile), true
+	case errors.Is(e, checksum.ExceptionInvalidAlgorithm):
+		return i18n.T(i18n.ExplainInvalidHasher), true
+	case errors.Is(e, checksum.ExceptionMissingKey):
+		return i18n.T(i18n.ExplainMissingKey), true
+	case errors.Is(e, tree.ExceptionImmutable):
+		return i18n.T(i18n.ExplainImmutable), true
+	case errors.Is(e, remove.ExceptionSymlink):
+		return i18n.T(i18n.ExplainSymlink), true
+	}
+
+	return "", false
+}
+
+// controller returns the concurrency controller of operation, tracked by the meter: fixed at the profile's
+// parallel.workers, or adapted between one and four workers per CPU.
+func controller(operation string) *concurrency.Controller {
+	c := concurrency.New(1, 4*runtime.GOMAXPROCS(0))
+	if settings.Parallel.Workers > 0 {
+		c = concurrency.Fixed(settings.Parallel.Workers)
+	}
+
+	meter.Track(operation, c)
+
+	return c
+}
+
+// statistics writes the --stats resource usage summary of cmd to stderr.
+func statistics(cmd *cobra.Command) {
+	if cmd == nil {
+		return
+	}
+
+	u := meter.Usage(cmd.CommandPath())
+
+	switch stats {
+	case "text":
+		u.Text(os.Stderr)
+	case "json":
+		fmt.Fprintln(os.Stderr, u.JSON())
+	}
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Whoops. There was an error while executing your CLI '%s'", err)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		workspace.Release()
+		os.Exit(130)
+	}()
+
+	defer workspace.Release()
+
+	meter = usage.Start()
+
+	cmd, err := rootCmd.ExecuteC()
+	statistics(cmd)
+
+	if err != nil {
+		workspace.Release()
+
+		if errorFormat == "json" {
+			fmt.Fprintln(os.Stderr, exception.JSON(err))
+			os.Exit(1)
+		}
+
+		fmt.Fprintln(os.Stderr, i18n.T(i18n.ErrorExecution, err))
+		if explanation, valid := explain(err); valid {
+			fmt.Fprintln(os.Stderr, explanation)
+		}
+
 		os.Exit(1)
 	}
 }
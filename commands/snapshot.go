@@ -0,0 +1,107 @@
+package root
+
+import (
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"cli/internal/report"
+	"cli/internal/snapshot"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot [path]",
+	Short: "Write a snapshot document of a file-system tree",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, e := walk(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		return write(cmd, snapshot.New(t, effective()))
+	},
+}
+
+var snapshotCompareCmd = &cobra.Command{
+	Use:   "compare <snapshot> <snapshot>",
+	Short: "Confirm two snapshots were produced with identical settings",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, e := snapshot.Read(args[0])
+		if e != nil {
+			return e
+		}
+
+		b, e := snapshot.Read(args[1])
+		if e != nil {
+			return e
+		}
+
+		if e := a.Comparable(b); e != nil {
+			return e
+		}
+
+		fmt.Fprintln(cmd.OutOrStdout(), i18n.T(i18n.SnapshotIdentical, a.Meta.Configuration))
+
+		return nil
+	},
+}
+
+var snapshotDiffCmd = &cobra.Command{
+	Use:   "diff <snapshot> <snapshot>",
+	Short: "Render two snapshots side by side, highlighting the entries that changed",
+	Long: `Render two snapshots side by side, highlighting added, removed, and modified entries;
+directories holding no changes are collapsed unless --all is given.
+
+With --interactive, the rendering is browsed in the terminal, expanding and collapsing
+directories and jumping between changes.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, e := snapshot.Read(args[0])
+		if e != nil {
+			return e
+		}
+
+		b, e := snapshot.Read(args[1])
+		if e != nil {
+			return e
+		}
+
+		value, _ := cmd.Flags().GetString("group-by")
+		grouping, e := report.Parse(value)
+		if e != nil {
+			return e
+		}
+
+		d := snapshot.Diff(a, b)
+		d.Width = render.Width(os.Stdout)
+		d.Grouping = grouping
+
+		if all, _ := cmd.Flags().GetBool("all"); all {
+			d.Expand(true)
+		}
+
+		if interactive, _ := cmd.Flags().GetBool("interactive"); interactive {
+			if !(render.Terminal(os.Stdin)) || !(render.Terminal(os.Stdout)) {
+				return errors.New("--interactive requires a terminal")
+			}
+
+			return d.Browse(os.Stdin, os.Stdout, style())
+		}
+
+		return write(cmd, d)
+	},
+}
+
+func init() {
+	snapshotDiffCmd.Flags().Bool("all", false, "expand every directory, not only those holding changes")
+	snapshotDiffCmd.Flags().BoolP("interactive", "i", false, "browse the rendering interactively in the terminal")
+	snapshotDiffCmd.Flags().String("group-by", "none", "total changed files by: none, extension, directory, tag, owner")
+
+	snapshotCmd.AddCommand(snapshotCompareCmd, snapshotDiffCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
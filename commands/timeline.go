@@ -0,0 +1,27 @@
+package root
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var timelineCmd = &cobra.Command{
+	Use:   "timeline [path]",
+	Short: "Export a tree as a mactime body file, for timeline tooling such as mactime or Plaso",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		forensic = true
+
+		s, e := source(cmd, args)
+		if e != nil {
+			return e
+		}
+
+		return s.Tree.Body(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	timelineCmd.Flags().String("snapshot", "", "export a snapshot document rather than walking a path")
+
+	rootCmd.AddCommand(timelineCmd)
+}
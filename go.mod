@@ -2,10 +2,16 @@ module cli
 
 go 1.21.0
 
-require gopkg.in/yaml.v3 v3.0.1
+require (
+	github.com/klauspost/compress v1.17.11
+	github.com/spf13/cobra v1.7.0
+	github.com/zeebo/blake3 v0.2.4
+	golang.org/x/sys v0.25.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
-	github.com/spf13/cobra v1.7.0 // indirect
+	github.com/klauspost/cpuid/v2 v2.0.12 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 )
@@ -0,0 +1,3 @@
+// Package cdc represents content-defined chunking (FastCDC), splitting data at content-dependent boundaries
+// so that identical content deduplicates across files regardless of its offset.
+package cdc
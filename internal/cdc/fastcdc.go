@@ -0,0 +1,114 @@
+package cdc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// gear is the table of random 64-bit values of the gear hash, generated deterministically with splitmix64
+// so chunk boundaries are reproducible across runs and builds.
+var gear = func() (table [256]uint64) {
+	state := uint64(0x9E3779B97F4A7C15)
+	for i := range table {
+		state += 0x9E3779B97F4A7C15
+		z := state
+		z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+		z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+		table[i] = z ^ (z >> 31)
+	}
+
+	return
+}()
+
+// Chunker represents FastCDC chunking with normalized chunk sizes between Min and Max, averaging about Average.
+type Chunker struct {
+	Min, Average, Max int
+
+	small, large uint64
+}
+
+// New returns a Chunker of the given sizes; average must be a power of two, and min < average < max.
+func New(min, average, max int) (*Chunker, error) {
+	if min <= 0 || !(min < average && average < max) {
+		return nil, fmt.Errorf("invalid chunk sizes: expected 0 < min (%d) < average (%d) < max (%d)", min, average, max)
+	} else if average&(average-1) != 0 {
+		return nil, fmt.Errorf("invalid average chunk size %d: expected a power of two", average)
+	}
+
+	// normalization level 2: a harder mask before the average size, and an easier one after it.
+	// The masks test the fingerprint's high bits, which depend on the most recent 64 bytes.
+	n := bits.TrailingZeros(uint(average))
+
+	return &Chunker{Min: min, Average: average, Max: max, small: mask(n + 2), large: mask(n - 2)}, nil
+}
+
+func mask(n int) uint64 {
+	if n <= 0 {
+		return 0
+	}
+
+	return ^uint64(0) << (64 - n)
+}
+
+// cut returns the length of the first chunk of data.
+func (c *Chunker) cut(data []byte) int {
+	n := len(data)
+	if n <= c.Min {
+		return n
+	} else if n > c.Max {
+		n = c.Max
+	}
+
+	normal := c.Average
+	if n < normal {
+		normal = n
+	}
+
+	var fingerprint uint64
+	i := c.Min
+	for ; i < normal; i++ {
+		fingerprint = (fingerprint << 1) + gear[data[i]]
+		if fingerprint&c.small == 0 {
+			return i + 1
+		}
+	}
+
+	for ; i < n; i++ {
+		fingerprint = (fingerprint << 1) + gear[data[i]]
+		if fingerprint&c.large == 0 {
+			return i + 1
+		}
+	}
+
+	return n
+}
+
+// Split reads r to its end, calling fn with every chunk; the chunk is only valid during the call.
+func (c *Chunker) Split(r io.Reader, fn func(chunk []byte)) error {
+	buffer := make([]byte, c.Max)
+	filled := 0
+	eof := false
+
+	for {
+		if !(eof) && filled < len(buffer) {
+			n, e := io.ReadFull(r, buffer[filled:])
+			filled += n
+			if errors.Is(e, io.EOF) || errors.Is(e, io.ErrUnexpectedEOF) {
+				eof = true
+			} else if e != nil {
+				return e
+			}
+		}
+
+		if filled == 0 {
+			return nil
+		}
+
+		length := c.cut(buffer[:filled])
+		fn(buffer[:length])
+
+		filled = copy(buffer, buffer[length:filled])
+	}
+}
@@ -0,0 +1,192 @@
+package checklist
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/render"
+	"strings"
+)
+
+// Item represents a file or directory of a Checklist.
+type Item struct {
+	Node     *tree.Node
+	Children []*Item
+
+	parent   *Item
+	depth    int
+	expanded bool
+
+	// files and checked are the totals of the Item's subtree; a file's are its own.
+	files   int
+	checked int
+}
+
+// Checklist represents a tree whose files are toggled to select them; a directory's files are toggled
+// together. Nothing is selected initially.
+type Checklist struct {
+	root *Item
+}
+
+// New returns the Checklist of the files and directories of t, with its root expanded.
+func New(t *tree.Node) *Checklist {
+	c := &Checklist{root: item(t, nil)}
+	c.root.expanded = true
+
+	return c
+}
+
+// item returns the Item of n and its subtree, beneath parent.
+func item(n *tree.Node, parent *Item) *Item {
+	i := &Item{Node: n, parent: parent}
+	if parent != nil {
+		i.depth = parent.depth + 1
+	}
+
+	if n.Type == tree.File {
+		i.files = 1
+		return i
+	}
+
+	for _, child := range n.Children() {
+		if child.Type != tree.File && child.Type != tree.Directory {
+			continue
+		}
+
+		c := item(child, i)
+		i.Children = append(i.Children, c)
+		i.files += c.files
+	}
+
+	return i
+}
+
+// State returns the Item's check state: "x" when all of its files are selected, "-" when some are,
+// and " " when none are.
+func (i *Item) State() string {
+	switch {
+	case i.files > 0 && i.checked == i.files:
+		return "x"
+	case i.checked > 0:
+		return "-"
+	}
+
+	return " "
+}
+
+// Toggle selects every file of the Item's subtree, unless all already are; then it deselects them.
+func (i *Item) Toggle() {
+	i.set(i.checked < i.files)
+}
+
+// set selects, or deselects, every file of the Item's subtree, updating the totals of its ancestors.
+func (i *Item) set(checked bool) {
+	before := i.checked
+
+	var mark func(item *Item)
+	mark = func(item *Item) {
+		if item.Node.Type == tree.File {
+			if checked {
+				item.checked = 1
+			} else {
+				item.checked = 0
+			}
+
+			return
+		}
+
+		item.checked = 0
+		for _, child := range item.Children {
+			mark(child)
+			item.checked += child.checked
+		}
+	}
+
+	mark(i)
+
+	for parent := i.parent; parent != nil; parent = parent.parent {
+		parent.checked += i.checked - before
+	}
+}
+
+// All selects, or with false deselects, every file.
+func (c *Checklist) All(checked bool) {
+	c.root.set(checked)
+}
+
+// Selected returns the paths of the selected files.
+func (c *Checklist) Selected() map[string]bool {
+	selected := map[string]bool{}
+
+	var collect func(i *Item)
+	collect = func(i *Item) {
+		if i.checked == 0 {
+			return
+		} else if i.Node.Type == tree.File {
+			selected[i.Node.Path] = true
+		}
+
+		for _, child := range i.Children {
+			collect(child)
+		}
+	}
+
+	collect(c.root)
+
+	return selected
+}
+
+// Totals returns the counts of the selected and all files, and the bytes of the selected files.
+func (c *Checklist) Totals() (checked, files int, bytes int64) {
+	for path := range c.Selected() {
+		bytes += c.root.Node.Map()[path].Size
+	}
+
+	return c.root.checked, c.root.files, bytes
+}
+
+// visible returns the Items shown: the root, and the children of expanded directories, in order.
+func (c *Checklist) visible() []*Item {
+	var items []*Item
+
+	var collect func(i *Item)
+	collect = func(i *Item) {
+		items = append(items, i)
+		if i.expanded {
+			for _, child := range i.Children {
+				collect(child)
+			}
+		}
+	}
+
+	collect(c.root)
+
+	return items
+}
+
+// row returns the Item's line of the checklist, fit to width.
+func (i *Item) row(width int, style render.Style) string {
+	glyph := "  "
+	if len(i.Children) > 0 {
+		glyph = "▸ "
+		if i.expanded {
+			glyph = "▾ "
+		}
+
+		if style.Plain {
+			glyph = strings.NewReplacer("▸", "+", "▾", "-").Replace(glyph)
+		}
+	}
+
+	name := tree.Escape(i.Node.Name)
+	detail := render.Bytes(i.Node.Size)
+	if i.Node.Type == tree.Directory {
+		name += "/"
+		detail = render.Bytes(i.Node.CountBytes())
+	}
+
+	line := strings.Repeat("  ", i.depth) + "[" + i.State() + "] " + glyph + name + "  " + detail
+	if i.State() == "x" {
+		return style.Added(style.Fit(line, width))
+	}
+
+	return style.Fit(line, width)
+}
@@ -0,0 +1,2 @@
+// Package checklist represents the interactive selection of a tree's files, toggled in a terminal.
+package checklist
@@ -0,0 +1,112 @@
+package checklist
+
+import (
+	"bytes"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"fmt"
+	"os"
+)
+
+// Run renders the Checklist on terminal out, reading keys from terminal in, so files and directories can
+// be toggled interactively, until enter confirms the selection, returning true, or q, escape, or ctrl-c
+// cancels it, returning false.
+func (c *Checklist) Run(in, out *os.File, style render.Style) (bool, error) {
+	restore, e := render.Raw(in)
+	if e != nil {
+		return false, e
+	}
+
+	defer restore()
+
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	cursor, top := 0, 0
+	key := make([]byte, 16)
+	for {
+		width, height, e := render.Size(out)
+		if e != nil || width <= 0 || height <= 0 {
+			width, height = 120, 40
+		}
+
+		items := c.visible()
+		page := max(height-2, 1)
+
+		cursor = min(max(cursor, 0), len(items)-1)
+		if cursor < top {
+			top = cursor
+		} else if cursor >= top+page {
+			top = cursor - page + 1
+		}
+
+		var screen bytes.Buffer
+		screen.WriteString("\x1b[H\x1b[2J")
+		for i := top; i < min(top+page, len(items)); i++ {
+			line := items[i].row(width-2, style)
+			if i == cursor {
+				screen.WriteString(style.Selected(">") + " " + line + "\n")
+			} else {
+				screen.WriteString("  " + line + "\n")
+			}
+		}
+
+		checked, files, size := c.Totals()
+		status := i18n.T(i18n.ChecklistStatus, checked, files, render.Bytes(size)) + "  " + i18n.T(i18n.ChecklistHelp)
+		screen.WriteString("\x1b[" + fmt.Sprint(height) + ";1H" + style.Fit(status, width-1))
+		if _, e := out.Write(screen.Bytes()); e != nil {
+			return false, e
+		}
+
+		n, e := in.Read(key)
+		if e != nil {
+			return false, e
+		}
+
+		selected := items[cursor]
+		switch string(key[:n]) {
+		case "q", "\x1b", "\x03":
+			return false, nil
+		case "\r", "\n":
+			return true, nil
+		case "j", "\x1b[B", "\x1bOB":
+			cursor++
+		case "k", "\x1b[A", "\x1bOA":
+			cursor--
+		case "\x1b[6~", "\x06":
+			cursor += page
+		case "\x1b[5~", "\x02":
+			cursor -= page
+		case "g", "\x1b[H", "\x1bOH":
+			cursor = 0
+		case "G", "\x1b[F", "\x1bOF":
+			cursor = len(items) - 1
+		case " ":
+			selected.Toggle()
+		case "l", "\x1b[C", "\x1bOC":
+			selected.expanded = len(selected.Children) > 0
+		case "h", "\x1b[D", "\x1bOD":
+			if selected.expanded {
+				selected.expanded = false
+			} else if selected.parent != nil {
+				selected.parent.expanded = false
+				cursor = index(c.visible(), selected.parent)
+			}
+		case "a":
+			c.All(true)
+		case "x":
+			c.All(false)
+		}
+	}
+}
+
+// index returns the position of i among items, or -1.
+func index(items []*Item, i *Item) int {
+	for position, candidate := range items {
+		if candidate == i {
+			return position
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"cli/internal/exception"
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Filename is the default configuration file name, resolved relative to the working directory.
+const Filename = ".cli.yaml"
+
+// Formats are the supported output formats.
+var Formats = []string{"json", "yaml", "text", "jsonl-flat", "tree-json", "json-indexed"}
+
+var (
+	ExceptionInvalidConfiguration = exception.New(exception.ECONFIG, "", "", nil)
+	ExceptionUnknownProfile       = exception.New(exception.EPROFILE, "", "", nil)
+)
+
+// Config represents the configuration file.
+type Config struct {
+	// Profile is the name of the profile used when none is selected.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+
+	// Profiles are named bundles of settings, selected via --profile.
+	Profiles map[string]Profile `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+// Profile represents a named bundle of settings for a common workflow. Relative ExcludesFrom ignore files
+// are resolved against the configuration file's directory.
+type Profile struct {
+	Excludes     []string `json:"excludes,omitempty" yaml:"excludes,omitempty"`
+	ExcludesFrom []string `json:"excludes-from,omitempty" yaml:"excludes-from,omitempty"`
+	Hasher       string   `json:"hasher,omitempty" yaml:"hasher,omitempty"`
+	Digests      []string `json:"digests,omitempty" yaml:"digests,omitempty"`
+	Formats      []string `json:"formats,omitempty" yaml:"formats,omitempty"`
+	Limits       Limits   `json:"limits,omitempty" yaml:"limits,omitempty"`
+	Sampling     Sampling `json:"sampling,omitempty" yaml:"sampling,omitempty"`
+	Parallel     Parallel `json:"parallel,omitempty" yaml:"parallel,omitempty"`
+
+	// SkipMarkers are the names of marker files, e.g. ".skipinventory", whose directories aren't descended into.
+	SkipMarkers []string `json:"skip-markers,omitempty" yaml:"skip-markers,omitempty"`
+
+	// StandardExclusions excludes CACHEDIR.TAG-tagged directories and nodump-flagged paths.
+	StandardExclusions bool `json:"standard-exclusions,omitempty" yaml:"standard-exclusions,omitempty"`
+
+	// Modes are the "PATTERN=MODE" permissions rules of copies, e.g. "*.sh=0755"; the last matching rule wins.
+	Modes []string `json:"modes,omitempty" yaml:"modes,omitempty"`
+
+	// Tags maps tag names to the glob patterns of the paths carrying the tag.
+	Tags map[string][]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// Limits represents the walk limits of a Profile. Zero values mean unlimited.
+type Limits struct {
+	MaxDepth int `json:"max-depth,omitempty" yaml:"max-depth,omitempty"`
+	MaxFiles int `json:"max-files,omitempty" yaml:"max-files,omitempty"`
+
+	// MaxEntries skips descending into directories of more entries than this.
+	MaxEntries int `json:"max-entries,omitempty" yaml:"max-entries,omitempty"`
+}
+
+// Sampling represents the opt-in sampled fingerprinting of large files. A zero Threshold disables sampling.
+type Sampling struct {
+	Threshold int64 `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	Size      int64 `json:"size,omitempty" yaml:"size,omitempty"`
+}
+
+// Parallel represents the opt-in chunk-parallel tree hashing of huge files, a zero Threshold disabling it, and
+// the number of Workers hashing and copying files at once, zero adapting it to the storage's latency and throughput.
+type Parallel struct {
+	Threshold int64 `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	Chunk     int64 `json:"chunk,omitempty" yaml:"chunk,omitempty"`
+	Workers   int   `json:"workers,omitempty" yaml:"workers,omitempty"`
+}
+
+// Load reads and parses the configuration file at path. A missing file at the
+// default location returns an empty Config.
+func Load(path string) (*Config, error) {
+	c := &Config{}
+
+	buffer, e := os.ReadFile(path)
+	if e != nil {
+		if errors.Is(e, os.ErrNotExist) && path == Filename {
+			return c, nil
+		}
+
+		return nil, e
+	}
+
+	if e := yaml.Unmarshal(buffer, c); e != nil {
+		return nil, exception.New(exception.ECONFIG, "load", path, e)
+	}
+
+	for _, profile := range c.Profiles {
+		for i, from := range profile.ExcludesFrom {
+			profile.ExcludesFrom[i] = relative(path, from)
+		}
+	}
+
+	return c, nil
+}
+
+// Select returns the named profile. An empty name selects the configuration's
+// default profile, if any; otherwise an empty Profile is returned.
+func (c *Config) Select(name string) (Profile, error) {
+	if name == "" {
+		name = c.Profile
+	}
+
+	if name == "" {
+		return Profile{}, nil
+	}
+
+	profile, valid := c.Profiles[name]
+	if !(valid) {
+		return Profile{}, exception.New(exception.EPROFILE, "select", "", fmt.Errorf("%q", name))
+	}
+
+	return profile, nil
+}
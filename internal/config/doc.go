@@ -0,0 +1,2 @@
+// Package config represents the command-line configuration file and its named profiles.
+package config
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Pattern represents a glob pattern read from an ignore file.
+type Pattern struct {
+	Glob string
+	Line int
+}
+
+// ReadIgnore reads the ignore file at path: one glob pattern per line, with
+// blank lines and lines starting with "#" skipped.
+func ReadIgnore(path string) ([]Pattern, error) {
+	f, e := os.Open(path)
+	if e != nil {
+		return nil, e
+	}
+
+	defer f.Close()
+
+	var patterns []Pattern
+
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		patterns = append(patterns, Pattern{Glob: text, Line: line})
+	}
+
+	return patterns, scanner.Err()
+}
+
+// relative resolves the ignore file path, referenced by the configuration file at configuration, against the
+// configuration's directory; absolute paths are returned as is.
+func relative(configuration, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(filepath.Dir(configuration), path)
+}
+
+// Patterns returns the profile's exclusion patterns, including those read from its ignore files.
+func (p Profile) Patterns() ([]string, error) {
+	patterns := append([]string{}, p.Excludes...)
+	for _, path := range p.ExcludesFrom {
+		partials, e := ReadIgnore(path)
+		if e != nil {
+			return nil, e
+		}
+
+		for _, pattern := range partials {
+			patterns = append(patterns, pattern.Glob)
+		}
+	}
+
+	return patterns, nil
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"cli/internal/fs/checksum"
+	"cli/internal/fs/tree"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Issue represents a single validation finding with its source position.
+type Issue struct {
+	File    string `json:"file" yaml:"file"`
+	Line    int    `json:"line,omitempty" yaml:"line,omitempty"`
+	Column  int    `json:"column,omitempty" yaml:"column,omitempty"`
+	Message string `json:"message" yaml:"message"`
+}
+
+func (i Issue) String() string {
+	if i.Line == 0 {
+		return fmt.Sprintf("%s: %s", i.File, i.Message)
+	} else if i.Column == 0 {
+		return fmt.Sprintf("%s:%d: %s", i.File, i.Line, i.Message)
+	}
+
+	return fmt.Sprintf("%s:%d:%d: %s", i.File, i.Line, i.Column, i.Message)
+}
+
+var (
+	keysConfig   = []string{"profile", "profiles"}
+	keysProfile  = []string{"excludes", "excludes-from", "hasher", "digests", "formats", "limits", "sampling", "parallel", "skip-markers", "standard-exclusions", "modes", "tags"}
+	keysLimits   = []string{"max-depth", "max-files", "max-entries"}
+	keysSampling = []string{"threshold", "size"}
+	keysParallel = []string{"threshold", "chunk", "workers"}
+)
+
+type validator struct {
+	file   string
+	issues []Issue
+}
+
+// Validate parses the configuration file at path, along with every ignore file
+// it references, and reports unknown keys, bad globs, and conflicting options.
+// The returned error is only non-nil if the configuration file can't be read.
+func Validate(path string) ([]Issue, error) {
+	buffer, e := os.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+
+	v := &validator{file: path}
+
+	var document yaml.Node
+	if e := yaml.Unmarshal(buffer, &document); e != nil {
+		v.issues = append(v.issues, Issue{File: path, Message: e.Error()})
+		return v.issues, nil
+	}
+
+	if len(document.Content) == 0 {
+		return v.issues, nil
+	}
+
+	var selection *yaml.Node
+	profiles := map[string]bool{}
+
+	v.mapping(document.Content[0], keysConfig, func(key string, value *yaml.Node) {
+		switch key {
+		case "profile":
+			selection = value
+		case "profiles":
+			v.mapping(value, nil, func(name string, value *yaml.Node) {
+				profiles[name] = true
+				v.profile(value)
+			})
+		}
+	})
+
+	if selection != nil && selection.Value != "" && !(profiles[selection.Value]) {
+		v.report(selection, "default profile %q is not defined under profiles", selection.Value)
+	}
+
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		if v.issues[i].File != v.issues[j].File {
+			return v.issues[i].File < v.issues[j].File
+		}
+
+		return v.issues[i].Line < v.issues[j].Line
+	})
+
+	return v.issues, nil
+}
+
+func (v *validator) report(node *yaml.Node, format string, arguments ...any) {
+	v.issues = append(v.issues, Issue{File: v.file, Line: node.Line, Column: node.Column, Message: fmt.Sprintf(format, arguments...)})
+}
+
+// mapping calls fn for every key-value pair of node, reporting keys not found in known. A nil known
+// accepts any key.
+func (v *validator) mapping(node *yaml.Node, known []string, fn func(key string, value *yaml.Node)) {
+	if node.Kind != yaml.MappingNode {
+		v.report(node, "expected a mapping")
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if known != nil && !(contains(known, key.Value)) {
+			v.report(key, "unknown key %q", key.Value)
+			continue
+		}
+
+		fn(key.Value, value)
+	}
+}
+
+func (v *validator) sequence(node *yaml.Node, fn func(item *yaml.Node)) {
+	if node.Kind != yaml.SequenceNode {
+		v.report(node, "expected a list")
+		return
+	}
+
+	for _, item := range node.Content {
+		fn(item)
+	}
+}
+
+func (v *validator) profile(node *yaml.Node) {
+	formats := map[string]bool{}
+
+	v.mapping(node, keysProfile, func(key string, value *yaml.Node) {
+		switch key {
+		case "excludes":
+			v.sequence(value, v.glob)
+		case "excludes-from":
+			v.sequence(value, func(item *yaml.Node) {
+				path := relative(v.file, item.Value)
+				patterns, e := ReadIgnore(path)
+				if e != nil {
+					v.report(item, "unreadable ignore file: %s", e.Error())
+					return
+				}
+
+				for _, pattern := range patterns {
+					if _, e := filepath.Match(pattern.Glob, ""); e != nil {
+						v.issues = append(v.issues, Issue{File: path, Line: pattern.Line, Message: fmt.Sprintf("bad glob %q: %s", pattern.Glob, e.Error())})
+					}
+				}
+			})
+		case "hasher":
+			if !(checksum.Algorithm(value.Value).Valid()) {
+				v.report(value, "unknown hasher %q (expected one of %v)", value.Value, checksum.Algorithms())
+			}
+		case "digests":
+			v.sequence(value, func(item *yaml.Node) {
+				if !(checksum.Algorithm(item.Value).Valid()) {
+					v.report(item, "unknown digest algorithm %q (expected one of %v)", item.Value, checksum.Algorithms())
+				}
+			})
+		case "formats":
+			v.sequence(value, func(item *yaml.Node) {
+				if !(contains(Formats, item.Value)) {
+					v.report(item, "unknown format %q (expected one of %v)", item.Value, Formats)
+				} else if formats[item.Value] {
+					v.report(item, "format %q is listed more than once", item.Value)
+				}
+
+				formats[item.Value] = true
+			})
+		case "skip-markers":
+			v.sequence(value, func(item *yaml.Node) {
+				if item.Value == "" || strings.ContainsRune(item.Value, '/') {
+					v.report(item, "skip marker %q must be a file name", item.Value)
+				}
+			})
+		case "standard-exclusions":
+			v.boolean(key, value)
+		case "modes":
+			v.sequence(value, func(item *yaml.Node) {
+				if _, e := tree.ParseRule(item.Value); e != nil {
+					v.report(item, "%s", e.Error())
+				}
+			})
+		case "tags":
+			v.mapping(value, nil, func(tag string, value *yaml.Node) {
+				v.sequence(value, v.glob)
+			})
+		case "limits":
+			v.mapping(value, keysLimits, v.natural)
+		case "sampling":
+			values := map[string]int64{}
+			v.mapping(value, keysSampling, func(key string, value *yaml.Node) {
+				v.natural(key, value)
+				values[key], _ = strconv.ParseInt(value.Value, 10, 64)
+			})
+
+			if values["threshold"] > 0 && values["size"] <= 0 {
+				v.report(value, "sampling threshold requires a positive sampling size")
+			} else if values["threshold"] > 0 && 2*values["size"] > values["threshold"] {
+				v.report(value, "sampling size %d covers the whole of files at the threshold %d", values["size"], values["threshold"])
+			}
+		case "parallel":
+			values := map[string]int64{}
+			v.mapping(value, keysParallel, func(key string, value *yaml.Node) {
+				v.natural(key, value)
+				values[key], _ = strconv.ParseInt(value.Value, 10, 64)
+			})
+
+			if values["threshold"] > 0 && values["chunk"] <= 0 {
+				v.report(value, "parallel threshold requires a positive chunk size")
+			}
+		}
+	})
+}
+
+// boolean reports values that aren't booleans.
+func (v *validator) boolean(key string, value *yaml.Node) {
+	if value.Kind != yaml.ScalarNode || value.ShortTag() != "!!bool" {
+		v.report(value, "%s must be true or false", key)
+	}
+}
+
+// natural reports values that aren't non-negative integers.
+func (v *validator) natural(key string, value *yaml.Node) {
+	if limit, e := strconv.ParseInt(value.Value, 10, 64); e != nil {
+		v.report(value, "%s must be an integer", key)
+	} else if limit < 0 {
+		v.report(value, "%s must not be negative", key)
+	}
+}
+
+func (v *validator) glob(item *yaml.Node) {
+	if _, e := filepath.Match(item.Value, ""); e != nil {
+		v.report(item, "bad glob %q: %s", item.Value, e.Error())
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,143 @@
+package daemon
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/render"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// State represents whether an Alert started or stopped firing.
+type State string
+
+const (
+	StateFiring   State = "firing"
+	StateResolved State = "resolved"
+)
+
+// Alert represents a rule condition that started, or stopped, holding at a path.
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Path      string    `json:"path"`
+	State     State     `json:"state"`
+	Message   string    `json:"message"`
+	Value     float64   `json:"value"`
+	Threshold float64   `json:"threshold"`
+	Time      time.Time `json:"time"`
+}
+
+// key identifies an Alert across evaluations.
+func (a Alert) key() string {
+	return a.Rule + "\x00" + a.Path
+}
+
+// sample represents the cumulative directory sizes of a walk.
+type sample struct {
+	time  time.Time
+	bytes map[string]int64
+}
+
+// record returns the sample of the directories of t.
+func record(t *tree.Node, now time.Time) sample {
+	s := sample{time: now, bytes: map[string]int64{t.Path: t.CountBytes()}}
+	for _, directory := range t.DirectoriesRecursive() {
+		s.bytes[directory.Path] = directory.CountBytes()
+	}
+
+	return s
+}
+
+// evaluate returns the alerts whose conditions hold for t at now, given the prior samples in ascending time order.
+func (r *Rules) evaluate(t *tree.Node, history []sample, now time.Time) []Alert {
+	var alerts []Alert
+
+	current := record(t, now)
+	for _, rule := range r.Rules {
+		scope := filepath.Join(t.Path, rule.Path)
+		directory, valid := t.Map()[scope]
+		if scope == t.Path {
+			directory, valid = t, true
+		}
+
+		if !(valid) {
+			continue
+		}
+
+		alert := func(path, message string, value, threshold float64) {
+			alerts = append(alerts, Alert{Rule: rule.Name, Path: path, State: StateFiring, Message: message, Value: value, Threshold: threshold, Time: now})
+		}
+
+		if rule.Growth > 0 {
+			if earlier, valid := before(history, now.Add(-time.Duration(rule.Window))); valid {
+				if previous := earlier.bytes[scope]; previous > 0 {
+					growth := 100 * float64(current.bytes[scope]-previous) / float64(previous)
+					if growth > rule.Growth {
+						alert(scope, fmt.Sprintf("%s grew %.1f%% (%s to %s) within %s", scope, growth, render.Bytes(previous), render.Bytes(current.bytes[scope]), time.Duration(rule.Window)), growth, rule.Growth)
+					}
+				}
+			}
+		}
+
+		if rule.MaxSize > 0 && directory.CountBytes() > int64(rule.MaxSize) {
+			alert(scope, fmt.Sprintf("%s holds %s, exceeding %s", scope, render.Bytes(directory.CountBytes()), render.Bytes(int64(rule.MaxSize))), float64(directory.CountBytes()), float64(rule.MaxSize))
+		}
+
+		if rule.MaxFileSize > 0 {
+			for _, file := range directory.FilesRecursive() {
+				if file.Size > int64(rule.MaxFileSize) {
+					alert(file.Path, fmt.Sprintf("%s is %s, exceeding %s", file.Path, render.Bytes(file.Size), render.Bytes(int64(rule.MaxFileSize))), float64(file.Size), float64(rule.MaxFileSize))
+				}
+			}
+		}
+	}
+
+	return alerts
+}
+
+// before returns the latest sample of history taken at or before cutoff.
+func before(history []sample, cutoff time.Time) (sample, bool) {
+	for i := len(history) - 1; i >= 0; i-- {
+		if !(history[i].time.After(cutoff)) {
+			return history[i], true
+		}
+	}
+
+	return sample{}, false
+}
+
+// transitions returns the alerts that started firing, along with resolutions of those that stopped,
+// relative to the previously firing alerts; firing is updated in place.
+func transitions(firing map[string]Alert, alerts []Alert, now time.Time) []Alert {
+	var changes []Alert
+
+	current := map[string]bool{}
+	for _, alert := range alerts {
+		current[alert.key()] = true
+		if _, valid := firing[alert.key()]; !(valid) {
+			changes = append(changes, alert)
+		}
+
+		firing[alert.key()] = alert
+	}
+
+	keys := make([]string, 0, len(firing))
+	for key := range firing {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if !(current[key]) {
+			resolved := firing[key]
+			resolved.State, resolved.Time = StateResolved, now
+			changes = append(changes, resolved)
+
+			delete(firing, key)
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,137 @@
+package daemon
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/progress"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Daemon represents the periodic walk of a tree, evaluating its Rules after every walk.
+type Daemon struct {
+	// Interval is the time between walks; it must be positive.
+	Interval time.Duration
+
+	// Walk walks the monitored tree.
+	Walk func() (*tree.Node, error)
+
+	Rules     *Rules
+	Notifiers []Notifier
+
+	// Errors receives non-fatal walk and notification errors; nil discards them.
+	Errors func(e error)
+
+	// Ledger persists the checksum verification results of every walk; nil verifies nothing.
+	Ledger *Ledger
+
+	// Progress returns the progress of the current walk, shown on the status page; nil omits it.
+	Progress func() *progress.Progress
+
+	mutex    sync.Mutex
+	history  []sample
+	firing   map[string]Alert
+	walks    int
+	walking  bool
+	last     time.Time
+	duration time.Duration
+	failure  error
+}
+
+// Run walks and evaluates the tree every Interval until ctx is done.
+func (d *Daemon) Run(ctx context.Context) error {
+	if d.Interval <= 0 {
+		return fmt.Errorf("invalid interval: %v", d.Interval)
+	}
+
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		d.Tick(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick performs a single walk and evaluation at now, notifying of alert transitions.
+func (d *Daemon) Tick(now time.Time) {
+	d.mutex.Lock()
+	d.walking = true
+	d.mutex.Unlock()
+
+	t, e := d.Walk()
+
+	changes, firing := d.evaluate(t, e, now)
+	if e != nil {
+		d.report(e)
+		return
+	}
+
+	if d.Ledger != nil {
+		d.mutex.Lock()
+		e := d.Ledger.Save()
+		d.mutex.Unlock()
+
+		if e != nil {
+			d.report(e)
+		}
+	}
+
+	for _, notifier := range d.Notifiers {
+		if e := notifier.Notify(changes, firing); e != nil {
+			d.report(e)
+		}
+	}
+}
+
+// evaluate records the outcome of a walk at now, returning its alert transitions and the firing alerts.
+func (d *Daemon) evaluate(t *tree.Node, failure error, now time.Time) (changes, firing []Alert) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.walks++
+	d.walking = false
+	d.last, d.duration, d.failure = now, time.Since(now), failure
+
+	if failure != nil {
+		return nil, nil
+	}
+
+	if d.firing == nil {
+		d.firing = map[string]Alert{}
+	}
+
+	alerts := d.Rules.evaluate(t, d.history, now)
+	changes = transitions(d.firing, alerts, now)
+
+	firing = make([]Alert, 0, len(d.firing))
+	for _, alert := range d.firing {
+		firing = append(firing, alert)
+	}
+
+	d.history = append(d.history, record(t, now))
+
+	if d.Ledger != nil {
+		d.Ledger.Verify(t, now)
+	}
+
+	// retain a single sample older than the longest window, the baseline of growth rules
+	cutoff := now.Add(-d.Rules.window())
+	for len(d.history) > 1 && !(d.history[1].time.After(cutoff)) {
+		d.history = d.history[1:]
+	}
+
+	return changes, firing
+}
+
+func (d *Daemon) report(e error) {
+	if d.Errors != nil {
+		d.Errors(e)
+	}
+}
@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"cli/internal/progress"
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+	"sort"
+	"time"
+)
+
+// Status represents the daemon's status page, for diagnosing stalled walks.
+type Status struct {
+	Walks   int             `json:"walks"`
+	Walking bool            `json:"walking"`
+	Walk    *progress.State `json:"walk,omitempty"`
+	Last    *time.Time      `json:"last-walk,omitempty"`
+	Seconds float64         `json:"last-walk-seconds"`
+	Error   string          `json:"last-error,omitempty"`
+	Samples int             `json:"samples"`
+	Firing  []Alert         `json:"firing"`
+}
+
+// Status returns the daemon's current Status; Walk is the position of the current, or last, walk.
+func (d *Daemon) Status() *Status {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	s := &Status{Walks: d.walks, Walking: d.walking, Samples: len(d.history), Firing: make([]Alert, 0, len(d.firing))}
+	if d.walks > 0 {
+		last := d.last
+		s.Last, s.Seconds = &last, d.duration.Seconds()
+	}
+
+	if d.failure != nil {
+		s.Error = d.failure.Error()
+	}
+
+	if d.Progress != nil {
+		s.Walk = d.Progress().State()
+	}
+
+	for _, alert := range d.firing {
+		s.Firing = append(s.Firing, alert)
+	}
+
+	sort.Slice(s.Firing, func(i, j int) bool {
+		return s.Firing[i].Rule < s.Firing[j].Rule || (s.Firing[i].Rule == s.Firing[j].Rule && s.Firing[i].Path < s.Firing[j].Path)
+	})
+
+	return s
+}
+
+// Handler returns the daemon's debug endpoints: the JSON Status at /debug/status, the Ledger's
+// Heatmap of the last 30 days at /debug/mismatches, and net/http/pprof at /debug/pprof/. The endpoints are served on their own mux, never on http.DefaultServeMux.
+func (d *Daemon) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/status", func(w http.ResponseWriter, r *http.Request) {
+		buffer, e := json.MarshalIndent(d.Status(), "", "    ")
+		if e != nil {
+			http.Error(w, e.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(append(buffer, '\n'))
+	})
+
+	mux.HandleFunc("/debug/mismatches", func(w http.ResponseWriter, r *http.Request) {
+		if d.Ledger == nil {
+			http.Error(w, "checksum verification is disabled; see --ledger", http.StatusNotFound)
+			return
+		}
+
+		d.mutex.Lock()
+		heatmap := d.Ledger.Heatmap(24*time.Hour, 30, 2)
+		d.mutex.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(heatmap.JSON() + "\n"))
+	})
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	return mux
+}
@@ -0,0 +1,2 @@
+// Package daemon represents the long-running monitoring mode: periodic walks evaluated against alerting rules.
+package daemon
@@ -0,0 +1,157 @@
+package daemon
+
+import (
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Class represents the verdict of a file's Mismatches.
+type Class string
+
+const (
+	// ClassSuspect is a file with repeated transient mismatches, likely a hardware or NFS issue.
+	ClassSuspect Class = "suspect"
+
+	// ClassTransient is a file with a single transient mismatch.
+	ClassTransient Class = "transient"
+
+	// ClassChanged is a file whose checksum only changed along with its size or modification time.
+	ClassChanged Class = "changed"
+)
+
+// Hotspot represents a file's Mismatches: its totals, and transient mismatches per heatmap bucket.
+type Hotspot struct {
+	Path      string    `json:"path" yaml:"path"`
+	Class     Class     `json:"class" yaml:"class"`
+	Transient int       `json:"transient" yaml:"transient"`
+	Changes   int       `json:"changes" yaml:"changes"`
+	Last      time.Time `json:"last" yaml:"last"`
+	Counts    []int     `json:"counts" yaml:"counts,flow"`
+}
+
+// Heatmap represents the files of a Ledger with checksum mismatches, suspects first, with their
+// transient mismatches bucketed over time.
+type Heatmap struct {
+	Ledger string      `json:"ledger" yaml:"ledger"`
+	Cycles int         `json:"cycles" yaml:"cycles"`
+	First  time.Time   `json:"first" yaml:"first"`
+	Last   time.Time   `json:"last" yaml:"last"`
+	Bucket string      `json:"bucket" yaml:"bucket"`
+	Starts []time.Time `json:"starts" yaml:"starts"`
+	Files  []Hotspot   `json:"files" yaml:"files"`
+}
+
+// Heatmap returns the Heatmap of the Ledger's mismatches, in buckets of the given width ending at
+// its last cycle. Files with at least repeated transient mismatches are suspects.
+func (l *Ledger) Heatmap(bucket time.Duration, buckets, repeated int) *Heatmap {
+	h := &Heatmap{Ledger: l.Path, Cycles: l.Cycles, First: l.First, Last: l.Last, Bucket: bucket.String(), Starts: make([]time.Time, buckets), Files: []Hotspot{}}
+
+	end := l.Last
+	for i := range h.Starts {
+		h.Starts[i] = end.Add(-time.Duration(buckets-i) * bucket)
+	}
+
+	for path, observation := range l.Files {
+		if len(observation.Mismatches) == 0 {
+			continue
+		}
+
+		spot := Hotspot{Path: path, Counts: make([]int, buckets)}
+		for _, mismatch := range observation.Mismatches {
+			if mismatch.Time.After(spot.Last) {
+				spot.Last = mismatch.Time
+			}
+
+			if !(mismatch.Transient) {
+				spot.Changes++
+				continue
+			}
+
+			spot.Transient++
+			if i := buckets - 1 - int(end.Sub(mismatch.Time)/bucket); i >= 0 && i < buckets {
+				spot.Counts[i]++
+			}
+		}
+
+		switch {
+		case spot.Transient >= repeated:
+			spot.Class = ClassSuspect
+		case spot.Transient > 0:
+			spot.Class = ClassTransient
+		default:
+			spot.Class = ClassChanged
+		}
+
+		h.Files = append(h.Files, spot)
+	}
+
+	rank := map[Class]int{ClassSuspect: 0, ClassTransient: 1, ClassChanged: 2}
+	sort.Slice(h.Files, func(i, j int) bool {
+		a, b := h.Files[i], h.Files[j]
+		if rank[a.Class] != rank[b.Class] {
+			return rank[a.Class] < rank[b.Class]
+		} else if a.Transient != b.Transient {
+			return a.Transient > b.Transient
+		}
+
+		return a.Path < b.Path
+	})
+
+	return h
+}
+
+func (h *Heatmap) JSON() string {
+	buffer, e := json.MarshalIndent(h, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (h *Heatmap) YAML() string {
+	buffer, e := yaml.Marshal(h)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing heatmap table to w: one row per file, one shaded cell per bucket,
+// oldest first.
+func (h *Heatmap) Text(w io.Writer, style render.Style) {
+	shades := []rune(" ░▒▓█")
+	if style.Plain {
+		shades = []rune(" .:*#")
+	}
+
+	fmt.Fprintln(w, i18n.T(i18n.HeatmapSummary, h.Ledger, h.Cycles, len(h.Files), h.Bucket))
+	if len(h.Files) == 0 {
+		return
+	}
+
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	since := i18n.T(i18n.HeatmapSince, h.Starts[0].Format(time.RFC3339))
+	fmt.Fprintf(t, "%s\t%s\t%s\t%s\t%s\t%s\n", i18n.T(i18n.HeatmapTransient), i18n.T(i18n.HeatmapChanges), i18n.T(i18n.HeatmapClass), i18n.T(i18n.HeatmapLast), since, i18n.T(i18n.ReportPath))
+	for _, spot := range h.Files {
+		var heat strings.Builder
+		for _, count := range spot.Counts {
+			heat.WriteRune(shades[min(count, len(shades)-1)])
+		}
+
+		fmt.Fprintf(t, "%d\t%d\t%s\t%s\t|%s|\t%s\n", spot.Transient, spot.Changes, spot.Class, spot.Last.Format(time.RFC3339), heat.String(), spot.Path)
+	}
+
+	t.Flush()
+}
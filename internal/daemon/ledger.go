@@ -0,0 +1,147 @@
+package daemon
+
+import (
+	"cli/internal/fs/tree"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// retention is how many of a file's most recent Mismatches a Ledger keeps.
+const retention = 64
+
+// Mismatch represents a file whose checksum changed between two consecutive walks.
+type Mismatch struct {
+	Time     time.Time `json:"time"`
+	Previous string    `json:"previous"`
+	Current  string    `json:"current"`
+
+	// Transient marks a checksum that changed while the file's size and modification time didn't: not a
+	// write, but the signature of failing hardware or an incoherent network file-system cache.
+	Transient bool `json:"transient"`
+}
+
+// Observation represents the last verified state of a file, and its history of Mismatches.
+type Observation struct {
+	Checksum   string     `json:"checksum,omitempty"`
+	Algorithm  string     `json:"algorithm,omitempty"`
+	Size       int64      `json:"size"`
+	Modified   time.Time  `json:"modified"`
+	Mismatches []Mismatch `json:"mismatches,omitempty"`
+}
+
+// Ledger represents the checksum verification results of every daemon cycle, persisted to Path so
+// that mismatches accumulate across restarts.
+type Ledger struct {
+	Path string `json:"-"`
+
+	Cycles int                     `json:"cycles"`
+	First  time.Time               `json:"first"`
+	Last   time.Time               `json:"last"`
+	Files  map[string]*Observation `json:"files"`
+}
+
+// Open reads the Ledger persisted at path; a missing file is an empty Ledger.
+func Open(path string) (*Ledger, error) {
+	l := &Ledger{Path: path, Files: map[string]*Observation{}}
+
+	buffer, e := os.ReadFile(path)
+	if errors.Is(e, fs.ErrNotExist) {
+		return l, nil
+	} else if e != nil {
+		return nil, e
+	}
+
+	if e := json.Unmarshal(buffer, l); e != nil {
+		return nil, e
+	}
+
+	if l.Files == nil {
+		l.Files = map[string]*Observation{}
+	}
+
+	return l, nil
+}
+
+// Verify compares the checksum of every file of t with the file's previous Observation, recording a
+// Mismatch for each that changed. Files that are no longer walked are forgotten, unless they have
+// Mismatches; those are kept, without a checksum, so reappearing files start from a fresh baseline.
+func (l *Ledger) Verify(t *tree.Node, now time.Time) {
+	if l.Cycles == 0 {
+		l.First = now
+	}
+
+	l.Cycles++
+	l.Last = now
+
+	walked := map[string]bool{}
+	for _, file := range t.FilesRecursive() {
+		if file.Checksum == nil || len(file.Errors) > 0 {
+			continue
+		}
+
+		walked[file.Path] = true
+
+		current := Observation{Checksum: *file.Checksum, Algorithm: file.Algorithm, Size: file.Size, Modified: file.Modified}
+
+		observation, valid := l.Files[file.Path]
+		if !(valid) {
+			l.Files[file.Path] = &current
+			continue
+		}
+
+		if observation.Checksum != "" && observation.Algorithm == current.Algorithm && observation.Checksum != current.Checksum {
+			transient := observation.Size == current.Size && observation.Modified.Equal(current.Modified)
+
+			current.Mismatches = append(observation.Mismatches, Mismatch{Time: now, Previous: observation.Checksum, Current: current.Checksum, Transient: transient})
+			if len(current.Mismatches) > retention {
+				current.Mismatches = current.Mismatches[len(current.Mismatches)-retention:]
+			}
+		} else {
+			current.Mismatches = observation.Mismatches
+		}
+
+		*observation = current
+	}
+
+	for path, observation := range l.Files {
+		if walked[path] {
+			continue
+		}
+
+		if len(observation.Mismatches) == 0 {
+			delete(l.Files, path)
+		} else {
+			observation.Checksum, observation.Algorithm = "", ""
+		}
+	}
+}
+
+// Save persists the Ledger to its Path. The file is replaced atomically.
+func (l *Ledger) Save() error {
+	buffer, e := json.Marshal(l)
+	if e != nil {
+		return e
+	}
+
+	temporary, e := os.CreateTemp(filepath.Dir(l.Path), ".cli-ledger-")
+	if e != nil {
+		return e
+	}
+
+	defer os.Remove(temporary.Name())
+
+	if _, e := temporary.Write(buffer); e != nil {
+		temporary.Close()
+		return e
+	}
+
+	if e := temporary.Close(); e != nil {
+		return e
+	}
+
+	return os.Rename(temporary.Name(), l.Path)
+}
@@ -0,0 +1,113 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Notifier delivers alert transitions, along with the currently firing alerts.
+type Notifier interface {
+	Notify(changes, firing []Alert) error
+}
+
+// Log writes each alert transition as a line to W.
+type Log struct {
+	W io.Writer
+}
+
+func (l *Log) Notify(changes, firing []Alert) error {
+	for _, alert := range changes {
+		if _, e := fmt.Fprintf(l.W, "%s [%s] %s: %s\n", alert.Time.Format(time.RFC3339), alert.State, alert.Rule, alert.Message); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Webhook posts alert transitions as a JSON document, {"alerts": [...]}, to URL.
+type Webhook struct {
+	URL    string
+	Client *http.Client
+}
+
+func (w *Webhook) Notify(changes, firing []Alert) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	buffer, e := json.Marshal(map[string][]Alert{"alerts": changes})
+	if e != nil {
+		return e
+	}
+
+	client := w.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	response, e := client.Post(w.URL, "application/json", bytes.NewReader(buffer))
+	if e != nil {
+		return e
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s: %s", w.URL, response.Status)
+	}
+
+	return nil
+}
+
+// Metrics writes the currently firing alerts to Path in the Prometheus text exposition format, for
+// the node_exporter textfile collector. The file is replaced atomically.
+type Metrics struct {
+	Path string
+}
+
+func (m *Metrics) Notify(changes, firing []Alert) error {
+	var buffer strings.Builder
+
+	buffer.WriteString("# HELP cli_alert Whether an alerting rule is firing for a path.\n")
+	buffer.WriteString("# TYPE cli_alert gauge\n")
+
+	sort.Slice(firing, func(i, j int) bool {
+		return firing[i].key() < firing[j].key()
+	})
+
+	for _, alert := range firing {
+		fmt.Fprintf(&buffer, "cli_alert{rule=%q,path=%q} 1\n", alert.Rule, alert.Path)
+	}
+
+	temporary, e := os.CreateTemp(filepath.Dir(m.Path), ".cli-metrics-")
+	if e != nil {
+		return e
+	}
+
+	defer os.Remove(temporary.Name())
+
+	if _, e := temporary.WriteString(buffer.String()); e != nil {
+		temporary.Close()
+		return e
+	}
+
+	if e := temporary.Close(); e != nil {
+		return e
+	}
+
+	// CreateTemp creates 0600 files, unreadable by a node exporter running as another user
+	if e := os.Chmod(temporary.Name(), 0o644); e != nil {
+		return e
+	}
+
+	return os.Rename(temporary.Name(), m.Path)
+}
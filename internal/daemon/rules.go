@@ -0,0 +1,202 @@
+package daemon
+
+import (
+	"bytes"
+	"cli/internal/config"
+	"cli/internal/exception"
+	"cli/internal/render"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Size represents a byte count, written either as an integer or with a unit, e.g. "2GB" or "512MiB".
+type Size int64
+
+func (s *Size) UnmarshalYAML(value *yaml.Node) error {
+	n, e := render.ParseBytes(value.Value)
+	if e != nil {
+		return positioned(value, e)
+	}
+
+	*s = Size(n)
+
+	return nil
+}
+
+// Duration represents a time.Duration written as a Go duration string, e.g. "1h" or "15m".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	duration, e := time.ParseDuration(value.Value)
+	if e != nil {
+		return positioned(value, e)
+	}
+
+	*d = Duration(duration)
+
+	return nil
+}
+
+// Rule represents an alerting rule, scoped to the directory at Path relative to the walked root.
+// Each of its non-zero conditions is evaluated independently.
+type Rule struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path,omitempty"`
+
+	// Growth alerts if the directory grows more than this many percent within Window.
+	Growth float64  `yaml:"growth,omitempty"`
+	Window Duration `yaml:"window,omitempty"`
+
+	// MaxSize alerts if the directory's cumulative size exceeds it.
+	MaxSize Size `yaml:"max-size,omitempty"`
+
+	// MaxFileSize alerts for every file beneath the directory exceeding it.
+	MaxFileSize Size `yaml:"max-file-size,omitempty"`
+}
+
+// positioned returns e as a yaml.TypeError at value's line, so decoding goes on to report the rest.
+func positioned(value *yaml.Node, e error) error {
+	return &yaml.TypeError{Errors: []string{fmt.Sprintf("line %d: %s", value.Line, e.Error())}}
+}
+
+// Rules represents the alerting rules file.
+type Rules struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Load reads and validates the alerting rules file at path; the first issue Validate would report fails it.
+func Load(path string) (*Rules, error) {
+	buffer, e := os.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+
+	r, issues := parse(path, buffer)
+	if len(issues) > 0 {
+		e := errors.New(issues[0].Message)
+		if issues[0].Line > 0 {
+			e = fmt.Errorf("line %d: %s", issues[0].Line, issues[0].Message)
+		}
+
+		return nil, exception.New(exception.ECONFIG, "load", path, e)
+	}
+
+	return r, nil
+}
+
+// Validate parses the alerting rules file at path, and reports unknown keys, bad values, and incomplete
+// rules, with their lines. The returned error is only non-nil if the file can't be read.
+func Validate(path string) ([]config.Issue, error) {
+	buffer, e := os.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+
+	_, issues := parse(path, buffer)
+
+	return issues, nil
+}
+
+// parse decodes the alerting rules of buffer, read from path, returning the issues found in line order.
+func parse(path string, buffer []byte) (*Rules, []config.Issue) {
+	var document yaml.Node
+	if e := yaml.Unmarshal(buffer, &document); e != nil {
+		return nil, []config.Issue{{File: path, Message: e.Error()}}
+	}
+
+	r := &Rules{}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(buffer))
+	decoder.KnownFields(true)
+
+	// type errors leave the rest decoded, so the rules are still checked
+	var issues []config.Issue
+	if e := decoder.Decode(r); e != nil && !(errors.Is(e, io.EOF)) {
+		var failure *yaml.TypeError
+		if !(errors.As(e, &failure)) {
+			return nil, []config.Issue{{File: path, Message: e.Error()}}
+		}
+
+		for _, message := range failure.Errors {
+			issue := config.Issue{File: path, Message: message}
+			if position, rest, valid := strings.Cut(message, ": "); valid {
+				if _, e := fmt.Sscanf(position, "line %d", &issue.Line); e == nil {
+					issue.Message = rest
+				}
+			}
+
+			issues = append(issues, issue)
+		}
+	}
+
+	positions := lines(&document)
+	for i, rule := range r.Rules {
+		problem := ""
+		switch {
+		case rule.Name == "":
+			problem = fmt.Sprintf("rule %d: missing name", i+1)
+		case rule.Growth > 0 && rule.Window <= 0:
+			problem = fmt.Sprintf("rule %q: growth requires a window", rule.Name)
+		case rule.Growth <= 0 && rule.MaxSize <= 0 && rule.MaxFileSize <= 0:
+			problem = fmt.Sprintf("rule %q: no condition", rule.Name)
+		}
+
+		if problem == "" {
+			continue
+		}
+
+		issue := config.Issue{File: path, Message: problem}
+		if i < len(positions) {
+			issue.Line = positions[i]
+		}
+
+		issues = append(issues, issue)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+
+	if len(issues) > 0 {
+		return nil, issues
+	}
+
+	return r, nil
+}
+
+// lines returns the line of every rule of document, in order.
+func lines(document *yaml.Node) []int {
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var positions []int
+
+	root := document.Content[0]
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "rules" && root.Content[i+1].Kind == yaml.SequenceNode {
+			for _, item := range root.Content[i+1].Content {
+				positions = append(positions, item.Line)
+			}
+		}
+	}
+
+	return positions
+}
+
+// window returns the longest growth window of the rules; history older than it is discarded.
+func (r *Rules) window() time.Duration {
+	var longest time.Duration
+	for _, rule := range r.Rules {
+		longest = max(longest, time.Duration(rule.Window))
+	}
+
+	return longest
+}
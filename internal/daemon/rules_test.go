@@ -0,0 +1,37 @@
+package daemon_test
+
+import (
+	"cli/internal/daemon"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateReportsRuleLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	rules := "rules:\n  - name: growth\n    growth: 10\n  - name: huge\n    max-file-size: 10EB\n  - name: typo\n    max-sise: 2GB\n    max-size: 1GB\n"
+	if e := os.WriteFile(path, []byte(rules), 0o644); e != nil {
+		t.Fatal(e)
+	}
+
+	issues, e := daemon.Validate(path)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	lines := map[int]bool{}
+	for _, issue := range issues {
+		lines[issue.Line] = true
+	}
+
+	// the rule without a window, the out-of-range size, and the unknown key
+	for _, line := range []int{2, 5, 7} {
+		if !(lines[line]) {
+			t.Errorf("no issue at line %d: %v", line, issues)
+		}
+	}
+
+	if _, e := daemon.Load(path); e == nil {
+		t.Fatal("loaded invalid rules")
+	}
+}
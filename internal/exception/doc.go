@@ -0,0 +1,3 @@
+// Package exception represents typed errors carrying stable codes for library consumers and
+// machine-facing error output.
+package exception
@@ -0,0 +1,136 @@
+package exception
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Code represents a stable, machine-facing error identifier.
+type Code string
+
+const (
+	ENILNODE        Code = "ENILNODE"
+	ENOTFILE        Code = "ENOTFILE"
+	ENOTDIR         Code = "ENOTDIR"
+	EREAD           Code = "EREAD"
+	EWRITE          Code = "EWRITE"
+	ETOOLARGE       Code = "ETOOLARGE"
+	EBINARY         Code = "EBINARY"
+	EWALKPARTIAL    Code = "EWALKPARTIAL"
+	EVERIFYMISMATCH Code = "EVERIFYMISMATCH"
+	EALGORITHM      Code = "EALGORITHM"
+	ECONFIG         Code = "ECONFIG"
+	EPROFILE        Code = "EPROFILE"
+	EINCOMPARABLE   Code = "EINCOMPARABLE"
+	ECOMMITTED      Code = "ECOMMITTED"
+	ECAPACITY       Code = "ECAPACITY"
+	EIMMUTABLE      Code = "EIMMUTABLE"
+	EREADONLY       Code = "EREADONLY"
+	ESYMLINK        Code = "ESYMLINK"
+	ELAYOUT         Code = "ELAYOUT"
+	EDRIFT          Code = "EDRIFT"
+	EKEY            Code = "EKEY"
+	EUNSUPPORTED    Code = "EUNSUPPORTED"
+	ELABEL          Code = "ELABEL"
+)
+
+var descriptions = map[Code]string{
+	ENILNODE:        "nil node",
+	ENOTFILE:        "invalid file node",
+	ENOTDIR:         "invalid directory",
+	EREAD:           "unable to read",
+	EWRITE:          "unable to write",
+	ETOOLARGE:       "file too large",
+	EBINARY:         "binary file",
+	EWALKPARTIAL:    "walk completed with errors",
+	EVERIFYMISMATCH: "checksum mismatch",
+	EALGORITHM:      "invalid checksum algorithm",
+	ECONFIG:         "invalid configuration",
+	EPROFILE:        "unknown profile",
+	EINCOMPARABLE:   "incomparable snapshots",
+	ECOMMITTED:      "workspace already committed",
+	ECAPACITY:       "insufficient destination capacity",
+	EIMMUTABLE:      "immutable or append-only destination",
+	EREADONLY:       "read-only assertion violated",
+	ESYMLINK:        "symbolic link refused",
+	ELAYOUT:         "checkout doesn't match the manifest's layout",
+	EDRIFT:          "permissions drifted from the baseline",
+	EKEY:            "missing hmac key",
+	EUNSUPPORTED:    "unsupported on this platform",
+	ELABEL:          "mismatched digest label",
+}
+
+// Error represents a typed error: the failed operation, the path it failed on, and the stable Code.
+type Error struct {
+	Code Code
+	Op   string
+	Path string
+	Err  error
+}
+
+// New returns an Error of the given code; sentinel values are created with empty op and path.
+func New(code Code, op, path string, err error) *Error {
+	return &Error{Code: code, Op: op, Path: path, Err: err}
+}
+
+func (e *Error) Error() string {
+	message := descriptions[e.Code]
+	if e.Err != nil {
+		message = message + ": " + e.Err.Error()
+	}
+
+	switch {
+	case e.Op != "" && e.Path != "":
+		return e.Op + " " + e.Path + ": " + message
+	case e.Op != "":
+		return e.Op + ": " + message
+	case e.Path != "":
+		return e.Path + ": " + message
+	}
+
+	return message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an *Error of the same Code; a target's non-empty Op and Path must match too.
+func (e *Error) Is(target error) bool {
+	t, valid := target.(*Error)
+	if !(valid) {
+		return false
+	}
+
+	return t.Code == e.Code && (t.Op == "" || t.Op == e.Op) && (t.Path == "" || t.Path == e.Path)
+}
+
+// document is the machine-facing representation of an error.
+type document struct {
+	Code    Code   `json:"code,omitempty"`
+	Op      string `json:"op,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(document{e.Code, e.Op, e.Path, e.Error()})
+}
+
+// JSON returns the machine-facing representation of err, carrying the code, op, and path of the
+// first typed Error in its chain, if any.
+func JSON(err error) string {
+	d := document{Message: err.Error()}
+
+	var typed *Error
+	if errors.As(err, &typed) {
+		d.Code, d.Op, d.Path = typed.Code, typed.Op, typed.Path
+	}
+
+	buffer, e := json.Marshal(d)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
@@ -0,0 +1,2 @@
+// Package export represents exporters rewriting a tree into another layout, such as a content-addressed store.
+package export
@@ -0,0 +1,35 @@
+package export
+
+// alphabet is Nix's base-32 alphabet, omitting e, o, u, and t.
+const alphabet = "0123456789abcdfghijklmnpqrsvwxyz"
+
+// nix32 encodes buffer in Nix's base-32, as used in store path names: least significant quintets of the
+// little-endian buffer last.
+func nix32(buffer []byte) string {
+	length := (len(buffer)*8-1)/5 + 1
+
+	encoded := make([]byte, 0, length)
+	for n := length - 1; n >= 0; n-- {
+		b := n * 5
+		i, j := b/8, b%8
+
+		c := buffer[i] >> j
+		if i+1 < len(buffer) {
+			c |= buffer[i+1] << (8 - j)
+		}
+
+		encoded = append(encoded, alphabet[c&0x1f])
+	}
+
+	return string(encoded)
+}
+
+// fold compresses digest to size bytes by XOR-ing its bytes in, as Nix shortens store path hashes.
+func fold(digest []byte, size int) []byte {
+	folded := make([]byte, size)
+	for i, b := range digest {
+		folded[i%size] ^= b
+	}
+
+	return folded
+}
@@ -0,0 +1,237 @@
+package export
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Object represents a top-level entry of an exported tree, and its content-addressed store path.
+type Object struct {
+	// Path is the entry's path relative to the exported root.
+	Path string          `json:"path" yaml:"path"`
+	Type tree.Descriptor `json:"type" yaml:"type"`
+
+	// Hash is the hex-encoded digest of the entry's name and content; Store is its store path name,
+	// the Nix base-32 encoding of the digest folded to 20 bytes, a dash, and the entry's name.
+	Hash  string `json:"hash" yaml:"hash"`
+	Store string `json:"store" yaml:"store"`
+
+	// Reused records the store path was already present, with identical content by construction.
+	Reused bool `json:"reused,omitempty" yaml:"reused,omitempty"`
+}
+
+// Manifest represents the mapping of a tree's entries to their store paths.
+type Manifest struct {
+	Source      string   `json:"source" yaml:"source"`
+	Destination string   `json:"destination" yaml:"destination"`
+	Objects     []Object `json:"objects" yaml:"objects"`
+}
+
+// Store exports each top-level entry of root to destination as a Nix store-style object, named by the digest of
+// its name and content, so equal content always gets the same name and different content never shares one.
+// Objects are staged, digesting the bytes as they're copied, then renamed into place under that digest, so a store
+// path never holds partial content, nor content other than its name's; present store paths are reused as is. With
+// dry, entries are only read, to compute the Manifest.
+//
+// Digests are SHA-256 over the bytes read, never the walk's checksums, so store paths don't vary with the hasher,
+// nor with files changing between the walk and the export.
+func Store(root *tree.Node, destination string, dry bool) (*Manifest, error) {
+	m := &Manifest{Source: root.Path, Destination: destination, Objects: make([]Object, 0)}
+
+	if !(dry) {
+		if e := os.MkdirAll(destination, 0o755); e != nil {
+			return nil, e
+		}
+	}
+
+	for _, child := range root.Children() {
+		object, e := stage(child, destination, dry)
+		if e != nil {
+			return nil, e
+		}
+
+		m.Objects = append(m.Objects, object)
+	}
+
+	return m, nil
+}
+
+// stage writes n to a staging path of destination, digesting it, then renames it to the store path name its
+// digest gives, unless that's present; with dry, n is only digested.
+func stage(n *tree.Node, destination string, dry bool) (Object, error) {
+	target := ""
+	if !(dry) {
+		staging, e := os.MkdirTemp(destination, ".staging-")
+		if e != nil {
+			return Object{}, e
+		}
+
+		defer os.RemoveAll(staging)
+
+		target = filepath.Join(staging, n.Name)
+	}
+
+	digest, e := write(n, target)
+	if e != nil {
+		return Object{}, e
+	}
+
+	object := Object{
+		Path:  n.Name,
+		Type:  n.Type,
+		Hash:  hex.EncodeToString(digest),
+		Store: nix32(fold(digest, 20)) + "-" + n.Name,
+	}
+
+	if _, e := os.Lstat(filepath.Join(destination, object.Store)); e == nil {
+		object.Reused = true
+	} else if !(errors.Is(e, os.ErrNotExist)) {
+		return Object{}, e
+	} else if !(dry) {
+		if e := os.Rename(target, filepath.Join(destination, object.Store)); e != nil {
+			return Object{}, e
+		}
+	}
+
+	return object, nil
+}
+
+// write writes n's content to target, unless it's empty: directories 0755, and files 0755 or 0644 by their
+// executable bit. It returns the digest of n's name and content, as written: a file's executable bit and the
+// SHA-256 of its bytes, a symbolic link's target, or a directory's children's names and digests, in name order.
+func write(n *tree.Node, target string) ([]byte, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", n.Type, n.Name)
+
+	switch n.Type {
+	case tree.Symbolic:
+		if target != "" {
+			if e := os.Symlink(n.Target, target); e != nil {
+				return nil, e
+			}
+		}
+
+		fmt.Fprintf(h, "%s\x00", n.Target)
+
+		return h.Sum(nil), nil
+	case tree.Directory:
+		if target != "" {
+			if e := os.Mkdir(target, 0o755); e != nil {
+				return nil, e
+			}
+		}
+
+		for _, child := range n.Children() {
+			path := ""
+			if target != "" {
+				path = filepath.Join(target, child.Name)
+			}
+
+			digest, e := write(child, path)
+			if e != nil {
+				return nil, e
+			}
+
+			fmt.Fprintf(h, "%x\x00", digest)
+		}
+
+		return h.Sum(nil), nil
+	}
+
+	source, e := os.Open(n.Path)
+	if e != nil {
+		return nil, e
+	}
+
+	defer source.Close()
+
+	info, e := source.Stat()
+	if e != nil {
+		return nil, e
+	}
+
+	content := sha256.New()
+	if target == "" {
+		if _, e := io.Copy(content, source); e != nil {
+			return nil, e
+		}
+	} else {
+		var mode os.FileMode = 0o644
+		if executable(info) {
+			mode = 0o755
+		}
+
+		f, e := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+		if e != nil {
+			return nil, e
+		}
+
+		if _, e := io.Copy(io.MultiWriter(f, content), source); e != nil {
+			f.Close()
+			return nil, e
+		}
+
+		if e := f.Close(); e != nil {
+			return nil, e
+		}
+	}
+
+	fmt.Fprintf(h, "%t\x00%x\x00", executable(info), content.Sum(nil))
+
+	return h.Sum(nil), nil
+}
+
+func executable(info os.FileInfo) bool {
+	return info.Mode().Perm()&0o111 != 0
+}
+
+// Write writes the Manifest's JSON document to path.
+func (m *Manifest) Write(path string) error {
+	return os.WriteFile(path, []byte(m.JSON()+"\n"), 0o644)
+}
+
+func (m *Manifest) JSON() string {
+	buffer, e := json.MarshalIndent(m, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (m *Manifest) YAML() string {
+	buffer, e := yaml.Marshal(m)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing path-to-store-path table to w.
+func (m *Manifest) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\t\n", i18n.T(i18n.ReportPath), i18n.T(i18n.ExportStore))
+	for _, object := range m.Objects {
+		reused := ""
+		if object.Reused {
+			reused = i18n.T(i18n.ExportReused)
+		}
+
+		fmt.Fprintf(t, "%s\t%s\t%s\n", object.Path, filepath.Join(m.Destination, object.Store), reused)
+	}
+
+	t.Flush()
+}
@@ -0,0 +1,76 @@
+package export_test
+
+import (
+	"cli/internal/export"
+	"cli/internal/fs/checksum"
+	"cli/internal/fs/tree"
+	"cli/internal/fs/tree/treetest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStoreNamesObjectsByExportedBytes(t *testing.T) {
+	f := treetest.New(t, map[string]string{
+		"bin/tool":   "binary",
+		"README.md":  "# readme",
+		"etc/config": "key = value",
+	})
+
+	var names []string
+	for _, algorithm := range []checksum.Algorithm{checksum.AlgorithmMD5, checksum.AlgorithmSHA256} {
+		n, e := tree.Walk(f.Root, tree.WithAlgorithm(algorithm))
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		m, e := export.Store(n, t.TempDir(), true)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		names = append(names, m.Objects[0].Store)
+	}
+
+	if names[0] != names[1] {
+		t.Fatalf("store paths %v vary with the walk's hasher", names)
+	}
+
+	n, e := tree.Walk(f.Root)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// the file changes between the walk and the export
+	if e := os.WriteFile(filepath.Join(f.Root, "README.md"), []byte("# changed"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+
+	destination := t.TempDir()
+	m, e := export.Store(n, destination, false)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	rewalked, e := tree.Walk(f.Root)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	expected, e := export.Store(rewalked, t.TempDir(), true)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	for i, object := range m.Objects {
+		if object.Store != expected.Objects[i].Store {
+			t.Errorf("%s exported as %s; expected %s, of its exported bytes", object.Path, object.Store, expected.Objects[i].Store)
+		}
+
+		if object.Path == "README.md" {
+			if contents, e := os.ReadFile(filepath.Join(destination, object.Store)); e != nil || string(contents) != "# changed" {
+				t.Errorf("exported %q (%v)", contents, e)
+			}
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package filter represents file filter expressions, shared by reports and copies, e.g. "tag=runtime,size<10MB".
+package filter
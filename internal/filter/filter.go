@@ -0,0 +1,176 @@
+package filter
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/render"
+	"fmt"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// operators are the supported comparisons, longest first so "<=" isn't parsed as "<".
+var operators = []string{"!=", "<=", ">=", "=", "<", ">"}
+
+// term represents a single comparison of an expression.
+type term struct {
+	key, operator, value string
+	number               int64
+}
+
+// Filter represents a conjunction of file predicates, parsed by Parse.
+type Filter struct {
+	Expression string
+
+	terms []term
+}
+
+// Parse parses a comma-separated expression of terms, every one of which a file must match:
+//
+//   - glob=PATTERN, glob!=PATTERN matches the file's name, path, or path relative to the tree's root.
+//   - tag=NAME, tag!=NAME matches the file's tags.
+//   - size<N, size<=N, size>N, size>=N, size=N compares the file's size, e.g. 10MB or 4KiB.
+//   - owner=USER, group=GROUP (or != either) compares the file's owner, by name or numeric identifier.
+func Parse(expression string) (*Filter, error) {
+	f := &Filter{Expression: expression}
+	for _, text := range strings.Split(expression, ",") {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		t, e := parse(text)
+		if e != nil {
+			return nil, fmt.Errorf("invalid filter term %q: %w", text, e)
+		}
+
+		f.terms = append(f.terms, t)
+	}
+
+	if len(f.terms) == 0 {
+		return nil, fmt.Errorf("invalid filter %q: no terms", expression)
+	}
+
+	return f, nil
+}
+
+func parse(text string) (t term, e error) {
+	index := -1
+	for _, operator := range operators {
+		if i := strings.Index(text, operator); i > 0 && (index < 0 || i < index) {
+			index, t.operator = i, operator
+		}
+	}
+
+	if index < 0 {
+		return t, fmt.Errorf("expected KEY OPERATOR VALUE")
+	}
+
+	t.key, t.value = strings.TrimSpace(text[:index]), strings.TrimSpace(text[index+len(t.operator):])
+
+	equality := t.operator == "=" || t.operator == "!="
+	switch t.key {
+	case "glob":
+		if _, e := filepath.Match(t.value, ""); e != nil {
+			return t, e
+		}
+	case "tag":
+	case "size":
+		t.number, e = render.ParseBytes(t.value)
+		return t, e
+	case "owner":
+		t.number, e = identifier(t.value, func(name string) (string, error) {
+			u, e := user.Lookup(name)
+			if e != nil {
+				return "", e
+			}
+
+			return u.Uid, nil
+		})
+	case "group":
+		t.number, e = identifier(t.value, func(name string) (string, error) {
+			g, e := user.LookupGroup(name)
+			if e != nil {
+				return "", e
+			}
+
+			return g.Gid, nil
+		})
+	default:
+		return t, fmt.Errorf("unknown key %q (expected glob, tag, size, owner, or group)", t.key)
+	}
+
+	if !(equality) {
+		return t, fmt.Errorf("%s only supports = and !=", t.key)
+	}
+
+	return t, e
+}
+
+// identifier returns the numeric identifier value, resolving names via lookup.
+func identifier(value string, lookup func(name string) (string, error)) (int64, error) {
+	if id, e := strconv.ParseInt(value, 10, 64); e == nil {
+		return id, nil
+	}
+
+	id, e := lookup(value)
+	if e != nil {
+		return 0, e
+	}
+
+	return strconv.ParseInt(id, 10, 64)
+}
+
+// Match reports whether the file n matches every term of the Filter.
+func (f *Filter) Match(n *tree.Node) bool {
+	for _, t := range f.terms {
+		if !(t.match(n)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (t term) match(n *tree.Node) bool {
+	var matched bool
+	switch t.key {
+	case "glob":
+		relative, _ := filepath.Rel(n.Root().Path, n.Path)
+		for _, candidate := range []string{n.Name, n.Path, relative} {
+			if m, _ := filepath.Match(t.value, candidate); m {
+				matched = true
+			}
+		}
+	case "tag":
+		for _, tag := range n.Tags {
+			matched = matched || tag == t.value
+		}
+	case "owner":
+		matched = n.Owner != nil && int64(n.Owner.UID) == t.number
+	case "group":
+		matched = n.Owner != nil && int64(n.Owner.GID) == t.number
+	case "size":
+		switch t.operator {
+		case "<":
+			return n.Size < t.number
+		case "<=":
+			return n.Size <= t.number
+		case ">":
+			return n.Size > t.number
+		case ">=":
+			return n.Size >= t.number
+		case "=":
+			return n.Size == t.number
+		case "!=":
+			return n.Size != t.number
+		}
+	}
+
+	if t.operator == "!=" {
+		return !(matched)
+	}
+
+	return matched
+}
@@ -0,0 +1,3 @@
+// Package find represents find(1)-compatible queries over an in-memory tree, for shell scripts to query walks
+// and snapshots without rewriting their find invocations.
+package find
@@ -0,0 +1,281 @@
+package find
+
+import (
+	"cli/internal/fs/tree"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// predicate represents a single test of an expression.
+type predicate func(n *tree.Node, now time.Time) bool
+
+// Query represents a parsed find invocation: starting points, options, and an expression of tests, all of which
+// must hold.
+type Query struct {
+	Paths []string
+
+	// Snapshot is the snapshot document queried rather than walking Paths.
+	Snapshot string
+
+	// MaxDepth limits the depth below each starting point; -1 means unlimited.
+	MaxDepth int
+
+	// Print0 terminates each printed path with a NUL rather than a newline.
+	Print0 bool
+
+	tests []predicate
+}
+
+// Parse parses find arguments: starting points, then -name, -iname, -type, -size, -mtime, -maxdepth, -not (or !),
+// -print, and -print0. Starting points default to ".", and --snapshot FILE may precede them.
+func Parse(args []string) (*Query, error) {
+	q := &Query{MaxDepth: -1}
+
+	for len(args) > 0 && (!(strings.HasPrefix(args[0], "-")) || args[0] == "--snapshot") && args[0] != "!" {
+		if args[0] == "--snapshot" {
+			if len(args) < 2 {
+				return nil, fmt.Errorf("find: missing argument to %s", args[0])
+			}
+
+			q.Snapshot, args = args[1], args[2:]
+			continue
+		}
+
+		q.Paths, args = append(q.Paths, args[0]), args[1:]
+	}
+
+	if len(q.Paths) == 0 {
+		q.Paths = []string{"."}
+	}
+
+	negate := false
+	for len(args) > 0 {
+		primary := args[0]
+		args = args[1:]
+
+		argument := func() (string, error) {
+			if len(args) == 0 {
+				return "", fmt.Errorf("find: missing argument to %s", primary)
+			}
+
+			value := args[0]
+			args = args[1:]
+
+			return value, nil
+		}
+
+		var test predicate
+		switch primary {
+		case "!", "-not":
+			negate = !(negate)
+			continue
+		case "-print":
+			continue
+		case "-print0":
+			q.Print0 = true
+			continue
+		case "-maxdepth":
+			value, e := argument()
+			if e != nil {
+				return nil, e
+			}
+
+			depth, e := strconv.Atoi(value)
+			if e != nil || depth < 0 {
+				return nil, fmt.Errorf("find: invalid argument %q to -maxdepth", value)
+			}
+
+			q.MaxDepth = depth
+			continue
+		case "-name", "-iname":
+			pattern, e := argument()
+			if e != nil {
+				return nil, e
+			} else if _, e := filepath.Match(pattern, ""); e != nil {
+				return nil, fmt.Errorf("find: invalid pattern %q to %s: %w", pattern, primary, e)
+			}
+
+			insensitive := primary == "-iname"
+			test = func(n *tree.Node, now time.Time) bool {
+				name := n.Name
+				if insensitive {
+					return match(strings.ToLower(pattern), strings.ToLower(name))
+				}
+
+				return match(pattern, name)
+			}
+		case "-type":
+			value, e := argument()
+			if e != nil {
+				return nil, e
+			}
+
+			descriptor, valid := map[string]tree.Descriptor{"f": tree.File, "d": tree.Directory, "l": tree.Symbolic}[value]
+			if !(valid) {
+				return nil, fmt.Errorf("find: unsupported argument %q to -type (expected f, d, or l)", value)
+			}
+
+			test = func(n *tree.Node, now time.Time) bool {
+				return n.Type == descriptor
+			}
+		case "-size":
+			value, e := argument()
+			if e != nil {
+				return nil, e
+			}
+
+			if test, e = size(value); e != nil {
+				return nil, e
+			}
+		case "-mtime":
+			value, e := argument()
+			if e != nil {
+				return nil, e
+			}
+
+			if test, e = mtime(value); e != nil {
+				return nil, e
+			}
+		default:
+			return nil, fmt.Errorf("find: unsupported primary %q", primary)
+		}
+
+		if negate {
+			positive := test
+			test = func(n *tree.Node, now time.Time) bool {
+				return !(positive(n, now))
+			}
+
+			negate = false
+		}
+
+		q.tests = append(q.tests, test)
+	}
+
+	if negate {
+		return nil, fmt.Errorf("find: expected an expression after !")
+	}
+
+	return q, nil
+}
+
+func match(pattern, name string) bool {
+	matched, _ := filepath.Match(pattern, name)
+
+	return matched
+}
+
+// numeric parses find's [+-]N numeric argument into a comparison of a value against N.
+func numeric(value string) (func(v, n int64) bool, string) {
+	switch {
+	case strings.HasPrefix(value, "+"):
+		return func(v, n int64) bool { return v > n }, value[1:]
+	case strings.HasPrefix(value, "-"):
+		return func(v, n int64) bool { return v < n }, value[1:]
+	}
+
+	return func(v, n int64) bool { return v == n }, value
+}
+
+// size parses -size [+-]N[cwbkMG]: the file size, rounded up to units of 512-byte blocks unless suffixed
+// with bytes (c), two-byte words (w), kibibytes (k), mebibytes (M), or gibibytes (G), compared with N.
+func size(value string) (predicate, error) {
+	compare, digits := numeric(value)
+
+	unit := int64(512)
+	if suffix := digits[len(digits)-min(len(digits), 1):]; strings.ContainsAny(suffix, "cwbkMG") {
+		unit = map[string]int64{"c": 1, "w": 2, "b": 512, "k": 1 << 10, "M": 1 << 20, "G": 1 << 30}[suffix]
+		digits = digits[:len(digits)-1]
+	}
+
+	n, e := strconv.ParseInt(digits, 10, 64)
+	if e != nil || n < 0 {
+		return nil, fmt.Errorf("find: invalid argument %q to -size", value)
+	}
+
+	return func(node *tree.Node, now time.Time) bool {
+		return compare((sized(node)+unit-1)/unit, n)
+	}, nil
+}
+
+// sized returns n's size as lstat(2) reports it, which for symbolic links is their target's length; walks only
+// record regular files' sizes, so directories' are stat'ed where they still exist.
+func sized(n *tree.Node) int64 {
+	switch n.Type {
+	case tree.Symbolic:
+		return int64(len(n.Target))
+	case tree.Directory:
+		if info, e := os.Lstat(n.Path); e == nil {
+			return info.Size()
+		}
+	}
+
+	return n.Size
+}
+
+// mtime parses -mtime [+-]N: the 24-hour periods since modification, rounded down, compared with N.
+func mtime(value string) (predicate, error) {
+	compare, digits := numeric(value)
+
+	n, e := strconv.ParseInt(digits, 10, 64)
+	if e != nil || n < 0 {
+		return nil, fmt.Errorf("find: invalid argument %q to -mtime", value)
+	}
+
+	return func(node *tree.Node, now time.Time) bool {
+		days := int64(math.Floor(now.Sub(node.Modified).Hours() / 24))
+
+		return compare(days, n)
+	}, nil
+}
+
+// Match reports whether n satisfies every test of the expression.
+func (q *Query) Match(n *tree.Node, now time.Time) bool {
+	for _, test := range q.tests {
+		if !(test(n, now)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Print writes the path of each Node of start's subtree matching the Query, within MaxDepth, to w, in pre-order
+// by name. Paths are printed as find does: the starting point as given, joined with the path relative to it.
+func (q *Query) Print(w io.Writer, path string, start *tree.Node, now time.Time) error {
+	terminator := "\n"
+	if q.Print0 {
+		terminator = "\x00"
+	}
+
+	var failure error
+	start.Each(func(n *tree.Node) bool {
+		depth := n.Depth() - start.Depth()
+		if q.MaxDepth >= 0 && depth > q.MaxDepth {
+			return false
+		}
+
+		if q.Match(n, now) {
+			printed := path
+			if depth > 0 {
+				relative, _ := filepath.Rel(start.Path, n.Path)
+				printed = strings.TrimSuffix(path, "/") + "/" + filepath.ToSlash(relative)
+			}
+
+			if _, e := io.WriteString(w, printed+terminator); e != nil {
+				failure = e
+				return false
+			}
+		}
+
+		return failure == nil
+	})
+
+	return failure
+}
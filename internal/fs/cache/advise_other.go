@@ -0,0 +1,8 @@
+//go:build !(linux || freebsd)
+
+package cache
+
+import "os"
+
+// advise is a no-op; readahead hints are only given on Linux and FreeBSD.
+func advise(f *os.File) {}
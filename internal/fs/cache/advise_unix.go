@@ -0,0 +1,17 @@
+//go:build linux || freebsd
+
+package cache
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// advise hints the kernel that f will be read sequentially, in full, soon; hints are best-effort.
+func advise(f *os.File) {
+	fd := int(f.Fd())
+
+	_ = unix.Fadvise(fd, 0, 0, unix.FADV_SEQUENTIAL)
+	_ = unix.Fadvise(fd, 0, 0, unix.FADV_WILLNEED)
+}
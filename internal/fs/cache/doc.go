@@ -0,0 +1,3 @@
+// Package cache represents page-cache warming: reading files sequentially, with readahead hints, so later
+// reads are served from memory.
+package cache
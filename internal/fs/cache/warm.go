@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Warming represents the outcome of warming a tree's files into the page cache.
+type Warming struct {
+	Root    string  `json:"root" yaml:"root"`
+	Files   int     `json:"files" yaml:"files"`
+	Bytes   int64   `json:"bytes" yaml:"bytes"`
+	Seconds float64 `json:"seconds" yaml:"seconds"`
+
+	// Throughput is the bytes read per second.
+	Throughput float64 `json:"throughput" yaml:"throughput"`
+
+	// Skipped maps the files that couldn't be read to their error.
+	Skipped map[string]string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+// File reads the file at path to its end, after hinting sequential readahead, returning the bytes read.
+func File(path string, buffer []byte) (int64, error) {
+	f, e := os.Open(path)
+	if e != nil {
+		return 0, e
+	}
+
+	defer f.Close()
+
+	advise(f)
+
+	var total int64
+	for {
+		n, e := f.Read(buffer)
+		total += int64(n)
+		if errors.Is(e, io.EOF) {
+			return total, nil
+		} else if e != nil {
+			return total, e
+		}
+	}
+}
+
+// Warm reads every file of root, in walk order, into the page cache.
+func Warm(root *tree.Node) *Warming {
+	c := &Warming{Root: root.Path}
+
+	start := time.Now()
+	buffer := make([]byte, 1<<20)
+	for _, n := range root.FilesRecursive() {
+		bytes, e := File(n.Path, buffer)
+		c.Bytes += bytes
+		if e != nil {
+			if c.Skipped == nil {
+				c.Skipped = map[string]string{}
+			}
+
+			c.Skipped[n.Path] = e.Error()
+			continue
+		}
+
+		c.Files++
+	}
+
+	c.Seconds = time.Since(start).Seconds()
+	if c.Seconds > 0 {
+		c.Throughput = float64(c.Bytes) / c.Seconds
+	}
+
+	return c
+}
+
+func (c *Warming) JSON() string {
+	buffer, e := json.MarshalIndent(c, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (c *Warming) YAML() string {
+	buffer, e := yaml.Marshal(c)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing warming outcome, then any skipped files, to w.
+func (c *Warming) Text(w io.Writer, style render.Style) {
+	fmt.Fprintln(w, i18n.T(i18n.WarmTotals, c.Root, c.Files, render.Bytes(c.Bytes), c.Seconds, render.Bytes(int64(c.Throughput))))
+
+	if len(c.Skipped) == 0 {
+		return
+	}
+
+	paths := make([]string, 0, len(c.Skipped))
+	for path := range c.Skipped {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	fmt.Fprintln(w)
+
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\n", i18n.T(i18n.WarmSkipped), i18n.T(i18n.WarmError))
+	for _, path := range paths {
+		fmt.Fprintf(t, "%s\t%s\n", path, c.Skipped[path])
+	}
+
+	t.Flush()
+}
@@ -0,0 +1,85 @@
+package checksum
+
+import (
+	"cli/internal/exception"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+
+	"github.com/zeebo/blake3"
+)
+
+// Algorithm represents a supported hashing algorithm.
+type Algorithm string
+
+const (
+	AlgorithmMD5    Algorithm = "md5"
+	AlgorithmSHA1   Algorithm = "sha1"
+	AlgorithmSHA256 Algorithm = "sha256"
+	AlgorithmSHA512 Algorithm = "sha512"
+	AlgorithmBLAKE3 Algorithm = "blake3"
+)
+
+var ExceptionInvalidAlgorithm = exception.New(exception.EALGORITHM, "", "", nil)
+
+// Algorithms returns all supported hashing algorithms.
+func Algorithms() []Algorithm {
+	return []Algorithm{AlgorithmMD5, AlgorithmSHA1, AlgorithmSHA256, AlgorithmSHA512, AlgorithmBLAKE3}
+}
+
+// Valid reports whether the Algorithm is supported.
+func (a Algorithm) Valid() bool {
+	for _, algorithm := range Algorithms() {
+		if a == algorithm {
+			return true
+		}
+	}
+
+	return false
+}
+
+// New returns a new hash.Hash for the given Algorithm.
+func (a Algorithm) New() (hash.Hash, error) {
+	switch a {
+	case AlgorithmMD5:
+		return md5.New(), nil
+	case AlgorithmSHA1:
+		return sha1.New(), nil
+	case AlgorithmSHA256, "":
+		return sha256.New(), nil
+	case AlgorithmSHA512:
+		return sha512.New(), nil
+	case AlgorithmBLAKE3:
+		return blake3.New(), nil
+	}
+
+	return nil, exception.New(exception.EALGORITHM, "hash", "", fmt.Errorf("%q", a))
+}
+
+// Hash calculates the hex-encoded digest of the file at filepath using the given Algorithm.
+func Hash(filepath string, algorithm Algorithm) *string {
+	h, e := algorithm.New()
+	if e != nil {
+		panic(e)
+	}
+
+	f, e := os.Open(filepath)
+	if e != nil {
+		panic(exception.New(exception.EREAD, "checksum", filepath, e))
+	}
+
+	defer f.Close()
+
+	if _, e := io.Copy(h, f); e != nil {
+		panic(exception.New(exception.EREAD, "checksum", filepath, e))
+	}
+
+	sum := fmt.Sprintf("%x", h.Sum(nil))
+
+	return &sum
+}
@@ -0,0 +1,161 @@
+package checksum
+
+import (
+	"cli/internal/exception"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// DirectoryOptions represents the inputs of a directory digest beyond file paths and contents.
+type DirectoryOptions struct {
+	// Excludes are glob patterns matched against entry names and slash-separated relative paths.
+	Excludes []string
+
+	// Modes includes permission bits in the digest.
+	Modes bool
+
+	// Empty includes empty directories in the digest.
+	Empty bool
+
+	// Key, if set, produces an authenticated (HMAC) directory digest; see Algorithm.Keyed.
+	Key []byte
+}
+
+// Directory calculates a deterministic digest of the directory at path without building a tree:
+// every entry, in sorted relative-path order, contributes a line of its type, relative path, and
+// content digest (files), link target (symbolic links), or nothing (empty directories, if enabled).
+// The digest is suitable as a cache key; it does not depend on timestamps or ownership.
+func Directory(path string, algorithm Algorithm, options DirectoryOptions) (string, error) {
+	h, e := algorithm.New()
+	if options.Key != nil {
+		h, e = algorithm.keyed(options.Key)
+	}
+
+	if e != nil {
+		return "", e
+	}
+
+	// lines are keyed by relative path, as WalkDir's per-directory order differs from the paths' sorted
+	// order, e.g. visiting "a/b" before "a-b"
+	lines := map[string]string{}
+
+	walker := func(current string, entry fs.DirEntry, e error) error {
+		if e != nil {
+			return exception.New(exception.EREAD, "checksum", current, e)
+		}
+
+		relative, e := filepath.Rel(path, current)
+		if e != nil {
+			return e
+		}
+
+		if relative == "." {
+			return nil
+		}
+
+		relative = filepath.ToSlash(relative)
+		if excluded(options.Excludes, entry.Name(), relative) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		info, e := entry.Info()
+		if e != nil {
+			return exception.New(exception.EREAD, "checksum", current, e)
+		}
+
+		mode := ""
+		if options.Modes {
+			mode = fmt.Sprintf(" %04o", info.Mode().Perm())
+		}
+
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			target, e := os.Readlink(current)
+			if e != nil {
+				return exception.New(exception.EREAD, "checksum", current, e)
+			}
+
+			lines[relative] = fmt.Sprintf("l%s %q %q\n", mode, relative, target)
+		case info.IsDir():
+			if options.Empty {
+				entries, e := os.ReadDir(current)
+				if e != nil {
+					return exception.New(exception.EREAD, "checksum", current, e)
+				}
+
+				if len(entries) == 0 {
+					lines[relative] = fmt.Sprintf("d%s %q\n", mode, relative)
+				}
+			}
+		case info.Mode().IsRegular():
+			digest, e := file(current, algorithm)
+			if e != nil {
+				return e
+			}
+
+			lines[relative] = fmt.Sprintf("f%s %q %s\n", mode, relative, digest)
+		}
+
+		return nil
+	}
+
+	if e := filepath.WalkDir(path, walker); e != nil {
+		return "", e
+	}
+
+	paths := make([]string, 0, len(lines))
+	for relative := range lines {
+		paths = append(paths, relative)
+	}
+
+	sort.Strings(paths)
+
+	for _, relative := range paths {
+		io.WriteString(h, lines[relative])
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// file calculates the hex-encoded digest of the file at path, returning rather than panicking on errors.
+func file(path string, algorithm Algorithm) (string, error) {
+	h, e := algorithm.New()
+	if e != nil {
+		return "", e
+	}
+
+	f, e := os.Open(path)
+	if e != nil {
+		return "", exception.New(exception.EREAD, "checksum", path, e)
+	}
+
+	defer f.Close()
+
+	if _, e := io.Copy(h, f); e != nil {
+		return "", exception.New(exception.EREAD, "checksum", path, e)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+func excluded(patterns []string, name, relative string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+
+		if matched, _ := filepath.Match(pattern, relative); matched {
+			return true
+		}
+	}
+
+	return false
+}
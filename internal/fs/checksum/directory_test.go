@@ -0,0 +1,39 @@
+package checksum_test
+
+import (
+	"cli/internal/fs/checksum"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirectoryHashesSortedRelativePaths(t *testing.T) {
+	root := t.TempDir()
+	for _, name := range []string{"a/b", "a-b", "a.b"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if e := os.MkdirAll(filepath.Dir(path), 0o755); e != nil {
+			t.Fatal(e)
+		}
+
+		if e := os.WriteFile(path, []byte(name), 0o644); e != nil {
+			t.Fatal(e)
+		}
+	}
+
+	digest, e := checksum.Directory(root, checksum.AlgorithmSHA256, checksum.DirectoryOptions{})
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// WalkDir visits a/b before a-b and a.b; sorted, it's last
+	h := sha256.New()
+	for _, name := range []string{"a-b", "a.b", "a/b"} {
+		fmt.Fprintf(h, "f %q %x\n", name, sha256.Sum256([]byte(name)))
+	}
+
+	if expected := fmt.Sprintf("%x", h.Sum(nil)); digest != expected {
+		t.Fatalf("digest %s; expected %s, of the sorted relative paths", digest, expected)
+	}
+}
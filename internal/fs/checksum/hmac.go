@@ -0,0 +1,60 @@
+package checksum
+
+import (
+	"cli/internal/exception"
+	"crypto/hmac"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+var ExceptionMissingKey = exception.New(exception.EKEY, "", "", nil)
+
+// Keyed returns the label of an authenticated digest computed with the given Algorithm, e.g. "hmac-sha256".
+func (a Algorithm) Keyed() string {
+	return "hmac-" + string(a)
+}
+
+// keyed returns a constructor of HMAC hashes of the given Algorithm and key.
+func (a Algorithm) keyed(key []byte) (hash.Hash, error) {
+	if len(key) == 0 {
+		return nil, exception.New(exception.EKEY, "hmac", "", nil)
+	}
+
+	if _, e := a.New(); e != nil {
+		return nil, e
+	}
+
+	return hmac.New(func() hash.Hash {
+		h, _ := a.New()
+		return h
+	}, key), nil
+}
+
+// HMAC calculates the hex-encoded, keyed digest of the file at path, so that the digest can't be
+// recomputed, and thus forged, by anyone lacking the key.
+func HMAC(path string, key []byte, algorithm Algorithm) (string, error) {
+	h, e := algorithm.keyed(key)
+	if e != nil {
+		return "", e
+	}
+
+	f, e := os.Open(path)
+	if e != nil {
+		return "", exception.New(exception.EREAD, "hmac", path, e)
+	}
+
+	defer f.Close()
+
+	if _, e := io.Copy(h, f); e != nil {
+		return "", exception.New(exception.EREAD, "hmac", path, e)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// Equal compares two hex-encoded digests in constant time.
+func Equal(a, b string) bool {
+	return hmac.Equal([]byte(a), []byte(b))
+}
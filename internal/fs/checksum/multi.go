@@ -0,0 +1,42 @@
+package checksum
+
+import (
+	"cli/internal/exception"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+// Multi calculates the hex-encoded digests of the file at path for every given Algorithm in a
+// single read pass.
+func Multi(path string, algorithms ...Algorithm) (map[Algorithm]string, error) {
+	hashes := make([]hash.Hash, len(algorithms))
+	writers := make([]io.Writer, len(algorithms))
+	for i, algorithm := range algorithms {
+		h, e := algorithm.New()
+		if e != nil {
+			return nil, e
+		}
+
+		hashes[i], writers[i] = h, h
+	}
+
+	f, e := os.Open(path)
+	if e != nil {
+		return nil, exception.New(exception.EREAD, "checksum", path, e)
+	}
+
+	defer f.Close()
+
+	if _, e := io.Copy(io.MultiWriter(writers...), f); e != nil {
+		return nil, exception.New(exception.EREAD, "checksum", path, e)
+	}
+
+	digests := make(map[Algorithm]string, len(algorithms))
+	for i, algorithm := range algorithms {
+		digests[algorithm] = fmt.Sprintf("%x", hashes[i].Sum(nil))
+	}
+
+	return digests, nil
+}
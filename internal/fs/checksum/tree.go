@@ -0,0 +1,112 @@
+package checksum
+
+import (
+	"cli/internal/exception"
+	"cli/internal/fs/concurrency"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Tree returns the label of a tree digest computed with the given Algorithm over chunk-byte leaves,
+// e.g. "tree-sha256-4194304".
+func (a Algorithm) Tree(chunk int64) string {
+	return "tree-" + string(a) + "-" + strconv.FormatInt(chunk, 10)
+}
+
+// ParseTree parses a tree digest label into its Algorithm and chunk size.
+func ParseTree(label string) (Algorithm, int64, bool) {
+	rest, valid := strings.CutPrefix(strings.ToLower(label), "tree-")
+	if !(valid) {
+		return "", 0, false
+	}
+
+	index := strings.LastIndex(rest, "-")
+	if index < 0 {
+		return "", 0, false
+	}
+
+	chunk, e := strconv.ParseInt(rest[index+1:], 10, 64)
+	if e != nil || chunk <= 0 || !(Algorithm(rest[:index]).Valid()) {
+		return "", 0, false
+	}
+
+	return Algorithm(rest[:index]), chunk, true
+}
+
+// Tree calculates the tree digest of the file at filepath, hashing its chunks in parallel. Each chunk-byte
+// leaf is hashed as H(0x00 || chunk), and the root as H(0x01 || leaves || size), the size a big-endian uint64.
+// The result differs from the file's plain digest and must always be labeled via Algorithm.Tree. Chunks are
+// hashed at the levels of the concurrency Controller c; nil adapts them between one and GOMAXPROCS.
+func Tree(filepath string, algorithm Algorithm, chunk int64, c *concurrency.Controller) *string {
+	sum, e := tree(filepath, algorithm, chunk, c)
+	if e != nil {
+		panic(e)
+	}
+
+	return &sum
+}
+
+func tree(path string, algorithm Algorithm, chunk int64, c *concurrency.Controller) (string, error) {
+	if _, e := algorithm.New(); e != nil {
+		return "", e
+	}
+
+	if c == nil {
+		c = concurrency.New(1, runtime.GOMAXPROCS(0))
+	}
+
+	f, e := os.Open(path)
+	if e != nil {
+		return "", exception.New(exception.EREAD, "checksum", path, e)
+	}
+
+	defer f.Close()
+
+	info, e := f.Stat()
+	if e != nil {
+		return "", exception.New(exception.EREAD, "checksum", path, e)
+	}
+
+	size := info.Size()
+	count := int((size + chunk - 1) / chunk)
+	if count == 0 {
+		count = 1
+	}
+
+	leaves := make([][]byte, count)
+	failures := make([]error, count)
+
+	c.Each(count, func(i int) int64 {
+		h, _ := algorithm.New()
+		h.Write([]byte{0x00})
+
+		n, e := io.Copy(h, io.NewSectionReader(f, int64(i)*chunk, chunk))
+		if e != nil {
+			failures[i] = e
+			return n
+		}
+
+		leaves[i] = h.Sum(nil)
+
+		return n
+	})
+
+	h, _ := algorithm.New()
+	h.Write([]byte{0x01})
+	for i, leaf := range leaves {
+		if failures[i] != nil {
+			return "", exception.New(exception.EREAD, "checksum", path, failures[i])
+		}
+
+		h.Write(leaf)
+	}
+
+	binary.Write(h, binary.BigEndian, uint64(size))
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,37 @@
+package checksum
+
+import (
+	"cli/internal/exception"
+	"fmt"
+	"strings"
+)
+
+var ExceptionLabel = exception.New(exception.ELABEL, "", "", nil)
+
+// Verify reports whether the digest of the file at path matches expected, compared case-insensitively.
+// An expected digest may carry an "<algorithm>:" label, which must then match algorithm, else Verify
+// returns an ELABEL error rather than a mismatch. Tree digest labels, e.g. "tree-sha256-4194304",
+// are verified with the tree construction.
+func Verify(path, expected string, algorithm Algorithm) (bool, error) {
+	if label, digest, labeled := strings.Cut(expected, ":"); labeled {
+		if Algorithm(strings.ToLower(label)) != algorithm {
+			return false, exception.New(exception.ELABEL, "verify", path, fmt.Errorf("labeled %s, verifying with %s", label, algorithm))
+		}
+
+		expected = digest
+	}
+
+	var digest string
+	var e error
+	if base, chunk, valid := ParseTree(string(algorithm)); valid {
+		digest, e = tree(path, base, chunk, nil)
+	} else {
+		digest, e = file(path, algorithm)
+	}
+
+	if e != nil {
+		return false, e
+	}
+
+	return strings.EqualFold(digest, strings.TrimSpace(expected)), nil
+}
@@ -0,0 +1,38 @@
+package checksum_test
+
+import (
+	"cli/internal/fs/checksum"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifyRejectsMismatchedLabels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if e := os.WriteFile(path, []byte("contents"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+
+	digest := *checksum.Hash(path, checksum.AlgorithmSHA256)
+
+	for _, expected := range []string{digest, "sha256:" + digest, "SHA256:" + digest} {
+		if valid, e := checksum.Verify(path, expected, checksum.AlgorithmSHA256); !(valid) || e != nil {
+			t.Errorf("%s: verified %v (%v)", expected, valid, e)
+		}
+	}
+
+	if valid, e := checksum.Verify(path, "sha256:"+digest[1:]+"0", checksum.AlgorithmSHA256); valid || e != nil {
+		t.Errorf("mismatched digest verified %v (%v); expected a mismatch", valid, e)
+	}
+
+	for _, algorithm := range []checksum.Algorithm{checksum.AlgorithmSHA1, "tree-sha256-4096", "whirlpool"} {
+		if _, e := checksum.Verify(path, "sha256:"+digest, algorithm); !(errors.Is(e, checksum.ExceptionLabel)) {
+			t.Errorf("%s: verifying a sha256 digest returned %v; expected ELABEL", algorithm, e)
+		}
+	}
+
+	if _, e := checksum.Verify(path, digest, "whirlpool"); !(errors.Is(e, checksum.ExceptionInvalidAlgorithm)) {
+		t.Errorf("verifying with an unknown algorithm returned %v; expected EALGORITHM", e)
+	}
+}
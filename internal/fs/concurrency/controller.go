@@ -0,0 +1,338 @@
+package concurrency
+
+import (
+	"cli/internal/i18n"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// overhead is the cost, in bytes, charged to every operation on top of the bytes it transfers, so the
+	// throughput of many small files reflects their rate of opens and seeks rather than their few bytes.
+	overhead = 64 << 10
+
+	// samples is the number of operations measured per worker before the level is reconsidered.
+	samples = 4
+
+	// settle is the minimum duration of a measurement window, so a burst of cached reads isn't taken for
+	// the storage's throughput.
+	settle = 20 * time.Millisecond
+
+	// congestion is the factor by which service times may exceed the fastest observed, without throughput
+	// improving, before the storage is considered saturated.
+	congestion = 4
+
+	// gain is the relative change in throughput counted as an improvement, or a decline.
+	gain = 0.1
+
+	// retention is the number of level changes a Report lists.
+	retention = 64
+)
+
+// Reason represents why a Controller changed its level.
+type Reason string
+
+const (
+	// ReasonScaling raises the level, as throughput improved with the last raise.
+	ReasonScaling Reason = "scaling"
+
+	// ReasonCongested halves the level, as service times rose past the storage's baseline: the seeks of
+	// spinning disks, or round trips of network file-systems, queueing behind one another.
+	ReasonCongested Reason = "congested"
+
+	// ReasonDeclining lowers the level, as throughput declined, or didn't improve on the last raise.
+	ReasonDeclining Reason = "declining"
+)
+
+// Change represents a change of a Controller's level, After seconds from its first operation.
+type Change struct {
+	After   float64 `json:"after-seconds"`
+	Workers int     `json:"workers"`
+	Reason  Reason  `json:"reason"`
+}
+
+// Controller bounds the number of concurrent operations to a level between Minimum and Maximum. An adaptive
+// Controller starts at Minimum and probes the storage, doubling the level while throughput improves, then
+// steps it up while parallelism still pays off, and down when throughput declines or a raise didn't pay
+// off, halving it when service times rise past the fastest observed; levels stay low for spinning disks
+// and network file-systems, and climb for NVMe. A Controller is safe for concurrent use.
+type Controller struct {
+	Minimum int
+	Maximum int
+
+	mutex    sync.Mutex
+	cond     *sync.Cond
+	clock    func() time.Time
+	adaptive bool
+	growing  bool
+	raised   bool
+	level    int
+	active   int
+
+	// the current measurement window
+	window     time.Time
+	parked     time.Time
+	operations int
+	cost       int64
+	busy       time.Duration
+
+	baseline float64
+	previous float64
+
+	started     time.Time
+	initial     int
+	lowest      int
+	peak        int
+	count       int
+	bytes       int64
+	latency     time.Duration
+	adjustments int
+	changes     []Change
+}
+
+// New returns an adaptive Controller of levels between minimum and maximum. It panics if minimum isn't
+// positive, or maximum is below minimum.
+func New(minimum, maximum int) *Controller {
+	c := controller(minimum, maximum)
+	c.adaptive, c.growing = true, true
+
+	return c
+}
+
+// Fixed returns a Controller that always runs workers operations at once. It panics if workers isn't positive.
+func Fixed(workers int) *Controller {
+	return controller(workers, workers)
+}
+
+// controller returns a Controller at level minimum.
+func controller(minimum, maximum int) *Controller {
+	if minimum < 1 || maximum < minimum {
+		panic(fmt.Errorf("invalid concurrency levels: %d-%d", minimum, maximum))
+	}
+
+	c := &Controller{Minimum: minimum, Maximum: maximum, level: minimum, initial: minimum, lowest: minimum, peak: minimum, clock: time.Now}
+	c.cond = sync.NewCond(&c.mutex)
+
+	return c
+}
+
+// Level returns the number of operations the Controller currently runs at once.
+func (c *Controller) Level() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.level
+}
+
+// Each calls fn for every index below count, running as many calls at once as the Controller's level
+// allows, and measures each: fn returns the bytes it transferred. A panic in fn stops further calls, and
+// is raised again once the running ones return.
+func (c *Controller) Each(count int, fn func(i int) int64) {
+	var next atomic.Int64
+	var failed atomic.Bool
+	var once sync.Once
+	var failure any
+
+	var group sync.WaitGroup
+	for w := 0; w < min(c.Maximum, count); w++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+
+			for {
+				c.acquire()
+
+				i := int(next.Add(1) - 1)
+				if i >= count || failed.Load() {
+					c.release(0, 0, false)
+					return
+				}
+
+				start := c.clock()
+				bytes, r := call(fn, i)
+				c.release(bytes, c.clock().Sub(start), r == nil)
+
+				if r != nil {
+					once.Do(func() {
+						failure = r
+					})
+
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+
+	group.Wait()
+
+	if failure != nil {
+		panic(failure)
+	}
+}
+
+// call returns the bytes fn(i) transferred, or what it panicked with.
+func call(fn func(i int) int64, i int) (bytes int64, r any) {
+	defer func() {
+		r = recover()
+	}()
+
+	return fn(i), nil
+}
+
+// acquire blocks until fewer operations than the level are running, and starts one; time spent without
+// any running isn't counted towards the measurement window.
+func (c *Controller) acquire() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for c.active >= c.level {
+		c.cond.Wait()
+	}
+
+	now := c.clock()
+	if c.started.IsZero() {
+		c.started, c.window = now, now
+	} else if c.active == 0 && !(c.parked.IsZero()) {
+		c.window = c.window.Add(now.Sub(c.parked))
+	}
+
+	c.active++
+}
+
+// release ends an operation, recording its bytes and latency if it's measured, and reconsiders the level.
+func (c *Controller) release(bytes int64, latency time.Duration, measured bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.clock()
+
+	c.active--
+	if c.active == 0 {
+		c.parked = now
+	}
+
+	if measured {
+		c.operations++
+		c.cost += bytes + overhead
+		c.busy += latency
+
+		c.count++
+		c.bytes += bytes
+		c.latency += latency
+
+		c.adjust(now)
+	}
+
+	c.cond.Broadcast()
+}
+
+// adjust closes the measurement window once it's sampled enough operations, changing the level on
+// comparing its throughput, and service time per byte, with those of the previous and fastest windows.
+func (c *Controller) adjust(now time.Time) {
+	elapsed := now.Sub(c.window)
+	if !(c.adaptive) || c.operations < samples*c.level || elapsed < settle {
+		return
+	}
+
+	rate := float64(c.cost) / elapsed.Seconds()
+	service := c.busy.Seconds() / float64(c.cost)
+	if c.baseline == 0 || service < c.baseline {
+		c.baseline = service
+	}
+
+	improved := c.previous == 0 || rate > c.previous*(1+gain)
+
+	level, reason := c.level, Reason("")
+	switch {
+	case service > congestion*c.baseline && !(improved) && c.level > c.Minimum:
+		level, reason = max(c.level/2, c.Minimum), ReasonCongested
+	case improved && c.level < c.Maximum:
+		if c.growing {
+			level = min(c.level*2, c.Maximum)
+		} else {
+			level = c.level + 1
+		}
+
+		reason = ReasonScaling
+	case (c.raised || rate < c.previous*(1-gain)) && !(improved) && c.level > c.Minimum:
+		level, reason = c.level-1, ReasonDeclining
+	}
+
+	c.raised = level > c.level
+
+	// doubling ends at the first window that doesn't improve on the last
+	c.growing = c.growing && improved
+	c.previous = rate
+	c.window, c.operations, c.cost, c.busy = now, 0, 0, 0
+
+	if level == c.level {
+		return
+	}
+
+	c.level = level
+	c.lowest, c.peak = min(c.lowest, level), max(c.peak, level)
+	c.adjustments++
+
+	c.changes = append(c.changes, Change{After: now.Sub(c.started).Seconds(), Workers: level, Reason: reason})
+	if len(c.changes) > retention {
+		c.changes = c.changes[len(c.changes)-retention:]
+	}
+
+	c.cond.Broadcast()
+}
+
+// Report represents the levels a Controller chose, and the operations it measured; Changes lists the
+// most recent of its Adjustments.
+type Report struct {
+	Adaptive    bool     `json:"adaptive"`
+	Minimum     int      `json:"minimum"`
+	Maximum     int      `json:"maximum"`
+	Initial     int      `json:"initial"`
+	Final       int      `json:"final"`
+	Lowest      int      `json:"lowest"`
+	Peak        int      `json:"peak"`
+	Operations  int      `json:"operations"`
+	Bytes       int64    `json:"bytes"`
+	Latency     float64  `json:"mean-latency-seconds"`
+	Adjustments int      `json:"adjustments"`
+	Changes     []Change `json:"changes,omitempty"`
+}
+
+// Report returns the Report of the Controller so far.
+func (c *Controller) Report() *Report {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	r := &Report{
+		Adaptive:    c.adaptive,
+		Minimum:     c.Minimum,
+		Maximum:     c.Maximum,
+		Initial:     c.initial,
+		Final:       c.level,
+		Lowest:      c.lowest,
+		Peak:        c.peak,
+		Operations:  c.count,
+		Bytes:       c.bytes,
+		Adjustments: c.adjustments,
+		Changes:     append([]Change{}, c.changes...),
+	}
+
+	if c.count > 0 {
+		r.Latency = c.latency.Seconds() / float64(c.count)
+	}
+
+	return r
+}
+
+// String returns the human-facing summary of the Report, e.g. "8 worker(s) (adaptive 1-32, from 1, lowest 1,
+// peak 8, 4 adjustments), 1200 operations at 0.42ms mean latency".
+func (r *Report) String() string {
+	levels := i18n.T(i18n.ConcurrencyFixed)
+	if r.Adaptive {
+		levels = i18n.T(i18n.ConcurrencyAdaptive, r.Minimum, r.Maximum, r.Initial, r.Lowest, r.Peak, r.Adjustments)
+	}
+
+	return i18n.T(i18n.ConcurrencyReport, r.Final, levels, r.Operations, r.Latency*1000)
+}
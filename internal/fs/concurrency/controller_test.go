@@ -0,0 +1,149 @@
+package concurrency
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEachCallsEveryIndexOnce(t *testing.T) {
+	for _, c := range []*Controller{New(1, 8), Fixed(1), Fixed(4)} {
+		calls := make([]atomic.Int32, 500)
+		c.Each(len(calls), func(i int) int64 {
+			calls[i].Add(1)
+			return 1
+		})
+
+		for i := range calls {
+			if n := calls[i].Load(); n != 1 {
+				t.Fatalf("index %d called %d times", i, n)
+			}
+		}
+
+		if r := c.Report(); r.Operations != len(calls) || r.Bytes != int64(len(calls)) {
+			t.Fatalf("report of %d operations, %d bytes; expected %d", r.Operations, r.Bytes, len(calls))
+		}
+	}
+}
+
+func TestEachBoundsConcurrency(t *testing.T) {
+	c := Fixed(3)
+
+	var active, peak atomic.Int32
+	c.Each(60, func(i int) int64 {
+		n := active.Add(1)
+		for {
+			if p := peak.Load(); n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+		active.Add(-1)
+
+		return 0
+	})
+
+	if p := peak.Load(); p != 3 {
+		t.Fatalf("peak of %d concurrent calls; expected 3", p)
+	}
+
+	if r := c.Report(); r.Adaptive || r.Final != 3 || r.Adjustments != 0 {
+		t.Fatalf("fixed controller adjusted: %s", r)
+	}
+}
+
+func TestEachRaisesPanic(t *testing.T) {
+	var calls atomic.Int32
+	defer func() {
+		if r := recover(); r != "failed" {
+			t.Fatalf("recovered %v; expected the panic of fn", r)
+		}
+
+		if n := calls.Load(); n >= 1000 {
+			t.Fatalf("%d calls after the panic", n)
+		}
+	}()
+
+	New(1, 4).Each(1000, func(i int) int64 {
+		calls.Add(1)
+		if i == 10 {
+			panic("failed")
+		}
+
+		time.Sleep(100 * time.Microsecond)
+
+		return 0
+	})
+}
+
+func TestInvalidLevels(t *testing.T) {
+	for _, levels := range [][2]int{{0, 4}, {4, 2}, {-1, -1}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("levels %v didn't panic", levels)
+				}
+			}()
+
+			New(levels[0], levels[1])
+		}()
+	}
+}
+
+// simulate runs operations of 4096 bytes through c on a fake clock, keeping as many outstanding as its level
+// allows: serve returns when a request started at start completes, given when the previous one did. Requests
+// complete in the order they started.
+func simulate(c *Controller, operations int, serve func(start, previous time.Time) time.Time) *Report {
+	now := time.Unix(0, 0)
+	c.clock = func() time.Time {
+		return now
+	}
+
+	var outstanding []time.Time
+	previous := now
+	for i := 0; i < operations; i++ {
+		for len(outstanding) < c.Level() {
+			c.acquire()
+			outstanding = append(outstanding, now)
+		}
+
+		start := outstanding[0]
+		outstanding = outstanding[1:]
+
+		now = serve(start, previous)
+		previous = now
+
+		c.release(4096, now.Sub(start), true)
+	}
+
+	return c.Report()
+}
+
+// TestAdaptiveScalesParallelStorage simulates storage serving concurrent requests at a constant latency,
+// such as NVMe or a network file-system with many outstanding requests.
+func TestAdaptiveScalesParallelStorage(t *testing.T) {
+	r := simulate(New(1, 16), 3000, func(start, _ time.Time) time.Time {
+		return start.Add(time.Millisecond)
+	})
+
+	if r.Peak < 8 || r.Changes[0].Reason != ReasonScaling {
+		t.Fatalf("didn't scale up: %s", r)
+	}
+}
+
+// TestAdaptiveSettlesSerialStorage simulates storage serving one request at a time, such as a spinning
+// disk's single head.
+func TestAdaptiveSettlesSerialStorage(t *testing.T) {
+	r := simulate(New(1, 16), 1000, func(start, previous time.Time) time.Time {
+		if previous.After(start) {
+			start = previous
+		}
+
+		return start.Add(time.Millisecond)
+	})
+
+	if r.Final > 2 || r.Peak > 4 {
+		t.Fatalf("didn't settle low: %s", r)
+	}
+}
@@ -0,0 +1,3 @@
+// Package concurrency adapts the parallelism of IO-bound work, such as hashing and copying files, to the
+// latency and throughput of the storage beneath it.
+package concurrency
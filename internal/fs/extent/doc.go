@@ -0,0 +1,2 @@
+// Package extent represents the physical extents of files, identifying copy-on-write clones that share storage.
+package extent
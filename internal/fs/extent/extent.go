@@ -0,0 +1,24 @@
+package extent
+
+// Extent represents a contiguous range of a file's data on its device. A zero Physical offset is unknown.
+type Extent struct {
+	Logical  uint64 `json:"logical"`
+	Physical uint64 `json:"physical"`
+	Length   uint64 `json:"length"`
+	Shared   bool   `json:"shared,omitempty"`
+}
+
+// Same reports whether a and b map identical, known physical extents: reflinked clones, or links to a single file.
+func Same(a, b []Extent) bool {
+	if len(a) == 0 || len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Physical == 0 || a[i].Logical != b[i].Logical || a[i].Physical != b[i].Physical || a[i].Length != b[i].Length {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,93 @@
+package extent
+
+import (
+	"errors"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// FS_IOC_FIEMAP, and the struct fiemap and fiemap_extent flags, of linux/fiemap.h.
+const (
+	fiemap = 0xC020660B
+
+	flagSync = 0x00000001
+
+	extentLast        = 0x00000001
+	extentUnknown     = 0x00000002
+	extentDelalloc    = 0x00000004
+	extentEncoded     = 0x00000008
+	extentNotAligned  = 0x00000100
+	extentDataInline  = 0x00000200
+	extentDataTail    = 0x00000400
+	extentShared      = 0x00002000
+	extentUnreliable  = extentUnknown | extentDelalloc | extentEncoded | extentNotAligned | extentDataInline | extentDataTail
+	extentsPerRequest = 64
+)
+
+type header struct {
+	start         uint64
+	length        uint64
+	flags         uint32
+	mappedExtents uint32
+	extentCount   uint32
+	reserved      uint32
+}
+
+type record struct {
+	logical    uint64
+	physical   uint64
+	length     uint64
+	reserved64 [2]uint64
+	flags      uint32
+	reserved   [3]uint32
+}
+
+type request struct {
+	header
+	extents [extentsPerRequest]record
+}
+
+// Of returns the extents of the file at path via FIEMAP; file-systems without it return errors.ErrUnsupported.
+// Extents whose location is unknown, pending, or shared with other data (inline, tail-packed) have a zero Physical.
+func Of(path string) ([]Extent, error) {
+	f, e := os.Open(path)
+	if e != nil {
+		return nil, e
+	}
+
+	defer f.Close()
+
+	var extents []Extent
+	var start uint64
+	for {
+		r := request{header: header{start: start, length: ^uint64(0) - start, flags: flagSync, extentCount: extentsPerRequest}}
+		if _, _, errno := unix.Syscall(unix.SYS_IOCTL, f.Fd(), fiemap, uintptr(unsafe.Pointer(&r))); errno != 0 {
+			if errno == unix.EOPNOTSUPP || errno == unix.ENOTTY || errno == unix.EINVAL {
+				return nil, errors.ErrUnsupported
+			}
+
+			return nil, &os.PathError{Op: "fiemap", Path: path, Err: errno}
+		}
+
+		if r.mappedExtents == 0 {
+			return extents, nil
+		}
+
+		for _, x := range r.extents[:r.mappedExtents] {
+			extent := Extent{Logical: x.logical, Physical: x.physical, Length: x.length, Shared: x.flags&extentShared != 0}
+			if x.flags&extentUnreliable != 0 {
+				extent.Physical = 0
+			}
+
+			extents = append(extents, extent)
+
+			if x.flags&extentLast != 0 {
+				return extents, nil
+			}
+
+			start = x.logical + x.length
+		}
+	}
+}
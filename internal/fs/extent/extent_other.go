@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package extent
+
+import "errors"
+
+// Of returns errors.ErrUnsupported; extents are only reported on Linux and macOS.
+func Of(path string) ([]Extent, error) {
+	return nil, errors.ErrUnsupported
+}
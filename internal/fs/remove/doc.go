@@ -0,0 +1,3 @@
+// Package remove represents deletion beneath a root directory that never follows symbolic links,
+// guarding deletions in shared directories against planted symlinks.
+package remove
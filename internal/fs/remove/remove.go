@@ -0,0 +1,85 @@
+package remove
+
+import (
+	"cli/internal/exception"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+var ExceptionSymlink = exception.New(exception.ESYMLINK, "", "", nil)
+
+// Root represents a directory beneath which deletions never traverse, nor delete through, symbolic links.
+// The root's own path is resolved once, when opened.
+type Root struct {
+	// Path is the root directory, as given.
+	Path string
+
+	resolved string
+}
+
+// Open returns the Root of directory path.
+func Open(path string) (*Root, error) {
+	absolute, e := filepath.Abs(path)
+	if e != nil {
+		return nil, e
+	}
+
+	resolved, e := filepath.EvalSymlinks(absolute)
+	if e != nil {
+		return nil, e
+	}
+
+	return &Root{Path: absolute, resolved: resolved}, nil
+}
+
+// Remove deletes the non-directory at path; a symbolic link is deleted itself, never its target.
+func (r *Root) Remove(path string) error {
+	components, e := r.components(path)
+	if e != nil {
+		return e
+	}
+
+	return r.remove(path, components)
+}
+
+// RemoveDirectory deletes the empty directory at path; a symbolic link is refused rather than deleted.
+func (r *Root) RemoveDirectory(path string) error {
+	components, e := r.components(path)
+	if e != nil {
+		return e
+	}
+
+	return r.rmdir(path, components)
+}
+
+// RemoveAll deletes path and everything beneath it, deleting rather than descending into symbolic links.
+// A missing path is not an error.
+func (r *Root) RemoveAll(path string) error {
+	components, e := r.components(path)
+	if e != nil {
+		return e
+	}
+
+	return r.removeAll(path, components)
+}
+
+// components returns path's components relative to the root; the root itself and paths outside it are refused.
+func (r *Root) components(path string) ([]string, error) {
+	absolute, e := filepath.Abs(path)
+	if e != nil {
+		return nil, e
+	}
+
+	relative, e := filepath.Rel(r.Path, absolute)
+	if e != nil || relative == "." || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s is not beneath %s", path, r.Path)
+	}
+
+	return strings.Split(relative, string(filepath.Separator)), nil
+}
+
+func symlink(path string) error {
+	return exception.New(exception.ESYMLINK, "remove", path, errors.New("refusing to traverse a symbolic link"))
+}
@@ -0,0 +1,67 @@
+//go:build !unix
+
+package remove
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// parent refuses symbolic link components beneath the root. Without openat, this is checked before
+// deleting rather than enforced by the deletion itself.
+func (r *Root) parent(components []string) error {
+	current := r.Path
+	for _, component := range components[:len(components)-1] {
+		current = filepath.Join(current, component)
+
+		info, e := os.Lstat(current)
+		if e != nil {
+			return e
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return symlink(current)
+		}
+	}
+
+	return nil
+}
+
+func (r *Root) remove(path string, components []string) error {
+	if e := r.parent(components); e != nil {
+		return e
+	}
+
+	target := filepath.Join(append([]string{r.Path}, components...)...)
+	if info, e := os.Lstat(target); e != nil {
+		return e
+	} else if info.IsDir() {
+		return &os.PathError{Op: "remove", Path: path, Err: os.ErrInvalid}
+	}
+
+	return os.Remove(target)
+}
+
+func (r *Root) rmdir(path string, components []string) error {
+	if e := r.parent(components); e != nil {
+		return e
+	}
+
+	target := filepath.Join(append([]string{r.Path}, components...)...)
+	if info, e := os.Lstat(target); e != nil {
+		return e
+	} else if !(info.IsDir()) {
+		return &os.PathError{Op: "remove", Path: path, Err: os.ErrInvalid}
+	}
+
+	return os.Remove(target)
+}
+
+func (r *Root) removeAll(path string, components []string) error {
+	if e := r.parent(components); e != nil {
+		return e
+	}
+
+	// os.RemoveAll deletes, rather than descends into, symbolic links beneath its path.
+	return os.RemoveAll(filepath.Join(append([]string{r.Path}, components...)...))
+}
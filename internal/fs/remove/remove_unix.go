@@ -0,0 +1,147 @@
+//go:build unix
+
+package remove
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/sys/unix"
+)
+
+// parent opens the directory holding the final component, descending from the root with O_NOFOLLOW
+// so that no intermediate component can be a symbolic link.
+func (r *Root) parent(path string, components []string) (int, error) {
+	fd, e := unix.Open(r.resolved, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_CLOEXEC, 0)
+	if e != nil {
+		return -1, &os.PathError{Op: "open", Path: r.Path, Err: e}
+	}
+
+	current := r.Path
+	for _, component := range components[:len(components)-1] {
+		current = filepath.Join(current, component)
+
+		next, e := unix.Openat(fd, component, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+		if e != nil {
+			var st unix.Stat_t
+			if errors.Is(e, unix.ELOOP) || (unix.Fstatat(fd, component, &st, unix.AT_SYMLINK_NOFOLLOW) == nil && st.Mode&unix.S_IFMT == unix.S_IFLNK) {
+				e = symlink(current)
+			} else {
+				e = &os.PathError{Op: "open", Path: current, Err: e}
+			}
+
+			unix.Close(fd)
+
+			return -1, e
+		}
+
+		unix.Close(fd)
+		fd = next
+	}
+
+	return fd, nil
+}
+
+func (r *Root) remove(path string, components []string) error {
+	fd, e := r.parent(path, components)
+	if e != nil {
+		return e
+	}
+
+	defer unix.Close(fd)
+
+	name := components[len(components)-1]
+
+	// unlinkat refuses directories without AT_REMOVEDIR, and deletes symbolic links themselves.
+	if e := unix.Unlinkat(fd, name, 0); e != nil {
+		if errors.Is(e, unix.EPERM) || errors.Is(e, unix.EISDIR) {
+			var st unix.Stat_t
+			if unix.Fstatat(fd, name, &st, unix.AT_SYMLINK_NOFOLLOW) == nil && st.Mode&unix.S_IFMT == unix.S_IFDIR {
+				e = unix.EISDIR
+			}
+		}
+
+		return &os.PathError{Op: "remove", Path: path, Err: e}
+	}
+
+	return nil
+}
+
+func (r *Root) rmdir(path string, components []string) error {
+	fd, e := r.parent(path, components)
+	if e != nil {
+		return e
+	}
+
+	defer unix.Close(fd)
+
+	// unlinkat with AT_REMOVEDIR refuses symbolic links with ENOTDIR, rather than following them.
+	if e := unix.Unlinkat(fd, components[len(components)-1], unix.AT_REMOVEDIR); e != nil {
+		return &os.PathError{Op: "remove", Path: path, Err: e}
+	}
+
+	return nil
+}
+
+func (r *Root) removeAll(path string, components []string) error {
+	fd, e := r.parent(path, components)
+	if e != nil {
+		return e
+	}
+
+	defer unix.Close(fd)
+
+	return all(fd, components[len(components)-1], path)
+}
+
+// all deletes name beneath the directory fd, descending only into real directories, opened with O_NOFOLLOW.
+func all(parent int, name, path string) error {
+	var st unix.Stat_t
+	if e := unix.Fstatat(parent, name, &st, unix.AT_SYMLINK_NOFOLLOW); e != nil {
+		if errors.Is(e, unix.ENOENT) {
+			return nil
+		}
+
+		return &os.PathError{Op: "lstat", Path: path, Err: e}
+	}
+
+	if st.Mode&unix.S_IFMT != unix.S_IFDIR {
+		if e := unix.Unlinkat(parent, name, 0); e != nil && !(errors.Is(e, unix.ENOENT)) {
+			return &os.PathError{Op: "remove", Path: path, Err: e}
+		}
+
+		return nil
+	}
+
+	fd, e := unix.Openat(parent, name, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if e != nil {
+		if errors.Is(e, unix.ELOOP) {
+			return symlink(path)
+		}
+
+		return &os.PathError{Op: "open", Path: path, Err: e}
+	}
+
+	directory := os.NewFile(uintptr(fd), path)
+	names, e := directory.Readdirnames(-1)
+	if e == nil {
+		for _, child := range names {
+			if e = all(fd, child, filepath.Join(path, child)); e != nil {
+				break
+			}
+		}
+	}
+
+	directory.Close()
+
+	if e != nil {
+		return e
+	}
+
+	if e := unix.Unlinkat(parent, name, unix.AT_REMOVEDIR); e != nil && !(errors.Is(e, unix.ENOENT)) {
+		return &os.PathError{Op: "remove", Path: path, Err: e}
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package tree
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Body writes the tree as a mactime(1) body file (The Sleuth Kit 3.x format), one line per Node:
+//
+//	MD5|name|inode|mode_as_string|UID|GID|size|atime|mtime|ctime|crtime
+//
+// Timestamps are in seconds since the epoch, zero where unknown; trees walked WithForensic carry all of them.
+func (n *Node) Body(w io.Writer) error {
+	buffer := bufio.NewWriter(w)
+
+	var e error
+	n.Each(func(node *Node) bool {
+		if e == nil {
+			_, e = fmt.Fprintln(buffer, node.body())
+		}
+
+		return e == nil
+	})
+
+	if e != nil {
+		return e
+	}
+
+	return buffer.Flush()
+}
+
+// body returns the Node's body file line.
+func (n *Node) body() string {
+	md5 := "0"
+	if n.Checksum != nil && n.Algorithm == "md5" {
+		md5 = *n.Checksum
+	}
+
+	name := n.Path
+	if n.Type == Symbolic {
+		name += " -> " + n.Target
+	}
+
+	var owner Owner
+	if n.Owner != nil {
+		owner = *n.Owner
+	}
+
+	var born *time.Time
+	accessed, modified, changed := time.Time{}, n.Modified, time.Time{}
+	if n.Times != nil {
+		accessed, modified, changed, born = n.Times.Accessed, n.Times.Modified, n.Times.Changed, n.Times.Born
+	}
+
+	crtime := int64(0)
+	if born != nil {
+		crtime = epoch(*born)
+	}
+
+	return strings.Join([]string{
+		md5,
+		strings.ReplaceAll(name, "|", "\\|"),
+		fmt.Sprint(n.Inode),
+		n.kind(),
+		fmt.Sprint(owner.UID),
+		fmt.Sprint(owner.GID),
+		fmt.Sprint(n.Size),
+		fmt.Sprint(epoch(accessed)),
+		fmt.Sprint(epoch(modified)),
+		fmt.Sprint(epoch(changed)),
+		fmt.Sprint(crtime),
+	}, "|")
+}
+
+// kind returns the Node's mode_as_string: its fls(1) type prefix and symbolic mode, e.g. "r/rrw-r--r--".
+// Trees walked without WithForensic carry no mode, e.g. "r/r---------".
+func (n *Node) kind() string {
+	letter := "r"
+	switch n.Type {
+	case Directory:
+		letter = "d"
+	case Symbolic:
+		letter = "l"
+	}
+
+	mode := "---------"
+	if len(n.Mode) == 10 {
+		mode = n.Mode[1:]
+	}
+
+	return letter + "/" + letter + mode
+}
+
+// epoch returns t in seconds since the epoch, zero for the zero time.
+func epoch(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
+// symbolic returns the ls(1)-style representation of mode, e.g. "-rwsr-xr-x".
+func symbolic(mode os.FileMode) string {
+	buffer := []byte("----------")
+
+	switch {
+	case mode&os.ModeDir != 0:
+		buffer[0] = 'd'
+	case mode&os.ModeSymlink != 0:
+		buffer[0] = 'l'
+	case mode&os.ModeNamedPipe != 0:
+		buffer[0] = 'p'
+	case mode&os.ModeSocket != 0:
+		buffer[0] = 's'
+	case mode&os.ModeCharDevice != 0:
+		buffer[0] = 'c'
+	case mode&os.ModeDevice != 0:
+		buffer[0] = 'b'
+	}
+
+	const rwx = "rwxrwxrwx"
+	for i := 0; i < 9; i++ {
+		if mode&(1<<uint(8-i)) != 0 {
+			buffer[i+1] = rwx[i]
+		}
+	}
+
+	special := []struct {
+		bit              os.FileMode
+		index            int
+		executable, flag byte
+	}{
+		{os.ModeSetuid, 3, 's', 'S'},
+		{os.ModeSetgid, 6, 's', 'S'},
+		{os.ModeSticky, 9, 't', 'T'},
+	}
+
+	for _, s := range special {
+		if mode&s.bit == 0 {
+			continue
+		}
+
+		if buffer[s.index] != '-' {
+			buffer[s.index] = s.executable
+		} else {
+			buffer[s.index] = s.flag
+		}
+	}
+
+	return string(buffer)
+}
@@ -0,0 +1,112 @@
+package tree
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Priority represents the order in which a budgeted copy's files claim its byte budget.
+type Priority string
+
+const (
+	// PrioritySmallest copies the smallest files first, fitting the most files within the budget.
+	PrioritySmallest Priority = "smallest"
+
+	// PriorityLargest copies the largest files first.
+	PriorityLargest Priority = "largest"
+
+	// PriorityNewest copies the most recently modified files first.
+	PriorityNewest Priority = "newest"
+
+	// PriorityOldest copies the least recently modified files first.
+	PriorityOldest Priority = "oldest"
+
+	// PriorityPath copies files in path order.
+	PriorityPath Priority = "path"
+)
+
+// Priorities returns all supported copy priorities.
+func Priorities() []Priority {
+	return []Priority{PrioritySmallest, PriorityLargest, PriorityNewest, PriorityOldest, PriorityPath}
+}
+
+// Valid reports whether the Priority is supported.
+func (p Priority) Valid() bool {
+	for _, priority := range Priorities() {
+		if p == priority {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithMaxBytes limits the bytes of file contents each copy writes to limit: files claim the budget in
+// priority order, and those that no longer fit are omitted, listed in Omitted, while smaller files
+// of lower priority may still fill the remainder. Directories are copied regardless. It panics if
+// priority isn't Valid.
+func WithMaxBytes(limit int64, priority Priority) Option {
+	if !(priority.Valid()) {
+		panic(fmt.Errorf("invalid copy priority: %q", priority))
+	}
+
+	return func(o *Options) {
+		o.MaxBytes = limit
+		o.Priority = priority
+	}
+}
+
+// Omitted returns the paths of the files the tree's last copy omitted to stay within its WithMaxBytes budget.
+func (n *Node) Omitted() []string {
+	return n.options.omitted
+}
+
+// budget returns the files within the WithMaxBytes budget, in their given order, recording the others
+// as omitted. Without a budget, every file is returned.
+func (o *Options) budget(files []*Node) []*Node {
+	o.omitted = nil
+	if o.MaxBytes <= 0 {
+		return files
+	}
+
+	ranked := make([]int, len(files))
+	for i := range ranked {
+		ranked[i] = i
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := files[ranked[i]], files[ranked[j]]
+		switch o.Priority {
+		case PrioritySmallest:
+			return a.Size < b.Size
+		case PriorityLargest:
+			return a.Size > b.Size
+		case PriorityNewest:
+			return a.Modified.After(b.Modified)
+		case PriorityOldest:
+			return a.Modified.Before(b.Modified)
+		}
+
+		return false
+	})
+
+	kept := make([]bool, len(files))
+	remaining := o.MaxBytes
+	for _, i := range ranked {
+		if files[i].Size <= remaining {
+			kept[i] = true
+			remaining -= files[i].Size
+		}
+	}
+
+	within := make([]*Node, 0, len(files))
+	for i, file := range files {
+		if kept[i] {
+			within = append(within, file)
+		} else {
+			o.omitted = append(o.omitted, file.Path)
+		}
+	}
+
+	return within
+}
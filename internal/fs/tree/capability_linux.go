@@ -0,0 +1,100 @@
+//go:build linux
+
+package tree
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// capability is the extended attribute holding a file's capability sets.
+const capability = "security.capability"
+
+// the vfs_cap_data revisions and flags of linux/capability.h.
+const (
+	revisionMask = 0xff000000
+	revision1    = 0x01000000
+	revision2    = 0x02000000
+	revision3    = 0x03000000
+	effective    = 0x000001
+)
+
+// capabilityNames are the capability names of linux/capability.h, by bit.
+var capabilityNames = []string{
+	"cap_chown", "cap_dac_override", "cap_dac_read_search", "cap_fowner", "cap_fsetid", "cap_kill",
+	"cap_setgid", "cap_setuid", "cap_setpcap", "cap_linux_immutable", "cap_net_bind_service",
+	"cap_net_broadcast", "cap_net_admin", "cap_net_raw", "cap_ipc_lock", "cap_ipc_owner", "cap_sys_module",
+	"cap_sys_rawio", "cap_sys_chroot", "cap_sys_ptrace", "cap_sys_pacct", "cap_sys_admin", "cap_sys_boot",
+	"cap_sys_nice", "cap_sys_resource", "cap_sys_time", "cap_sys_tty_config", "cap_mknod", "cap_lease",
+	"cap_audit_write", "cap_audit_control", "cap_setfcap", "cap_mac_override", "cap_mac_admin", "cap_syslog",
+	"cap_wake_alarm", "cap_block_suspend", "cap_audit_read", "cap_perfmon", "cap_bpf", "cap_checkpoint_restore",
+}
+
+// capabilities returns the file capabilities of path in getcap(8) notation, e.g. "cap_net_bind_service=ep",
+// or an empty string if it has none.
+func capabilities(path string) string {
+	buffer := make([]byte, 24)
+
+	size, e := unix.Lgetxattr(path, capability, buffer)
+	if e != nil || size < 4 {
+		return ""
+	}
+
+	buffer = buffer[:size]
+	magic := binary.LittleEndian.Uint32(buffer)
+
+	words := 0
+	switch magic & revisionMask {
+	case revision1:
+		words = 1
+	case revision2, revision3:
+		words = 2
+	}
+
+	if words == 0 || size < 4+8*words {
+		return ""
+	}
+
+	var permitted, inheritable uint64
+	for i := 0; i < words; i++ {
+		permitted |= uint64(binary.LittleEndian.Uint32(buffer[4+8*i:])) << (32 * i)
+		inheritable |= uint64(binary.LittleEndian.Uint32(buffer[8+8*i:])) << (32 * i)
+	}
+
+	// group capabilities sharing the same sets, e.g. "cap_net_admin,cap_net_raw=ep"
+	var order []string
+	groups := map[string][]string{}
+	for bit := range capabilityNames {
+		sets := ""
+		if magic&effective != 0 && permitted&(1<<bit) != 0 {
+			sets += "e"
+		}
+
+		if inheritable&(1<<bit) != 0 {
+			sets += "i"
+		}
+
+		if permitted&(1<<bit) != 0 {
+			sets += "p"
+		}
+
+		if sets == "" {
+			continue
+		}
+
+		if _, valid := groups[sets]; !(valid) {
+			order = append(order, sets)
+		}
+
+		groups[sets] = append(groups[sets], capabilityNames[bit])
+	}
+
+	partials := make([]string, 0, len(order))
+	for _, sets := range order {
+		partials = append(partials, strings.Join(groups[sets], ",")+"="+sets)
+	}
+
+	return strings.Join(partials, " ")
+}
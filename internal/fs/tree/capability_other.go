@@ -0,0 +1,8 @@
+//go:build !linux
+
+package tree
+
+// capabilities returns the file capabilities of path; unsupported on this platform.
+func capabilities(path string) string {
+	return ""
+}
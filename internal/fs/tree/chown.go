@@ -0,0 +1,69 @@
+package tree
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ParseOwner parses a "user", "user:group", or ":group" specification, by name or numeric identifier,
+// into the Owner applied by WithChown; an omitted user or group is -1, leaving it unchanged.
+func ParseOwner(specification string) (*Owner, error) {
+	name, group, _ := strings.Cut(specification, ":")
+	if name == "" && group == "" {
+		return nil, fmt.Errorf("invalid owner: %q", specification)
+	}
+
+	o := &Owner{UID: -1, GID: -1}
+
+	if name != "" {
+		if id, e := strconv.Atoi(name); e == nil {
+			o.UID = id
+		} else if u, e := user.Lookup(name); e != nil {
+			return nil, fmt.Errorf("unknown user: %s", name)
+		} else if o.UID, e = strconv.Atoi(u.Uid); e != nil {
+			return nil, fmt.Errorf("unsupported user identifier: %s", u.Uid)
+		}
+	}
+
+	if group != "" {
+		if id, e := strconv.Atoi(group); e == nil {
+			o.GID = id
+		} else if g, e := user.LookupGroup(group); e != nil {
+			return nil, fmt.Errorf("unknown group: %s", group)
+		} else if o.GID, e = strconv.Atoi(g.Gid); e != nil {
+			return nil, fmt.Errorf("unsupported group identifier: %s", g.Gid)
+		}
+	}
+
+	return o, nil
+}
+
+// WithChown assigns owner to everything a copy materializes, like tar --owner and --group,
+// rather than the copying user.
+func WithChown(owner *Owner) Option {
+	return func(o *Options) {
+		o.Chown = owner
+	}
+}
+
+// chown assigns the Options' Chown owner, if any, to target, never following a symbolic link.
+func (o *Options) chown(target string) {
+	if o.Chown == nil {
+		return
+	}
+
+	if e := os.Lchown(target, o.Chown.UID, o.Chown.GID); e != nil {
+		panic(e)
+	}
+}
+
+// own assigns the Options' Chown owner to the nodes materialized at destination.
+func (o *Options) own(destination string, nodes []*Node) {
+	for _, node := range nodes {
+		o.chown(filepath.Join(destination, node.Path))
+	}
+}
@@ -0,0 +1,99 @@
+package tree
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+)
+
+// TreeJSON writes the tree in the exact schema and layout of GNU tree's -J output: nested directory,
+// file, and link objects holding only their type, name, and link target, followed by a report of the
+// directory and file totals. As tree does, the root is named for the walked path, isn't counted, and
+// links to directories count as directories.
+func (n *Node) TreeJSON(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+
+	var directories, files int
+
+	var entry func(node *Node, name string, level int)
+	entry = func(node *Node, name string, level int) {
+		indent := strings.Repeat("  ", level)
+
+		writer.WriteString(indent)
+		switch node.Type {
+		case Directory:
+			writer.WriteString(`{"type":"directory","name":`)
+			writer.Write(quote(Escape(name)))
+			writer.WriteString(`,"contents":[`)
+			writer.WriteByte('\n')
+
+			children := node.Children()
+			for i, child := range children {
+				if child.Type == Directory {
+					directories++
+				}
+
+				entry(child, child.Name, level+1)
+				if i < len(children)-1 {
+					writer.WriteByte(',')
+				}
+
+				writer.WriteByte('\n')
+			}
+
+			writer.WriteString(indent)
+			writer.WriteString("]}")
+		case Symbolic:
+			if info, e := os.Stat(node.Path); e == nil && info.IsDir() {
+				directories++
+			} else {
+				files++
+			}
+
+			writer.WriteString(`{"type":"link","name":`)
+			writer.Write(quote(Escape(name)))
+			writer.WriteString(`,"target":`)
+			writer.Write(quote(Escape(node.Target)))
+			writer.WriteByte('}')
+		default:
+			files++
+
+			writer.WriteString(`{"type":"file","name":`)
+			writer.Write(quote(Escape(name)))
+			writer.WriteByte('}')
+		}
+	}
+
+	writer.WriteString("[\n")
+	entry(n, n.Path, 1)
+	writer.WriteString("\n,\n")
+
+	report, e := json.Marshal(struct {
+		Type        string `json:"type"`
+		Directories int    `json:"directories"`
+		Files       int    `json:"files"`
+	}{"report", directories, files})
+	if e != nil {
+		return e
+	}
+
+	writer.WriteString("  ")
+	writer.Write(report)
+	writer.WriteString("\n]\n")
+
+	return writer.Flush()
+}
+
+// quote returns name as a JSON string, without the HTML escaping of json.Marshal.
+func quote(name string) []byte {
+	var buffer bytes.Buffer
+
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(name)
+
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n"))
+}
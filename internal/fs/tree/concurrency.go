@@ -0,0 +1,98 @@
+package tree
+
+import (
+	"cli/internal/fs/concurrency"
+	"os"
+	"path/filepath"
+)
+
+// WithConcurrency hashes the walk's files concurrently, once they're all walked, at the levels c chooses;
+// c's level also bounds the chunks of tree digests, hashed one file at a time. Without it, files are
+// hashed one at a time as they're walked.
+func WithConcurrency(c *concurrency.Controller) Option {
+	return func(o *Options) {
+		o.Concurrency = c
+	}
+}
+
+// WithCopyConcurrency writes the files of the tree's copies concurrently, at the levels c chooses.
+func WithCopyConcurrency(c *concurrency.Controller) Option {
+	return func(o *Options) {
+		o.CopyConcurrency = c
+	}
+}
+
+// deferred represents a file whose hashing the walk deferred WithConcurrency, and its information from
+// before it was read, verified WithReadOnly once it's hashed.
+type deferred struct {
+	file   *Node
+	before os.FileInfo
+}
+
+// digest hashes the files the walk deferred WithConcurrency: tree-digested files last, one at a time, as
+// their chunks are hashed at the same Controller's level.
+func (o *Options) digest() {
+	pending := o.pending
+	if len(pending) == 0 {
+		return
+	}
+
+	o.pending = nil
+
+	var files, chunked []deferred
+	for _, d := range pending {
+		sampled := o.SampleThreshold > 0 && d.file.Size > o.SampleThreshold
+		if !(sampled) && o.ParallelThreshold > 0 && d.file.Size > o.ParallelThreshold {
+			chunked = append(chunked, d)
+		} else {
+			files = append(files, d)
+		}
+	}
+
+	o.Concurrency.Each(len(files), func(i int) int64 {
+		file := files[i].file
+		o.hash(files[i])
+
+		if o.SampleThreshold > 0 && file.Size > o.SampleThreshold {
+			return min(file.Size, 2*o.SampleSize)
+		}
+
+		return file.Size
+	})
+
+	for _, d := range chunked {
+		o.hash(d)
+	}
+}
+
+// hash hashes the deferred file, then verifies it's unmodified since it was walked.
+func (o *Options) hash(d deferred) {
+	d.file.hash()
+	o.verify(d.file.Path, d.before)
+	o.Progress.Advance(1, d.file.Size)
+}
+
+// write materializes files beneath destination, concurrently WithCopyConcurrency, returning those written
+// in the order given.
+func (n *Node) write(destination string, files []*Node) []*Node {
+	materialized := make([]bool, len(files))
+	if c := n.options.CopyConcurrency; c != nil {
+		c.Each(len(files), func(i int) int64 {
+			materialized[i] = n.materialize(files[i], filepath.Join(destination, files[i].Path))
+			return files[i].Size
+		})
+	} else {
+		for i, file := range files {
+			materialized[i] = n.materialize(file, filepath.Join(destination, file.Path))
+		}
+	}
+
+	written := make([]*Node, 0, len(files))
+	for i, file := range files {
+		if materialized[i] {
+			written = append(written, file)
+		}
+	}
+
+	return written
+}
@@ -0,0 +1,155 @@
+package tree_test
+
+import (
+	"bytes"
+	"cli/internal/fs/checksum"
+	"cli/internal/fs/concurrency"
+	"cli/internal/fs/tree"
+	"cli/internal/fs/tree/treetest"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// files returns the contents of a fixture of count files across ten directories.
+func files(count int) map[string]string {
+	contents := make(map[string]string, count)
+	for i := 0; i < count; i++ {
+		contents[fmt.Sprintf("d%d/f%03d", i%10, i)] = fmt.Sprintf("contents of file %d\n", i)
+	}
+
+	return contents
+}
+
+func TestConcurrentHashingMatchesSequential(t *testing.T) {
+	fixture := files(300)
+	fixture["large"] = string(bytes.Repeat([]byte("x"), 3<<20))
+
+	f := treetest.New(t, fixture)
+	parallel := tree.WithParallelHashing(1<<20, 256<<10)
+
+	sequential, e := tree.Walk(f.Root, parallel)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	c := concurrency.New(1, 8)
+	concurrent, e := tree.Walk(f.Root, parallel, tree.WithConcurrency(c))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if sequential.JSON() != concurrent.JSON() {
+		t.Fatal("concurrent walk differs from the sequential walk")
+	}
+
+	if r := c.Report(); r.Operations == 0 {
+		t.Fatalf("nothing hashed concurrently: %s", r)
+	}
+}
+
+// modifier is the Faults of a file appended to as it's read, as by a concurrent writer.
+type modifier struct {
+	path string
+	once sync.Once
+}
+
+func (m *modifier) Inject(op tree.Operation, path string, bytes int64) error {
+	if op == tree.OperationRead && path == m.path {
+		m.once.Do(func() {
+			f, e := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+			if e == nil {
+				f.WriteString("modified\n")
+				f.Close()
+			}
+		})
+	}
+
+	return nil
+}
+
+func TestReadOnlyCatchesChangesDuringConcurrentHashing(t *testing.T) {
+	f := treetest.New(t, files(50))
+	path := filepath.Join(f.Root, "d7", "f017")
+
+	for _, c := range []*concurrency.Controller{nil, concurrency.New(1, 4)} {
+		if e := os.Chtimes(path, treetest.Epoch, treetest.Epoch); e != nil {
+			t.Fatal(e)
+		}
+
+		_, e := tree.Walk(f.Root, tree.WithReadOnly(), tree.WithFaults(&modifier{path: path}), tree.WithConcurrency(c))
+
+		if !(errors.Is(e, tree.ExceptionReadOnly)) {
+			t.Fatalf("walk with concurrency %v returned %v; expected EREADONLY", c != nil, e)
+		}
+	}
+}
+
+func TestConcurrentCopy(t *testing.T) {
+	f := treetest.New(t, files(300))
+
+	c := concurrency.New(1, 8)
+	source, e := tree.Walk(f.Root, tree.WithCopyConcurrency(c))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	destination := t.TempDir()
+	if e := source.Transact(destination, (*tree.Node).Copy); e != nil {
+		t.Fatal(e)
+	}
+
+	for _, file := range source.FilesRecursive() {
+		copied, e := os.ReadFile(filepath.Join(destination, file.Path))
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		if original, _ := os.ReadFile(file.Path); !(bytes.Equal(original, copied)) {
+			t.Fatalf("%s copied incorrectly", file.Path)
+		}
+	}
+
+	if r := c.Report(); r.Operations != 300 {
+		t.Fatalf("%d files copied concurrently; expected 300", r.Operations)
+	}
+}
+
+func TestConcurrentHashingLandsOnNodes(t *testing.T) {
+	f := treetest.New(t, files(100))
+
+	n, e := tree.Walk(f.Root, tree.WithConcurrency(concurrency.New(1, 8)), tree.WithDigests(checksum.AlgorithmMD5))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var compare func(n *tree.Node)
+	compare = func(n *tree.Node) {
+		children := n.Children()
+		if len(children) != len(n.Nodes) {
+			t.Fatalf("%s: %d children of %d nodes", n.Path, len(children), len(n.Nodes))
+		}
+
+		for i, child := range children {
+			node := &n.Nodes[i]
+			if child != node {
+				t.Errorf("%s: Children and Nodes hold different nodes", child.Path)
+			}
+
+			if child.Type == tree.File && (node.Checksum == nil || node.Algorithm == "" || len(node.Digests) != 1) {
+				t.Errorf("%s: unhashed in Nodes", node.Path)
+			}
+
+			if n.At(child.Path) != node {
+				t.Errorf("%s: At and Nodes hold different nodes", child.Path)
+			}
+
+			compare(node)
+		}
+	}
+
+	compare(n)
+}
@@ -0,0 +1,150 @@
+package tree
+
+import (
+	"bytes"
+	"cli/internal/exception"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	ExceptionTooLarge Exception = exception.New(exception.ETOOLARGE, "", "", nil)
+	ExceptionBinary   Exception = exception.New(exception.EBINARY, "", "", nil)
+)
+
+// sniff is how many leading bytes are inspected when detecting binary content.
+const sniff = 8000
+
+// ContentOptions represents the guards applied when reading a Node's contents.
+type ContentOptions struct {
+	// MaxBytes is the largest file size read. Zero means unlimited.
+	MaxBytes int64
+
+	// RejectBinary rejects files whose leading bytes contain NUL characters.
+	RejectBinary bool
+}
+
+// ContentOption configures Contents.
+type ContentOption func(o *ContentOptions)
+
+// MaxBytes rejects files larger than n bytes with an ETOOLARGE error.
+func MaxBytes(n int64) ContentOption {
+	return func(o *ContentOptions) {
+		o.MaxBytes = n
+	}
+}
+
+// RejectBinary rejects binary files with an EBINARY error.
+func RejectBinary() ContentOption {
+	return func(o *ContentOptions) {
+		o.RejectBinary = true
+	}
+}
+
+// Contents returns a Node of Type File's file contents.
+//
+//   - Contents returns an ETOOLARGE error, without reading, for files larger than MaxBytes.
+//   - Contents returns an EBINARY error for binary files if RejectBinary is set, reading only their leading bytes.
+//   - See Open for streaming consumers.
+func (n *Node) Contents(settings ...ContentOption) ([]byte, error) {
+	if n == nil {
+		return nil, ExceptionNilNode
+	} else if n.Type != File {
+		return nil, exception.New(exception.ENOTFILE, "contents", n.Path, nil)
+	}
+
+	o := &ContentOptions{}
+	for _, setting := range settings {
+		setting(o)
+	}
+
+	if o.MaxBytes > 0 {
+		info, e := os.Stat(n.URI())
+		if e != nil {
+			return nil, exception.New(exception.EREAD, "contents", n.Path, e)
+		}
+
+		if info.Size() > o.MaxBytes {
+			return nil, exception.New(exception.ETOOLARGE, "contents", n.Path, fmt.Errorf("%d bytes exceeds the limit of %d", info.Size(), o.MaxBytes))
+		}
+	}
+
+	if e := n.read(o.RejectBinary); e != nil {
+		return nil, e
+	}
+
+	if o.RejectBinary && Binary(n.content) {
+		return nil, exception.New(exception.EBINARY, "contents", n.Path, nil)
+	}
+
+	return n.content, nil
+}
+
+// Open opens a Node of Type File for streaming reads; the caller must close the returned reader.
+func (n *Node) Open() (io.ReadCloser, error) {
+	if n == nil {
+		return nil, ExceptionNilNode
+	} else if n.Type != File {
+		return nil, exception.New(exception.ENOTFILE, "open", n.Path, nil)
+	}
+
+	if e := n.options.inject(OperationRead, n.URI(), n.Size); e != nil {
+		return nil, exception.New(exception.EREAD, "open", n.Path, e)
+	}
+
+	f, e := os.Open(n.URI())
+	if e != nil {
+		return nil, exception.New(exception.EREAD, "open", n.Path, e)
+	}
+
+	return f, nil
+}
+
+// Binary reports whether the leading bytes of buffer contain a NUL character.
+func Binary(buffer []byte) bool {
+	if len(buffer) > sniff {
+		buffer = buffer[:sniff]
+	}
+
+	return bytes.IndexByte(buffer, 0) >= 0
+}
+
+// read will read-in the Node file-contents if of Type File. With reject, a binary file is rejected with an
+// EBINARY error once its leading bytes are read, without reading, nor caching, the rest.
+func (n *Node) read(reject bool) error {
+	if n == nil || n.Type != File || n.content != nil {
+		return nil
+	}
+
+	if e := n.options.inject(OperationRead, n.URI(), n.Size); e != nil {
+		return exception.New(exception.EREAD, "read", n.Path, e)
+	}
+
+	f, e := os.Open(n.URI())
+	if e != nil {
+		return exception.New(exception.EREAD, "read", n.Path, e)
+	}
+
+	defer f.Close()
+
+	head := make([]byte, sniff)
+	count, e := io.ReadFull(f, head)
+	if e != nil && !(errors.Is(e, io.EOF)) && !(errors.Is(e, io.ErrUnexpectedEOF)) {
+		return exception.New(exception.EREAD, "read", n.Path, e)
+	}
+
+	if reject && Binary(head[:count]) {
+		return exception.New(exception.EBINARY, "contents", n.Path, nil)
+	}
+
+	buffer := bytes.NewBuffer(head[:count])
+	if _, e := buffer.ReadFrom(f); e != nil {
+		return exception.New(exception.EREAD, "read", n.Path, e)
+	}
+
+	n.content = buffer.Bytes()
+
+	return nil
+}
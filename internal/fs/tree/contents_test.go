@@ -0,0 +1,41 @@
+package tree_test
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/fs/tree/treetest"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContentsRejectsBinaryByLeadingBytes(t *testing.T) {
+	f := treetest.New(t, map[string]string{
+		"binary": "\x00" + strings.Repeat("x", 1<<20),
+		"late":   strings.Repeat("x", 8000) + "\x00",
+		"empty":  "",
+	})
+
+	n, e := tree.Walk(f.Root)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	files := n.Map()
+
+	binary := files[filepath.Join(f.Root, "binary")]
+	if _, e := binary.Contents(tree.RejectBinary()); !(errors.Is(e, tree.ExceptionBinary)) {
+		t.Fatalf("binary file read with %v; expected EBINARY", e)
+	}
+
+	// a rejected file isn't cached, so is read whole once binary content is accepted
+	if contents, e := binary.Contents(); e != nil || len(contents) != 1<<20+1 {
+		t.Fatalf("read %d bytes of the binary file (%v)", len(contents), e)
+	}
+
+	for _, name := range []string{"late", "empty"} {
+		if _, e := files[filepath.Join(f.Root, name)].Contents(tree.RejectBinary()); e != nil {
+			t.Errorf("%s: %v", name, e)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package tree
+
+import (
+	"fmt"
+)
+
+// Contexts represents how SELinux security contexts are captured and applied on copy. AppArmor confines
+// by path rather than by file label, so there is nothing of it to capture or preserve.
+type Contexts string
+
+const (
+	ContextsNone     Contexts = "none"
+	ContextsPreserve Contexts = "preserve"
+	ContextsDefault  Contexts = "default"
+)
+
+// selinux is the extended attribute holding a file's SELinux security context.
+const selinux = "security.selinux"
+
+// Valid reports whether the Contexts policy is supported.
+func (c Contexts) Valid() bool {
+	switch c {
+	case ContextsNone, ContextsPreserve, ContextsDefault:
+		return true
+	}
+
+	return false
+}
+
+// WithContexts captures the security context of every Node, and on copy either restores it
+// (ContextsPreserve) or explicitly applies the destination directory's default label (ContextsDefault).
+func WithContexts(policy Contexts) Option {
+	return func(o *Options) {
+		if !(policy.Valid()) {
+			panic(fmt.Errorf("unsupported contexts policy: %s", policy))
+		}
+
+		o.Contexts = policy
+	}
+}
+
+// labeled reports whether security contexts are captured.
+func (o *Options) labeled() bool {
+	return o.Contexts != "" && o.Contexts != ContextsNone
+}
+
+// label applies the security context policy to target, the copy of the Node; fallback is the
+// destination's default label.
+func (n *Node) label(target, fallback string) error {
+	switch n.options.Contexts {
+	case ContextsPreserve:
+		if n.Context != "" {
+			return setcontext(target, n.Context)
+		}
+	case ContextsDefault:
+		if fallback != "" {
+			return setcontext(target, fallback)
+		}
+	}
+
+	return nil
+}
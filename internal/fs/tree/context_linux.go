@@ -0,0 +1,38 @@
+//go:build linux
+
+package tree
+
+import (
+	"bytes"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// context returns the SELinux security context of path, or an empty string where unlabeled.
+func context(path string) string {
+	buffer := make([]byte, 256)
+
+	size, e := unix.Lgetxattr(path, selinux, buffer)
+	if e == unix.ERANGE {
+		if size, e = unix.Lgetxattr(path, selinux, nil); e == nil {
+			buffer = make([]byte, size)
+			size, e = unix.Lgetxattr(path, selinux, buffer)
+		}
+	}
+
+	if e != nil {
+		return ""
+	}
+
+	return string(bytes.TrimRight(buffer[:size], "\x00"))
+}
+
+// setcontext sets the SELinux security context of path.
+func setcontext(path, context string) error {
+	if e := unix.Lsetxattr(path, selinux, append([]byte(context), 0), 0); e != nil {
+		return &os.PathError{Op: "setxattr " + selinux, Path: path, Err: e}
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package tree
+
+// context returns the SELinux security context of path; unsupported on this platform.
+func context(path string) string {
+	return ""
+}
+
+// setcontext sets the SELinux security context of path; unsupported on this platform.
+func setcontext(path, context string) error {
+	return nil
+}
@@ -0,0 +1,168 @@
+package tree
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Derivation computes a derived field of a Node, e.g. a parsed AST or a lint result.
+type Derivation func(n *Node) (any, error)
+
+// derivation represents a derived field's memoized value, computed once.
+type derivation struct {
+	once     sync.Once
+	computed atomic.Bool
+	value    any
+	e        error
+}
+
+// memo represents a Node's derived fields, allocated with the Node as it joins a walked or decoded tree, so
+// that no lock beyond its own guards them.
+type memo struct {
+	mutex  sync.Mutex
+	fields map[string]*derivation
+}
+
+// memo returns the Node's derived fields. A Node built outside of a walk or decoding allocates them on first
+// use, unguarded, so mustn't be derived concurrently.
+func (n *Node) memo() *memo {
+	if n.derived == nil {
+		n.derived = &memo{}
+	}
+
+	return n.derived
+}
+
+// Derive returns the Node's derived field name, computing it with fn on first use. The value, or error,
+// including a panic's, is memoized: later calls, including concurrent ones, which wait for the first to
+// finish, return it without calling fn. Derived fields are serialized, under "derived", once computed
+// without error.
+func (n *Node) Derive(name string, fn Derivation) (any, error) {
+	m := n.memo()
+
+	m.mutex.Lock()
+	d, valid := m.fields[name]
+	if !(valid) {
+		if m.fields == nil {
+			m.fields = map[string]*derivation{}
+		}
+
+		d = &derivation{}
+		m.fields[name] = d
+	}
+	m.mutex.Unlock()
+
+	d.once.Do(func() {
+		defer d.computed.Store(true)
+
+		// a panicking fn is memoized as its error, rather than crashing DeriveAll's workers
+		defer func() {
+			if r := recover(); r != nil {
+				if e, valid := r.(error); valid {
+					d.e = e
+				} else {
+					d.e = fmt.Errorf("%v", r)
+				}
+			}
+		}()
+
+		d.value, d.e = fn(n)
+	})
+
+	return d.value, d.e
+}
+
+// Derived returns the Node's memoized derived field name, without computing it; false if it wasn't
+// derived, or is still being computed.
+func (n *Node) Derived(name string) (value any, valid bool) {
+	values := n.derivations()
+
+	value, valid = values[name]
+
+	return
+}
+
+// Forget drops the Node's memoized derived fields of the given names, or all of them if none are given,
+// so that they're recomputed after the Node changes.
+func (n *Node) Forget(names ...string) {
+	m := n.memo()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if len(names) == 0 {
+		m.fields = nil
+	}
+
+	for _, name := range names {
+		delete(m.fields, name)
+	}
+}
+
+// DeriveAll derives the field name, with fn, of the Node and every descendant for which selected returns
+// true, nil selecting all, with up to workers calls of fn in parallel. Every Node is derived regardless
+// of failures, whose errors are joined and returned.
+func (n *Node) DeriveAll(name string, fn Derivation, selected func(n *Node) bool, workers int) error {
+	var nodes []*Node
+	n.Each(func(node *Node) bool {
+		if selected == nil || selected(node) {
+			nodes = append(nodes, node)
+		}
+
+		return true
+	})
+
+	failures := make([]error, len(nodes))
+
+	indices := make(chan int)
+	var group sync.WaitGroup
+	for w := 0; w < min(max(workers, 1), len(nodes)); w++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+
+			for i := range indices {
+				if _, e := nodes[i].Derive(name, fn); e != nil {
+					failures[i] = fmt.Errorf("derive %s of %s: %w", name, nodes[i].Path, e)
+				}
+			}
+		}()
+	}
+
+	for i := range nodes {
+		indices <- i
+	}
+
+	close(indices)
+	group.Wait()
+
+	return errors.Join(failures...)
+}
+
+// derivations returns the Node's derived fields computed without error, or nil if there are none.
+func (n *Node) derivations() map[string]any {
+	m := n.derived
+	if m == nil {
+		return nil
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	var values map[string]any
+	for name, d := range m.fields {
+		if !(d.computed.Load()) || d.e != nil {
+			continue
+		}
+
+		if values == nil {
+			values = map[string]any{}
+		}
+
+		values[name] = d.value
+	}
+
+	return values
+}
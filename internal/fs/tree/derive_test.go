@@ -0,0 +1,55 @@
+package tree_test
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/fs/tree/treetest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestDeriveComputesOncePerNode(t *testing.T) {
+	f := treetest.New(t, files(64))
+
+	n, e := tree.Walk(f.Root)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	var calls atomic.Int64
+	size := func(node *tree.Node) (any, error) {
+		calls.Add(1)
+		return node.Size, nil
+	}
+
+	var group sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+
+			if e := n.DeriveAll("size", size, nil, 8); e != nil {
+				t.Error(e)
+			}
+		}()
+	}
+
+	group.Wait()
+
+	count := 0
+	n.Each(func(*tree.Node) bool {
+		count++
+		return true
+	})
+
+	if int(calls.Load()) != count {
+		t.Fatalf("derived %d times for %d nodes", calls.Load(), count)
+	}
+
+	// the index and the tree hold the same derived fields
+	for _, file := range n.FilesRecursive() {
+		if value, valid := n.At(file.Path).Derived("size"); !(valid) || value != file.Size {
+			t.Fatalf("%s: derived %v through At", file.Path, value)
+		}
+	}
+}
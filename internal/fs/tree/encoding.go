@@ -0,0 +1,275 @@
+package tree
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Encoding represents how a Node is serialized to JSON and YAML.
+type Encoding struct {
+	// OmitEmpty omits attributes holding their zero value, rather than serializing them as null or empty.
+	OmitEmpty bool
+}
+
+// DefaultEncoding is the Encoding of nodes without an explicit WithEncoding option.
+var DefaultEncoding = Encoding{OmitEmpty: true}
+
+// WithEncoding sets the Encoding of every Node in the tree.
+func WithEncoding(encoding Encoding) Option {
+	return func(o *Options) {
+		o.Encoding = &encoding
+	}
+}
+
+// field represents a single serialized Node attribute.
+type field struct {
+	key   string
+	value any
+}
+
+// fields returns the Node's serialized attributes in their document order.
+func (n *Node) fields() []field {
+	var modified any
+	if !(n.Modified.IsZero()) {
+		modified = n.Modified.Format(time.RFC3339Nano)
+	}
+
+	return []field{
+		{"path", Escape(n.Path)},
+		{"dirname", Escape(n.Dirname)},
+		{"name", Escape(n.Name)},
+		{"raw", n.raw()},
+		{"type", n.Type},
+		{"target", Escape(n.Target)},
+		{"size", n.Size},
+		{"modified", modified},
+		{"times", n.Times},
+		{"inode", n.Inode},
+		{"mode", n.Mode},
+		{"owner", n.Owner},
+		{"flags", n.Flags},
+		{"xattrs", n.Xattrs},
+		{"context", n.Context},
+		{"capabilities", n.Capabilities},
+		{"tags", n.Tags},
+		{"checksum", n.Checksum},
+		{"algorithm", n.Algorithm},
+		{"digests", n.Digests},
+		{"annotations", n.Annotations},
+		{"derived", n.derivations()},
+		{"errors", n.Errors},
+	}
+}
+
+// encoding returns the Node's Encoding.
+func (n *Node) encoding() Encoding {
+	if n.options != nil && n.options.Encoding != nil {
+		return *n.options.Encoding
+	}
+
+	return DefaultEncoding
+}
+
+func empty(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	case reflect.Pointer:
+		return v.IsNil()
+	}
+
+	return v.IsZero()
+}
+
+func (n *Node) MarshalJSON() ([]byte, error) {
+	encoding := n.encoding()
+
+	var buffer bytes.Buffer
+	if e := n.attributes(&buffer); e != nil {
+		return nil, e
+	}
+
+	if children := n.Children(); len(children) > 0 || !(encoding.OmitEmpty) {
+		if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+
+		buffer.WriteString(`"nodes":[`)
+		for i, child := range children {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+
+			value, e := child.MarshalJSON()
+			if e != nil {
+				return nil, e
+			}
+
+			buffer.Write(value)
+		}
+
+		buffer.WriteByte(']')
+	}
+
+	buffer.WriteByte('}')
+
+	return buffer.Bytes(), nil
+}
+
+// attributes writes the opening brace and the Node's serialized attributes, without its nodes, to buffer.
+func (n *Node) attributes(buffer *bytes.Buffer) error {
+	encoding := n.encoding()
+
+	buffer.WriteByte('{')
+	for _, f := range n.fields() {
+		if encoding.OmitEmpty && empty(f.value) {
+			continue
+		}
+
+		value, e := json.Marshal(f.value)
+		if e != nil {
+			return e
+		}
+
+		if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(f.key)
+		buffer.Write(key)
+		buffer.WriteByte(':')
+		buffer.Write(value)
+	}
+
+	return nil
+}
+
+func (n *Node) MarshalYAML() (any, error) {
+	encoding := n.encoding()
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, f := range n.fields() {
+		if encoding.OmitEmpty && empty(f.value) {
+			continue
+		}
+
+		value := &yaml.Node{}
+		if e := value.Encode(f.value); e != nil {
+			return nil, e
+		}
+
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.key}, value)
+	}
+
+	if children := n.Children(); len(children) > 0 || !(encoding.OmitEmpty) {
+		sequence := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, child := range children {
+			value, e := child.MarshalYAML()
+			if e != nil {
+				return nil, e
+			}
+
+			sequence.Content = append(sequence.Content, value.(*yaml.Node))
+		}
+
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "nodes"}, sequence)
+	}
+
+	return mapping, nil
+}
+
+// record is the decoding representation of a Node.
+type record Node
+
+// encoded is the decoding representation of a Node along with the raw bytes of its escaped attributes.
+type encoded struct {
+	*record `yaml:",inline"`
+
+	Raw map[string]string `json:"raw" yaml:"raw"`
+}
+
+func (n *Node) UnmarshalJSON(buffer []byte) error {
+	value := encoded{record: (*record)(n)}
+	if e := json.Unmarshal(buffer, &value); e != nil {
+		return e
+	}
+
+	if e := n.restore(value.Raw); e != nil {
+		return e
+	}
+
+	n.link()
+
+	return nil
+}
+
+func (n *Node) UnmarshalYAML(value *yaml.Node) error {
+	decoded := encoded{record: (*record)(n)}
+	if e := value.Decode(&decoded); e != nil {
+		return e
+	}
+
+	if e := n.restore(decoded.Raw); e != nil {
+		return e
+	}
+
+	n.link()
+
+	return nil
+}
+
+// link rebuilds the parent, depth, table, and count relations of a decoded Node's subtree.
+func (n *Node) link() {
+	n.table = map[string]*Node{}
+	n.derived = &memo{}
+	n.counts = counts{}
+	if n.parent == nil {
+		n.depth = 0
+		n.lookup = map[string]*Node{}
+		n.misses = &misses{}
+	}
+
+	root := n.Root()
+	for i := range n.Nodes {
+		child := &n.Nodes[i]
+		child.parent = n
+		child.depth = n.depth + 1
+		child.position = i
+		child.options = n.options
+
+		n.table[child.Path] = child
+		root.lookup[child.Path] = child
+
+		child.link()
+
+		n.counts.tally(child)
+	}
+}
+
+func (n *Node) JSON() string {
+	buffer, e := json.MarshalIndent(n, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (n *Node) YAML() string {
+	buffer, e := yaml.Marshal(n)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
@@ -0,0 +1,54 @@
+package tree
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// CacheTag is the file name marking a directory as holding regenerable cache data, per the
+// Cache Directory Tagging Specification.
+const CacheTag = "CACHEDIR.TAG"
+
+// signature is the header a valid CacheTag begins with.
+var signature = []byte("Signature: 8a477f597d28d172789f06886806bc55")
+
+// WithStandardExclusions honors the backup-exclusion conventions of mature backup tools: directories
+// containing a valid CacheTag, and files and directories flagged nodump are excluded.
+func WithStandardExclusions() Option {
+	return func(o *Options) {
+		o.StandardExclusions = true
+	}
+}
+
+// cached reports whether directory contains a valid CacheTag.
+func cached(directory string) bool {
+	f, e := os.Open(filepath.Join(directory, CacheTag))
+	if e != nil {
+		return false
+	}
+
+	defer f.Close()
+
+	header := make([]byte, len(signature))
+	if _, e := io.ReadFull(f, header); e != nil {
+		return false
+	}
+
+	return bytes.Equal(header, signature)
+}
+
+// dumpless reports whether the standard exclusions exclude the entry at path: a nodump flag, or a
+// directory's CacheTag.
+func (o *Options) dumpless(path string, entry os.DirEntry) bool {
+	if !(o.StandardExclusions) {
+		return false
+	}
+
+	if entry.Type()&os.ModeSymlink == 0 && (entry.IsDir() || entry.Type().IsRegular()) && nodump(path) {
+		return true
+	}
+
+	return entry.IsDir() && cached(path)
+}
@@ -0,0 +1,69 @@
+package tree
+
+import (
+	"io/fs"
+	"os"
+)
+
+// Operation represents a file-system operation of walks, hashes, and copies that Faults intercept.
+type Operation string
+
+const (
+	// OperationList lists a walked directory.
+	OperationList Operation = "list"
+
+	// OperationStat stats a walked directory entry.
+	OperationStat Operation = "stat"
+
+	// OperationRead reads a file, to hash it or for its Contents.
+	OperationRead Operation = "read"
+
+	// OperationWrite writes a copied file.
+	OperationWrite Operation = "write"
+
+	// OperationMkdir creates a copied directory.
+	OperationMkdir Operation = "mkdir"
+)
+
+// Faults intercepts the file-system operations of a tree, for testing error handling without broken
+// disks; see package treetest.
+type Faults interface {
+	// Inject is called before op is performed on path, transferring bytes (a file's size for reads and
+	// writes, otherwise zero). A non-nil error fails the operation as though the file-system returned it;
+	// Inject may also delay the operation by blocking.
+	Inject(op Operation, path string, bytes int64) error
+}
+
+// WithFaults intercepts the tree's file-system operations with faults.
+func WithFaults(faults Faults) Option {
+	return func(o *Options) {
+		o.Faults = faults
+	}
+}
+
+// inject returns the error faults inject into op on path, if any.
+func (o *Options) inject(op Operation, path string, bytes int64) error {
+	if o == nil || o.Faults == nil {
+		return nil
+	}
+
+	return o.Faults.Inject(op, path, bytes)
+}
+
+// list lists directory, unless faults inject an error.
+func (o *Options) list(directory string) ([]fs.DirEntry, error) {
+	if e := o.inject(OperationList, directory, 0); e != nil {
+		return nil, e
+	}
+
+	return os.ReadDir(directory)
+}
+
+// stat returns the information of entry at path, unless faults inject an error.
+func (o *Options) stat(path string, entry fs.DirEntry) (fs.FileInfo, error) {
+	if e := o.inject(OperationStat, path, 0); e != nil {
+		return nil, e
+	}
+
+	return entry.Info()
+}
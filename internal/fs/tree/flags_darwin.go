@@ -0,0 +1,61 @@
+//go:build darwin
+
+package tree
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// names maps st_flags bits to their chflags(1) names.
+var names = []struct {
+	bit  uint32
+	name string
+}{
+	{unix.UF_NODUMP, "nodump"},
+	{unix.UF_IMMUTABLE, "uchg"},
+	{unix.UF_APPEND, "uappnd"},
+	{unix.UF_OPAQUE, "opaque"},
+	{unix.UF_COMPRESSED, "compressed"},
+	{unix.UF_TRACKED, "tracked"},
+	{unix.UF_HIDDEN, "hidden"},
+	{unix.SF_ARCHIVED, "arch"},
+	{unix.SF_IMMUTABLE, "schg"},
+	{unix.SF_APPEND, "sappnd"},
+	{unix.SF_RESTRICTED, "restricted"},
+}
+
+// flags returns the chflags(1) names of the file flags of path.
+func flags(path string) []string {
+	var stat unix.Stat_t
+	if e := unix.Lstat(path, &stat); e != nil {
+		return nil
+	}
+
+	var partials []string
+	for _, flag := range names {
+		if stat.Flags&flag.bit != 0 {
+			partials = append(partials, flag.name)
+		}
+	}
+
+	return partials
+}
+
+// restore copies the extended attributes, the capabilities only if capabilities is set, then the
+// user-settable file flags, of source onto target.
+func restore(source, target string, capabilities bool) error {
+	if e := copyxattrs(source, target, capabilities); e != nil {
+		return e
+	}
+
+	var stat unix.Stat_t
+	if e := unix.Lstat(source, &stat); e != nil {
+		return e
+	}
+
+	if settable := stat.Flags & unix.UF_SETTABLE; settable != 0 {
+		return unix.Chflags(target, int(settable))
+	}
+
+	return nil
+}
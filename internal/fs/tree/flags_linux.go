@@ -0,0 +1,69 @@
+//go:build linux
+
+package tree
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// the inode flags of linux/fs.h.
+const (
+	flagCompress  = 0x00000004
+	flagSync      = 0x00000008
+	flagImmutable = 0x00000010
+	flagAppend    = 0x00000020
+	flagNoDump    = 0x00000040
+	flagNoAtime   = 0x00000080
+	flagDirSync   = 0x00010000
+	flagNoCOW     = 0x00800000
+)
+
+// names maps inode flag bits to their chattr(1) names.
+var names = []struct {
+	bit  uint32
+	name string
+}{
+	{flagCompress, "compress"},
+	{flagSync, "sync"},
+	{flagImmutable, "immutable"},
+	{flagAppend, "append"},
+	{flagNoDump, "nodump"},
+	{flagNoAtime, "noatime"},
+	{flagDirSync, "dirsync"},
+	{flagNoCOW, "nocow"},
+}
+
+// inode returns the inode flags of the regular file or directory at path.
+func inode(path string) (uint32, error) {
+	fd, e := unix.Open(path, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if e != nil {
+		return 0, e
+	}
+
+	defer unix.Close(fd)
+
+	return unix.IoctlGetUint32(fd, unix.FS_IOC_GETFLAGS)
+}
+
+// flags returns the chattr(1) names of the inode flags of path.
+func flags(path string) []string {
+	bits, e := inode(path)
+	if e != nil {
+		return nil
+	}
+
+	var partials []string
+	for _, flag := range names {
+		if bits&flag.bit != 0 {
+			partials = append(partials, flag.name)
+		}
+	}
+
+	return partials
+}
+
+// restore copies the extended attributes of source onto target, the capabilities only if capabilities is set.
+// Inode flags aren't restored, as the immutable and append-only flags require CAP_LINUX_IMMUTABLE.
+func restore(source, target string, capabilities bool) error {
+	return copyxattrs(source, target, capabilities)
+}
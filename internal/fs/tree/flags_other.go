@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package tree
+
+// flags returns the names of the file flags of path; unsupported on this platform.
+func flags(path string) []string {
+	return nil
+}
+
+// restore copies the extended attributes of source onto target, the capabilities only if capabilities is set.
+func restore(source, target string, capabilities bool) error {
+	return copyxattrs(source, target, capabilities)
+}
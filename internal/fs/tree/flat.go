@@ -0,0 +1,69 @@
+package tree
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strconv"
+)
+
+// JSONL writes one self-contained JSON object per line for each file of the tree, in path order:
+// every attribute of the file, with its full path, but no nesting.
+func (n *Node) JSONL(w io.Writer) error {
+	var buffer bytes.Buffer
+	for _, file := range n.FilesRecursive() {
+		buffer.Reset()
+
+		if e := file.attributes(&buffer); e != nil {
+			return e
+		}
+
+		buffer.WriteString("}\n")
+
+		if _, e := w.Write(buffer.Bytes()); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// entry represents a Node of the flat index of an Indexed document.
+type entry struct {
+	ID      int        `json:"id"`
+	Parent  *int       `json:"parent"`
+	Path    string     `json:"path"`
+	Type    Descriptor `json:"type"`
+	Depth   int        `json:"depth"`
+	Pointer string     `json:"pointer"`
+}
+
+// Indexed returns the JSON document of both representations of the tree: the nested tree, as JSON,
+// under "tree", and a flat "index" of every Node in depth-first pre-order. Each entry's id is its
+// position in the index, its parent the id of its parent (null for the root), and its pointer the
+// RFC 6901 JSON Pointer of the Node within the document, for random access without descending.
+func (n *Node) Indexed() string {
+	var index []entry
+
+	var collect func(node *Node, parent *int, pointer string)
+	collect = func(node *Node, parent *int, pointer string) {
+		id := len(index)
+		index = append(index, entry{ID: id, Parent: parent, Path: Escape(node.Path), Type: node.Type, Depth: node.depth - n.depth, Pointer: pointer})
+
+		for i, child := range node.Children() {
+			collect(child, &id, pointer+"/nodes/"+strconv.Itoa(i))
+		}
+	}
+
+	collect(n, nil, "/tree")
+
+	buffer, e := json.MarshalIndent(struct {
+		Tree  *Node   `json:"tree"`
+		Index []entry `json:"index"`
+	}{n, index}, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
@@ -0,0 +1,108 @@
+package tree
+
+import (
+	"cli/internal/exception"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var ExceptionImmutable = exception.New(exception.EIMMUTABLE, "", "", nil)
+
+// WithHandleImmutable clears the immutable and append-only flags of a Replace's destination, and
+// reapplies them to the replaced paths, rather than failing.
+func WithHandleImmutable() Option {
+	return func(o *Options) {
+		o.HandleImmutable = true
+	}
+}
+
+// lock represents an immutable or append-only path and its locking flag bits.
+type lock struct {
+	path string
+	bits uint32
+}
+
+// String returns the path along with the flags locking it, e.g. "/srv/a (immutable, append-only)".
+func (l lock) String() string {
+	var partials []string
+	if l.bits&lockImmutable != 0 {
+		partials = append(partials, "immutable")
+	}
+
+	if l.bits&lockAppend != 0 {
+		partials = append(partials, "append-only")
+	}
+
+	return fmt.Sprintf("%s (%s)", l.path, strings.Join(partials, ", "))
+}
+
+// locks returns the immutable or append-only paths beneath, and including, root; a missing root has none.
+func locks(root string) ([]lock, error) {
+	var partials []lock
+
+	e := filepath.WalkDir(root, func(path string, entry fs.DirEntry, e error) error {
+		if e != nil {
+			return e
+		}
+
+		if !(entry.IsDir()) && !(entry.Type().IsRegular()) {
+			return nil
+		}
+
+		if bits := locked(path); bits != 0 {
+			partials = append(partials, lock{path: path, bits: bits})
+		}
+
+		return nil
+	})
+
+	if errors.Is(e, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	return partials, e
+}
+
+// unlock clears the locking flags of the destination's locks: directly, with HandleImmutable, or else
+// by failing with an ExceptionImmutable listing the locked paths.
+func (o *Options) unlock(destination string, locked []lock) error {
+	if len(locked) == 0 {
+		return nil
+	}
+
+	if !(o.HandleImmutable) {
+		listed := make([]string, 0, len(locked))
+		for _, l := range locked {
+			listed = append(listed, l.String())
+		}
+
+		return exception.New(exception.EIMMUTABLE, "replace", destination, errors.New(strings.Join(listed, "; ")))
+	}
+
+	for _, l := range locked {
+		if e := setlock(l.path, l.bits, false); e != nil {
+			return exception.New(exception.EIMMUTABLE, "replace", l.path, e)
+		}
+	}
+
+	return nil
+}
+
+// relock reapplies the locking flags of locked onto the paths that still exist.
+func relock(locked []lock) error {
+	for _, l := range locked {
+		if _, e := os.Lstat(l.path); e != nil {
+			continue
+		}
+
+		if e := setlock(l.path, l.bits, true); e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+//go:build darwin
+
+package tree
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// the locking flags: chflags uchg/schg and uappnd/sappnd.
+const (
+	lockImmutable = unix.UF_IMMUTABLE | unix.SF_IMMUTABLE
+	lockAppend    = unix.UF_APPEND | unix.SF_APPEND
+)
+
+// locked returns the locking flag bits of path.
+func locked(path string) uint32 {
+	var stat unix.Stat_t
+	if e := unix.Lstat(path, &stat); e != nil {
+		return 0
+	}
+
+	return stat.Flags & (lockImmutable | lockAppend)
+}
+
+// setlock sets, or clears, the locking flag bits of path; the system flags require root.
+func setlock(path string, bits uint32, set bool) error {
+	var stat unix.Stat_t
+	if e := unix.Lstat(path, &stat); e != nil {
+		return e
+	}
+
+	current := stat.Flags
+	if set {
+		current |= bits
+	} else {
+		current &^= bits
+	}
+
+	return unix.Chflags(path, int(current))
+}
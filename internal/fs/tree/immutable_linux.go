@@ -0,0 +1,46 @@
+//go:build linux
+
+package tree
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// the locking flags: chattr +i and +a.
+const (
+	lockImmutable = flagImmutable
+	lockAppend    = flagAppend
+)
+
+// locked returns the locking flag bits of path.
+func locked(path string) uint32 {
+	bits, e := inode(path)
+	if e != nil {
+		return 0
+	}
+
+	return bits & (lockImmutable | lockAppend)
+}
+
+// setlock sets, or clears, the locking flag bits of path; either requires CAP_LINUX_IMMUTABLE.
+func setlock(path string, bits uint32, set bool) error {
+	fd, e := unix.Open(path, unix.O_RDONLY|unix.O_NONBLOCK|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if e != nil {
+		return e
+	}
+
+	defer unix.Close(fd)
+
+	current, e := unix.IoctlGetUint32(fd, unix.FS_IOC_GETFLAGS)
+	if e != nil {
+		return e
+	}
+
+	if set {
+		current |= bits
+	} else {
+		current &^= bits
+	}
+
+	return unix.IoctlSetPointerInt(fd, unix.FS_IOC_SETFLAGS, int(current))
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package tree
+
+const (
+	lockImmutable = 1 << iota
+	lockAppend
+)
+
+// locked returns the locking flag bits of path; unsupported on this platform.
+func locked(path string) uint32 {
+	return 0
+}
+
+// setlock sets, or clears, the locking flag bits of path; unsupported on this platform.
+func setlock(path string, bits uint32, set bool) error {
+	return nil
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package tree
+
+import (
+	"os"
+)
+
+// inumber returns the inode number of the file described by info; unsupported on this platform.
+func inumber(info os.FileInfo) uint64 {
+	return 0
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package tree
+
+import (
+	"os"
+	"syscall"
+)
+
+// inumber returns the inode number of the file described by info, if available.
+func inumber(info os.FileInfo) uint64 {
+	if stat, valid := info.Sys().(*syscall.Stat_t); valid {
+		return uint64(stat.Ino)
+	}
+
+	return 0
+}
@@ -0,0 +1,99 @@
+package tree
+
+import (
+	"path/filepath"
+	"sync"
+)
+
+// negatives bounds how many failed lookups are cached before the cache is reset.
+const negatives = 1 << 16
+
+// misses represents a tree's cache of Searches matching nothing, shared by its nodes, so repeatedly searching
+// for absent descriptors costs a map read rather than a scan of the children. It's safe for concurrent use.
+type misses struct {
+	mutex sync.RWMutex
+	keys  map[string]struct{}
+}
+
+func (m *misses) has(key string) bool {
+	if m == nil {
+		return false
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	_, valid := m.keys[key]
+
+	return valid
+}
+
+func (m *misses) add(key string) {
+	if m == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.keys == nil || len(m.keys) >= negatives {
+		m.keys = map[string]struct{}{}
+	}
+
+	m.keys[key] = struct{}{}
+}
+
+// forget empties the cache; cheap when already empty.
+func (m *misses) forget() {
+	if m == nil {
+		return
+	}
+
+	m.mutex.RLock()
+	empty := len(m.keys) == 0
+	m.mutex.RUnlock()
+
+	if empty {
+		return
+	}
+
+	m.mutex.Lock()
+	m.keys = nil
+	m.mutex.Unlock()
+}
+
+// At returns the Node at path, absolute or relative to the Node, anywhere in the Node's tree; nil if there's none.
+// Lookups are a single read of the root's index, so failed ones aren't cached.
+func (n *Node) At(path string) *Node {
+	return n.Root().resolve(n, path)
+}
+
+// resolve returns the Node of the root's tree at path, relative to n unless absolute.
+func (root *Node) resolve(n *Node, path string) *Node {
+	candidate := filepath.Join(n.Path, path)
+	if filepath.IsAbs(path) {
+		candidate = filepath.Clean(path)
+
+		// a tree walked from a relative path is indexed by relative paths
+		if !(filepath.IsAbs(root.Path)) {
+			relative, e := filepath.Rel(root.URI(), candidate)
+			if e != nil {
+				return nil
+			}
+
+			candidate = filepath.Join(root.Path, relative)
+		}
+	}
+
+	if candidate == filepath.Clean(root.Path) {
+		return root
+	}
+
+	return root.lookup[candidate]
+}
+
+// Invalidate empties the tree's cache of Searches matching nothing, e.g. once a watcher has seen the file system change
+// under a tree that will be re-walked.
+func (n *Node) Invalidate() {
+	n.Root().misses.forget()
+}
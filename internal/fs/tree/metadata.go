@@ -0,0 +1,74 @@
+package tree
+
+import (
+	"path/filepath"
+)
+
+// WithMetadata captures file flags (e.g. macOS hidden or Finder flags) and the names of extended
+// attributes (e.g. resource forks and com.apple.* attributes), and preserves both on copy.
+func WithMetadata() Option {
+	return func(o *Options) {
+		o.Metadata = true
+	}
+}
+
+// capture records the file capabilities of the Node, and its file flags, extended attribute names,
+// and security context, if enabled.
+func (n *Node) capture() {
+	if n.Type == Symbolic {
+		return
+	} else if n.Type == File {
+		n.Capabilities = capabilities(n.Path)
+	}
+
+	if n.options.labeled() {
+		n.Context = context(n.Path)
+	}
+
+	if !(n.options.Metadata) {
+		return
+	}
+
+	n.Flags = flags(n.Path)
+
+	names, e := xattrs(n.Path)
+	if e != nil {
+		n.Errors = append(n.Errors, e.Error())
+	}
+
+	n.Xattrs = names
+}
+
+// preserve applies the security context policy, and restores the metadata, of the copied files and
+// directories onto their copies beneath destination, if enabled: files first, then directories deepest-first,
+// so restored immutable flags don't block the copy's own writes.
+func (n *Node) preserve(destination string, files, directories []*Node) {
+	if !(n.options.Metadata) && !(n.options.labeled()) {
+		return
+	}
+
+	fallback := context(filepath.Dir(filepath.Join(destination, n.Path)))
+
+	nodes := make([]*Node, 0, len(files)+len(directories)+1)
+	nodes = append(nodes, files...)
+	for i := len(directories) - 1; i >= 0; i-- {
+		nodes = append(nodes, directories[i])
+	}
+
+	nodes = append(nodes, n)
+
+	for _, node := range nodes {
+		target := filepath.Join(destination, node.Path)
+		if e := node.label(target, fallback); e != nil {
+			panic(e)
+		}
+
+		if !(n.options.Metadata) {
+			continue
+		}
+
+		if e := restore(node.Path, target, n.options.PreserveSpecial || n.options.PreserveCapabilities); e != nil {
+			panic(e)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package tree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Rule represents a permissions rule: copied files whose name, or path relative to the copied root, match
+// Pattern are created with Mode, rather than their source's permissions. Patterns ending in "/" match
+// directories instead.
+type Rule struct {
+	Pattern string
+	Mode    os.FileMode
+}
+
+// ParseRule parses a "PATTERN=MODE" rule, its mode in octal, e.g. "*.sh=0755" or "secrets/*=0600".
+func ParseRule(specification string) (Rule, error) {
+	index := strings.LastIndex(specification, "=")
+	if index <= 0 {
+		return Rule{}, fmt.Errorf("invalid mode rule %q (expected PATTERN=MODE)", specification)
+	}
+
+	pattern, octal := specification[:index], specification[index+1:]
+	if _, e := filepath.Match(strings.TrimSuffix(pattern, "/"), ""); e != nil {
+		return Rule{}, fmt.Errorf("invalid mode rule %q: %w", specification, e)
+	}
+
+	bits, e := strconv.ParseUint(octal, 8, 32)
+	if e != nil || bits > 0o7777 {
+		return Rule{}, fmt.Errorf("invalid mode rule %q: %q isn't an octal mode", specification, octal)
+	}
+
+	mode := os.FileMode(bits) & os.ModePerm
+	for bit, flag := range map[uint64]os.FileMode{0o4000: os.ModeSetuid, 0o2000: os.ModeSetgid, 0o1000: os.ModeSticky} {
+		if bits&bit != 0 {
+			mode |= flag
+		}
+	}
+
+	return Rule{Pattern: pattern, Mode: mode}, nil
+}
+
+// WithRules applies permissions rules to copies; the last matching rule wins.
+func WithRules(rules ...Rule) Option {
+	return func(o *Options) {
+		o.Rules = append(o.Rules, rules...)
+	}
+}
+
+// ruled returns the mode of the last of the Options' rules matching node, relative to root.
+func (o *Options) ruled(root, node *Node) (mode os.FileMode, matched bool) {
+	relative, e := filepath.Rel(root.Path, node.Path)
+	if e != nil {
+		return 0, false
+	}
+
+	for _, rule := range o.Rules {
+		pattern, directories := strings.CutSuffix(rule.Pattern, "/")
+		if directories != (node.Type == Directory) {
+			continue
+		}
+
+		if match([]string{pattern}, node.Name, relative) {
+			mode, matched = rule.Mode, true
+		}
+	}
+
+	return
+}
+
+// settle assigns the Options' Chown owner, then the rules' modes, to the directories materialized at destination.
+// Modes are applied last, as chown(2) clears setuid and setgid bits, and deepest-first, so that a restrictive
+// parent mode never denies access to its children.
+func (n *Node) settle(destination string, directories []*Node) {
+	n.options.own(destination, directories)
+
+	for i := len(directories) - 1; i >= 0; i-- {
+		directory := directories[i]
+		if mode, matched := n.options.ruled(n, directory); matched {
+			if e := os.Chmod(filepath.Join(destination, directory.Path), mode); e != nil {
+				panic(e)
+			}
+		}
+	}
+}
@@ -0,0 +1,17 @@
+//go:build darwin
+
+package tree
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// nodump reports whether the file or directory at path carries the nodump (chflags nodump) flag.
+func nodump(path string) bool {
+	var stat unix.Stat_t
+	if e := unix.Lstat(path, &stat); e != nil {
+		return false
+	}
+
+	return stat.Flags&unix.UF_NODUMP != 0
+}
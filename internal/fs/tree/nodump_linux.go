@@ -0,0 +1,10 @@
+//go:build linux
+
+package tree
+
+// nodump reports whether the file or directory at path carries the nodump (chattr +d) flag.
+func nodump(path string) bool {
+	bits, e := inode(path)
+
+	return e == nil && bits&flagNoDump != 0
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package tree
+
+// nodump reports whether the file or directory at path carries a nodump flag; unsupported on this platform.
+func nodump(path string) bool {
+	return false
+}
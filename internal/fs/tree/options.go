@@ -0,0 +1,269 @@
+package tree
+
+import (
+	"cli/internal/fs/checksum"
+	"cli/internal/fs/concurrency"
+	"cli/internal/progress"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Options represents the configurable behavior of a tree walk.
+type Options struct {
+	// Excludes are glob patterns matched against a node's name and path; matching nodes are not walked.
+	Excludes []string
+
+	// Algorithm is the hashing algorithm used for file checksums.
+	Algorithm checksum.Algorithm
+
+	// Digests are additional algorithms whose file digests are calculated in the same read pass.
+	Digests []checksum.Algorithm
+
+	// MaxDepth limits how many directory levels are descended. Zero means unlimited.
+	MaxDepth int
+
+	// MaxFiles limits how many files are added to the tree. Zero means unlimited.
+	MaxFiles int
+
+	// Unhashed skips reading files for checksums altogether, for walks that only need the tree's structure.
+	Unhashed bool
+
+	// SampleThreshold is the file size above which only a sampled fingerprint is calculated. Zero disables sampling.
+	SampleThreshold int64
+
+	// SampleSize is how many bytes from each of a sampled file's head and tail are hashed.
+	SampleSize int64
+
+	// ParallelThreshold is the file size above which the checksum is a tree digest, its chunks hashed in parallel.
+	// Zero disables parallel hashing.
+	ParallelThreshold int64
+
+	// ChunkSize is the size of each chunk of a parallel-hashed file.
+	ChunkSize int64
+
+	// Concurrency hashes the walk's files, and the chunks of its tree digests, concurrently at the levels it
+	// chooses; nil hashes files one at a time as they're walked, see WithConcurrency.
+	Concurrency *concurrency.Controller
+
+	// CopyConcurrency writes the files of copies concurrently at the levels it chooses; nil writes them one
+	// at a time.
+	CopyConcurrency *concurrency.Controller
+
+	// StandardExclusions excludes cache directories tagged with a CacheTag, and nodump-flagged files and directories.
+	StandardExclusions bool
+
+	// Metadata captures file flags and extended attribute names, and preserves them on copy.
+	Metadata bool
+
+	// Contexts is the security context policy; empty means ContextsNone.
+	Contexts Contexts
+
+	// Unreadable is the copy policy of files the user can't read; empty means UnreadableFail.
+	Unreadable Unreadable
+
+	// PreserveSpecial keeps the setuid, setgid, and sticky bits of copied files, and WithMetadata, their
+	// capabilities.
+	PreserveSpecial bool
+
+	// PreserveCapabilities keeps the capabilities of copied files WithMetadata.
+	PreserveCapabilities bool
+
+	// Rules are the permissions rules of copies; the last matching rule wins.
+	Rules []Rule
+
+	// MaxBytes is the budget of file contents each copy writes, claimed in Priority order. Zero means unlimited.
+	MaxBytes int64
+
+	// Priority is the order in which files claim the MaxBytes budget.
+	Priority Priority
+
+	// Chown is the owner assigned to everything a copy materializes; nil keeps the copying user's.
+	Chown *Owner
+
+	// HandleImmutable clears and reapplies a Replace destination's immutable and append-only flags.
+	HandleImmutable bool
+
+	// NeverFollow removes the paths of failed Transacts without traversing symbolic links beneath the destination.
+	NeverFollow bool
+
+	// Forensic captures every Node's Times at full precision.
+	Forensic bool
+
+	// ReadOnly asserts the walk performs no writes, and refuses the tree's mutating operations.
+	ReadOnly bool
+
+	// SkipDirIf are the predicates of directories whose children aren't walked, see WithSkipDirIf.
+	SkipDirIf []SkipDirIf
+
+	// Filter selects the files walked; nil walks every file.
+	Filter func(n *Node) bool
+
+	// Tags maps tag names to glob patterns; nodes matching any of a tag's patterns carry the tag.
+	Tags map[string][]string
+
+	// Progress receives the walk's progress; nil reports nothing.
+	Progress *progress.Progress
+
+	// Faults intercepts the tree's file-system operations; nil performs them as they are, see WithFaults.
+	Faults Faults
+
+	// Encoding is the serialization Encoding of the tree's nodes; nil means DefaultEncoding.
+	Encoding *Encoding
+
+	mutex      sync.Mutex
+	files      int
+	pending    []deferred
+	violations []string
+	skipped    []string
+	omitted    []string
+	undo       *undo
+}
+
+// Option configures a tree walk.
+type Option func(o *Options)
+
+// WithExcludes adds glob patterns of paths that will not be walked.
+func WithExcludes(patterns ...string) Option {
+	return func(o *Options) {
+		o.Excludes = append(o.Excludes, patterns...)
+	}
+}
+
+// WithAlgorithm sets the hashing algorithm used for file checksums.
+func WithAlgorithm(algorithm checksum.Algorithm) Option {
+	return func(o *Options) {
+		o.Algorithm = algorithm
+	}
+}
+
+// WithDigests calculates additional file digests alongside the checksum, in a single read pass.
+func WithDigests(algorithms ...checksum.Algorithm) Option {
+	return func(o *Options) {
+		o.Digests = append(o.Digests, algorithms...)
+	}
+}
+
+// WithSampling calculates sampled fingerprints, of size bytes from each of the head and tail,
+// rather than full digests of files larger than threshold.
+func WithSampling(threshold, size int64) Option {
+	return func(o *Options) {
+		o.SampleThreshold = threshold
+		o.SampleSize = size
+	}
+}
+
+// WithoutHashing walks without reading any file, leaving every Checksum nil.
+func WithoutHashing() Option {
+	return func(o *Options) {
+		o.Unhashed = true
+	}
+}
+
+// WithParallelHashing calculates tree digests, hashing chunk-byte chunks in parallel, rather than
+// sequential digests of files larger than threshold.
+func WithParallelHashing(threshold, chunk int64) Option {
+	return func(o *Options) {
+		o.ParallelThreshold = threshold
+		o.ChunkSize = chunk
+	}
+}
+
+// WithProgress reports the walk's hashing progress to p.
+func WithProgress(p *progress.Progress) Option {
+	return func(o *Options) {
+		o.Progress = p
+	}
+}
+
+// WithTags assigns tags to nodes matching the tag's glob patterns.
+func WithTags(tags map[string][]string) Option {
+	return func(o *Options) {
+		if o.Tags == nil {
+			o.Tags = map[string][]string{}
+		}
+
+		for tag, patterns := range tags {
+			o.Tags[tag] = append(o.Tags[tag], patterns...)
+		}
+	}
+}
+
+// WithFilter walks only the files for which keep returns true, dropping directories left without files.
+// keep is called with the file's parent, tags, owner, size, and modification time populated.
+func WithFilter(keep func(n *Node) bool) Option {
+	return func(o *Options) {
+		o.Filter = keep
+	}
+}
+
+// keeps returns whether the file child of parent is walked: without a Filter, or once its parent and tags
+// are populated, if the Filter keeps it.
+func (o *Options) keeps(parent, child *Node) bool {
+	if o.Filter == nil {
+		return true
+	}
+
+	child.parent, child.Tags = parent, o.tags(child.Name, child.Path)
+
+	return o.Filter(child)
+}
+
+// WithMaxDepth limits how many directory levels are descended.
+func WithMaxDepth(depth int) Option {
+	return func(o *Options) {
+		o.MaxDepth = depth
+	}
+}
+
+// WithMaxFiles limits how many files are added to the tree.
+func WithMaxFiles(files int) Option {
+	return func(o *Options) {
+		o.MaxFiles = files
+	}
+}
+
+func options(settings ...Option) *Options {
+	o := &Options{
+		Algorithm: checksum.AlgorithmSHA256,
+	}
+
+	for _, setting := range settings {
+		setting(o)
+	}
+
+	return o
+}
+
+// tags returns the sorted tags whose patterns match the given name or path.
+func (o *Options) tags(name, path string) (tags []string) {
+	for tag, patterns := range o.Tags {
+		if match(patterns, name, path) {
+			tags = append(tags, tag)
+		}
+	}
+
+	sort.Strings(tags)
+
+	return
+}
+
+// excluded returns whether the given name or path matches any of the exclusion patterns.
+func (o *Options) excluded(name, path string) bool {
+	return match(o.Excludes, name, path)
+}
+
+// match returns whether the given name or path matches any of the glob patterns.
+func match(patterns []string, name, path string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+
+		if matched, _ := filepath.Match(pattern, path); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,155 @@
+package tree_test
+
+import (
+	"bytes"
+	"cli/internal/fs/concurrency"
+	"cli/internal/fs/tree"
+	"cli/internal/fs/tree/treetest"
+	"cli/internal/render"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// ordering is a fixture whose names sort differently by byte, case, and number, and whose directories
+// nest files beside subdirectories sharing their prefix.
+var ordering = map[string]string{
+	"b":          "b",
+	"a.txt":      "a.txt",
+	"a/x":        "a/x",
+	"a/10":       "a/10",
+	"a/9":        "a/9",
+	"a/B/c":      "a/B/c",
+	"a/b/c":      "a/b/c",
+	"A":          "A",
+	"z/empty/":   "",
+	"z/y/x/w":    "w",
+	"_/-":        "-",
+	"d/e/f/g/h":  "h",
+	"d/e.go":     "e.go",
+	"d/e f/g":    "g",
+	"d/é":        "é",
+	"d/e/f.json": "{}",
+}
+
+// rendition represents every ordered view of a walked tree, collected by views.
+type rendition struct {
+	each        []string
+	files       []string
+	directories []string
+	json        string
+	yaml        string
+	text        string
+}
+
+func views(t *testing.T, n *tree.Node) rendition {
+	var r rendition
+	n.Each(func(node *tree.Node) bool {
+		r.each = append(r.each, node.Path)
+
+		children := node.Children()
+		names := make([]string, len(children))
+		for i, child := range children {
+			names[i] = child.Name
+			if child.Index() != i {
+				t.Errorf("%s: index %d at position %d", child.Path, child.Index(), i)
+			}
+		}
+
+		if !(sort.StringsAreSorted(names)) {
+			t.Errorf("%s: children out of name order: %s", node.Path, strings.Join(names, " "))
+		}
+
+		return true
+	})
+
+	for _, file := range n.FilesRecursive() {
+		r.files = append(r.files, file.Path)
+	}
+
+	for _, directory := range n.DirectoriesRecursive() {
+		r.directories = append(r.directories, directory.Path)
+	}
+
+	var text bytes.Buffer
+	render.Tree(&text, n, render.Style{Plain: true})
+
+	r.json, r.yaml, r.text = n.JSON(), n.YAML(), text.String()
+
+	return r
+}
+
+func TestOrderIsDeterministic(t *testing.T) {
+	f := treetest.New(t, ordering)
+
+	const walks = 8
+
+	renditions := make([]rendition, walks)
+
+	var group sync.WaitGroup
+	for i := 0; i < walks; i++ {
+		group.Add(1)
+		go func(i int) {
+			defer group.Done()
+
+			settings := []tree.Option{tree.WithMetadata()}
+			if i%2 == 1 {
+				settings = append(settings, tree.WithConcurrency(concurrency.New(1, 8)))
+			}
+
+			n, e := tree.Walk(f.Root, settings...)
+			if e != nil {
+				t.Error(e)
+				return
+			}
+
+			renditions[i] = views(t, n)
+		}(i)
+	}
+
+	group.Wait()
+
+	expected := renditions[0]
+	// the root, 13 directories, and 15 files
+	if len(expected.each) != 29 {
+		t.Fatalf("walked %d nodes: %v", len(expected.each), expected.each)
+	}
+
+	for i, r := range renditions[1:] {
+		switch {
+		case strings.Join(r.each, "\n") != strings.Join(expected.each, "\n"):
+			t.Errorf("walk %d: Each order differs", i+1)
+		case strings.Join(r.files, "\n") != strings.Join(expected.files, "\n"):
+			t.Errorf("walk %d: FilesRecursive order differs", i+1)
+		case strings.Join(r.directories, "\n") != strings.Join(expected.directories, "\n"):
+			t.Errorf("walk %d: DirectoriesRecursive order differs", i+1)
+		case r.json != expected.json:
+			t.Errorf("walk %d: JSON differs", i+1)
+		case r.yaml != expected.yaml:
+			t.Errorf("walk %d: YAML differs", i+1)
+		case r.text != expected.text:
+			t.Errorf("walk %d: rendering differs", i+1)
+		}
+	}
+}
+
+func TestOrderSurvivesDecoding(t *testing.T) {
+	f := treetest.New(t, ordering)
+
+	n, e := tree.Walk(f.Root)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	expected := views(t, n)
+
+	decoded := &tree.Node{}
+	if e := decoded.UnmarshalJSON([]byte(expected.json)); e != nil {
+		t.Fatal(e)
+	}
+
+	if r := views(t, decoded); strings.Join(r.each, "\n") != strings.Join(expected.each, "\n") || r.json != expected.json || r.text != expected.text {
+		t.Fatal("decoded tree is ordered differently")
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package tree
+
+import (
+	"os"
+)
+
+// owner returns nil: file ownership isn't available on this platform.
+func owner(info os.FileInfo) *Owner {
+	return nil
+}
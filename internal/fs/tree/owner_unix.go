@@ -0,0 +1,17 @@
+//go:build unix
+
+package tree
+
+import (
+	"os"
+	"syscall"
+)
+
+// owner returns the Owner of the file described by info, if available.
+func owner(info os.FileInfo) *Owner {
+	if stat, valid := info.Sys().(*syscall.Stat_t); valid {
+		return &Owner{UID: int(stat.Uid), GID: int(stat.Gid)}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package tree
+
+import (
+	"bufio"
+	"bytes"
+	"cli/internal/exception"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	// PreviewTruncated marks a preview that omits the remainder of the file.
+	PreviewTruncated = "[… truncated]"
+
+	// previewWidth is the longest line, in runes, included in a preview before being cut.
+	previewWidth = 512
+
+	// previewBytes bounds how much of a file is read per requested line.
+	previewBytes = 4 * previewWidth
+)
+
+// Preview returns the first lines of a text file, decoded to UTF-8 from UTF-8, UTF-16 (via
+// byte-order mark), or Latin-1. Overlong lines are cut with "…", and a PreviewTruncated marker
+// line is appended if the file holds more than the requested lines. Binary files return an
+// EBINARY error.
+func (n *Node) Preview(lines int) (string, error) {
+	reader, e := n.Open()
+	if e != nil {
+		return "", e
+	}
+
+	defer reader.Close()
+
+	limit := int64(lines+1) * previewBytes
+	buffer, e := io.ReadAll(io.LimitReader(reader, limit))
+	if e != nil {
+		return "", exception.New(exception.EREAD, "preview", n.Path, e)
+	}
+
+	partial := int64(len(buffer)) == limit
+
+	text, valid := decode(buffer)
+	if !(valid) {
+		return "", exception.New(exception.EBINARY, "preview", n.Path, nil)
+	}
+
+	var output strings.Builder
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, previewBytes), len(text)+1)
+
+	count := 0
+	for scanner.Scan() {
+		if count == lines {
+			partial = true
+			break
+		}
+
+		line := scanner.Text()
+		if utf8.RuneCountInString(line) > previewWidth {
+			line = string([]rune(line)[:previewWidth]) + "…"
+		}
+
+		fmt.Fprintln(&output, line)
+		count++
+	}
+
+	if partial {
+		output.WriteString(PreviewTruncated + "\n")
+	}
+
+	return output.String(), nil
+}
+
+// decode converts buffer to UTF-8, detecting UTF-8 and UTF-16 byte-order marks and falling back to
+// Latin-1 for invalid UTF-8. It reports false for binary content.
+func decode(buffer []byte) (string, bool) {
+	switch {
+	case bytes.HasPrefix(buffer, []byte{0xEF, 0xBB, 0xBF}):
+		buffer = buffer[3:]
+	case bytes.HasPrefix(buffer, []byte{0xFF, 0xFE}):
+		return utf16Decode(buffer[2:], binary.LittleEndian), true
+	case bytes.HasPrefix(buffer, []byte{0xFE, 0xFF}):
+		return utf16Decode(buffer[2:], binary.BigEndian), true
+	}
+
+	if Binary(buffer) {
+		return "", false
+	}
+
+	// a limited read may cut a multibyte rune; only the tail is forgiven.
+	if valid := bytes.TrimRightFunc(buffer, func(r rune) bool { return r == utf8.RuneError }); utf8.Valid(valid) {
+		return string(valid), true
+	}
+
+	runes := make([]rune, len(buffer))
+	for i, b := range buffer {
+		runes[i] = rune(b)
+	}
+
+	return string(runes), true
+}
+
+func utf16Decode(buffer []byte, order binary.ByteOrder) string {
+	units := make([]uint16, len(buffer)/2)
+	for i := range units {
+		units[i] = order.Uint16(buffer[2*i:])
+	}
+
+	return string(utf16.Decode(units))
+}
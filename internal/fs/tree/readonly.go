@@ -0,0 +1,55 @@
+package tree
+
+import (
+	"cli/internal/exception"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var ExceptionReadOnly = exception.New(exception.EREADONLY, "", "", nil)
+
+// WithReadOnly asserts that the walk performs no writes: every hashed file is verified unmodified
+// afterwards, and the tree's mutating operations (Copy, Replicate, Replace) are refused.
+func WithReadOnly() Option {
+	return func(o *Options) {
+		o.ReadOnly = true
+	}
+}
+
+// ReadOnly reports whether the tree was walked under the read-only assertion.
+func (n *Node) ReadOnly() bool {
+	return n.options != nil && n.options.ReadOnly
+}
+
+// mutate panics with an ExceptionReadOnly if the tree was walked under the read-only assertion.
+func (n *Node) mutate(op string) {
+	if n.ReadOnly() {
+		panic(exception.New(exception.EREADONLY, op, n.Path, errors.New("refusing to mutate a read-only tree")))
+	}
+}
+
+// verify records path as a read-only violation if its modification time, change time, or size
+// differ from before it was read.
+func (o *Options) verify(path string, before os.FileInfo) {
+	if !(o.ReadOnly) || before == nil {
+		return
+	}
+
+	after, e := os.Lstat(path)
+	if e != nil || !(after.ModTime().Equal(before.ModTime())) || !(changed(after).Equal(changed(before))) || after.Size() != before.Size() {
+		o.mutex.Lock()
+		o.violations = append(o.violations, path)
+		o.mutex.Unlock()
+	}
+}
+
+// violated returns an ExceptionReadOnly listing the paths modified during the walk, if any.
+func (o *Options) violated(path string) error {
+	if len(o.violations) == 0 {
+		return nil
+	}
+
+	return exception.New(exception.EREADONLY, "walk", path, fmt.Errorf("modified during the walk: %s", strings.Join(o.violations, ", ")))
+}
@@ -0,0 +1,134 @@
+package tree
+
+import (
+	"cli/internal/exception"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+)
+
+// clusters is the number of directories a sampling walk expects to sample at its frontier, so a level
+// becomes the frontier once it holds clusters/fraction directories.
+const clusters = 32
+
+// Tally represents the directories, files, and file bytes of part of a tree.
+type Tally struct {
+	Directories int
+	Files       int
+	Bytes       int64
+}
+
+// Sample represents a sampling walk: directories are listed breadth-first until a level holds enough of them
+// to sample, the frontier. Each frontier directory's subtree is then walked with probability Fraction, chosen
+// uniformly at random, and the rest are never descended into.
+type Sample struct {
+	// Fraction is the probability with which each frontier directory was sampled.
+	Fraction float64
+
+	// Frontier is the number of frontier directories; zero if the tree was too narrow to sample, and was
+	// walked fully.
+	Frontier int
+
+	// Listed is the number of directories listed, above the frontier and within the sampled subtrees.
+	Listed int
+
+	// Exact are the totals of the directories above the frontier, every one of which was listed.
+	Exact Tally
+
+	// Clusters are the totals of each sampled frontier directory's subtree.
+	Clusters []Tally
+}
+
+// Sampled walks the directory at path, honoring the exclusion and depth options, sampling the subtrees of its
+// frontier directories with probability fraction from a source seeded with seed. Nothing is hashed or read.
+func Sampled(path string, fraction float64, seed int64, settings ...Option) (*Sample, error) {
+	if info, e := os.Stat(path); e != nil || !(info.IsDir()) {
+		return nil, exception.New(exception.ENOTDIR, "sample", path, e)
+	}
+
+	o := options(settings...)
+	random := rand.New(rand.NewSource(seed))
+	s := &Sample{Fraction: fraction}
+
+	width := int(math.Ceil(clusters / fraction))
+
+	level, depth := []string{path}, 0
+	for len(level) > 0 {
+		if depth > 0 && len(level) >= width {
+			s.Frontier = len(level)
+			for _, directory := range level {
+				if random.Float64() < fraction {
+					s.Clusters = append(s.Clusters, s.walk(o, directory, depth))
+				}
+			}
+
+			break
+		}
+
+		var next []string
+		for _, directory := range level {
+			next = append(next, s.list(o, directory, depth, &s.Exact)...)
+		}
+
+		level, depth = next, depth+1
+	}
+
+	return s, nil
+}
+
+// walk returns the Tally of the whole subtree of directory, at depth.
+func (s *Sample) walk(o *Options, directory string, depth int) Tally {
+	var t Tally
+
+	var descend func(directory string, depth int)
+	descend = func(directory string, depth int) {
+		for _, subdirectory := range s.list(o, directory, depth, &t) {
+			descend(subdirectory, depth+1)
+		}
+	}
+
+	descend(directory, depth)
+
+	return t
+}
+
+// list lists directory, at depth, into t: the directory itself, and its files and their sizes. It returns
+// the subdirectories to descend into.
+func (s *Sample) list(o *Options, directory string, depth int, t *Tally) []string {
+	entries, e := o.list(directory)
+	if e != nil {
+		return nil
+	}
+
+	s.Listed++
+	t.Directories++
+
+	if o.skips(listed(directory), entries) {
+		return nil
+	}
+
+	var subdirectories []string
+	for _, entry := range entries {
+		name := entry.Name()
+		current := filepath.Join(directory, name)
+		if o.excluded(name, current) || o.dumpless(current, entry) {
+			continue
+		}
+
+		switch {
+		case entry.Type()&os.ModeSymlink != 0:
+		case entry.IsDir():
+			if o.MaxDepth == 0 || depth+1 < o.MaxDepth {
+				subdirectories = append(subdirectories, current)
+			}
+		default:
+			t.Files++
+			if info, e := entry.Info(); e == nil && info.Mode().IsRegular() {
+				t.Bytes += info.Size()
+			}
+		}
+	}
+
+	return subdirectories
+}
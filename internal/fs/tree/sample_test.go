@@ -0,0 +1,70 @@
+package tree_test
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/fs/tree/treetest"
+	"fmt"
+	"testing"
+)
+
+// bushy returns a fixture of width top-level directories, each of three subdirectories of two files.
+func bushy(width int) map[string]string {
+	contents := map[string]string{}
+	for i := 0; i < width; i++ {
+		for j := 0; j < 3; j++ {
+			contents[fmt.Sprintf("%03d/%d/a", i, j)] = "abcd"
+			contents[fmt.Sprintf("%03d/%d/b", i, j)] = "efghijkl"
+		}
+	}
+
+	return contents
+}
+
+func TestSampledSkipsUnsampledSubtrees(t *testing.T) {
+	f := treetest.New(t, bushy(200))
+
+	s, e := tree.Sampled(f.Root, 0.25, 1)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	// the root, and 200 frontier subtrees of four directories
+	if s.Frontier != 200 || s.Exact.Directories != 1 {
+		t.Fatalf("frontier of %d, %d directories above it; expected 200 beneath the root", s.Frontier, s.Exact.Directories)
+	}
+
+	if s.Listed != 1+4*len(s.Clusters) || len(s.Clusters) < 25 || len(s.Clusters) > 75 {
+		t.Fatalf("listed %d directories, sampling %d subtrees", s.Listed, len(s.Clusters))
+	}
+
+	for _, cluster := range s.Clusters {
+		if cluster != (tree.Tally{Directories: 4, Files: 6, Bytes: 36}) {
+			t.Fatalf("sampled subtree tallied %+v", cluster)
+		}
+	}
+}
+
+func TestSampledIsExactWithoutSampling(t *testing.T) {
+	for _, c := range []struct {
+		width    int
+		fraction float64
+	}{{5, 0.1}, {200, 1}} {
+		f := treetest.New(t, bushy(c.width))
+
+		s, e := tree.Sampled(f.Root, c.fraction, 1)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		total := s.Exact
+		for _, cluster := range s.Clusters {
+			total.Directories += cluster.Directories
+			total.Files += cluster.Files
+			total.Bytes += cluster.Bytes
+		}
+
+		if expected := (tree.Tally{Directories: 1 + 4*c.width, Files: 6 * c.width, Bytes: 36 * int64(c.width)}); total != expected {
+			t.Fatalf("width %d at fraction %v tallied %+v; expected %+v", c.width, c.fraction, total, expected)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package tree
+
+import (
+	"cli/internal/exception"
+	"cli/internal/progress"
+	"os"
+	"path/filepath"
+)
+
+// Scan performs a fast pre-scan of the directory at path, honoring the exclusion, filter, and limit options,
+// and returns the file and byte totals a full walk would process. Nothing is hashed or read.
+func Scan(path string, settings ...Option) (*progress.Totals, error) {
+	if info, e := os.Stat(path); e != nil || !(info.IsDir()) {
+		return nil, exception.New(exception.ENOTDIR, "scan", path, e)
+	}
+
+	o := options(settings...)
+	totals := &progress.Totals{}
+
+	var scan func(directory *Node, depth int)
+	scan = func(directory *Node, depth int) {
+		entries, e := o.list(directory.Path)
+		if e != nil || o.skips(directory, entries) {
+			return
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			current := filepath.Join(directory.Path, name)
+			if o.excluded(name, current) || o.dumpless(current, entry) {
+				continue
+			}
+
+			switch {
+			case entry.Type()&os.ModeSymlink != 0:
+			case entry.IsDir():
+				if o.MaxDepth == 0 || depth+1 < o.MaxDepth {
+					scan(&Node{Name: name, Dirname: directory.Path, Path: current, Type: Directory, parent: directory}, depth+1)
+				}
+			default:
+				if o.MaxFiles > 0 && totals.Files >= o.MaxFiles {
+					continue
+				}
+
+				file := &Node{Name: name, Dirname: directory.Path, Path: current, Type: File}
+
+				info, e := entry.Info()
+				if e == nil {
+					file.Owner, file.Modified = owner(info), info.ModTime()
+					if info.Mode().IsRegular() {
+						file.Size = info.Size()
+					}
+				}
+
+				if !(o.keeps(directory, file)) {
+					continue
+				}
+
+				totals.Files++
+				totals.Bytes += file.Size
+			}
+		}
+	}
+
+	scan(&Node{Name: filepath.Base(path), Dirname: filepath.Dir(path), Path: path, Type: Directory}, 0)
+
+	return totals, nil
+}
@@ -0,0 +1,41 @@
+package tree_test
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/fs/tree/treetest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestScanMatchesFilteredWalk(t *testing.T) {
+	f := treetest.New(t, map[string]string{
+		"bin/tool":        "binary",
+		"lib/a.so":        "shared object",
+		"lib/b.so":        "another",
+		"docs/guide.md":   "# guide",
+		"docs/api/ref.md": "# reference",
+		"README.md":       "# readme",
+	})
+
+	for _, settings := range [][]tree.Option{
+		nil,
+		{tree.WithFilter(func(n *tree.Node) bool { return strings.HasSuffix(n.Name, ".md") })},
+		{tree.WithTags(map[string][]string{"runtime": {"*.so", "bin/*"}}), tree.WithFilter(func(n *tree.Node) bool { return len(n.Tags) > 0 })},
+		{tree.WithFilter(func(n *tree.Node) bool { return filepath.Base(n.Dirname) == n.Root().Name })},
+	} {
+		n, e := tree.Walk(f.Root, settings...)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		totals, e := tree.Scan(f.Root, settings...)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		if totals.Files != n.CountFiles() || totals.Bytes != n.CountBytes() {
+			t.Errorf("scanned %d files of %d bytes; walked %d of %d", totals.Files, totals.Bytes, n.CountFiles(), n.CountBytes())
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package tree
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// SkipDirIf decides, from a directory and its entries, whether its children are walked at all.
+type SkipDirIf func(dir *Node, entries []fs.DirEntry) bool
+
+// WithSkipDirIf stops descending into the directories for which any of skip returns true. The predicates are given
+// the directory's listing before any of its children are stat'ed or added; skipped directories are kept, empty.
+func WithSkipDirIf(skip ...SkipDirIf) Option {
+	return func(o *Options) {
+		o.SkipDirIf = append(o.SkipDirIf, skip...)
+	}
+}
+
+// SkipMarker returns a SkipDirIf skipping the directories holding an entry named marker, e.g. ".skipinventory".
+func SkipMarker(marker string) SkipDirIf {
+	return func(dir *Node, entries []fs.DirEntry) bool {
+		for _, entry := range entries {
+			if entry.Name() == marker {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// SkipLarger returns a SkipDirIf skipping the directories holding more than limit entries.
+func SkipLarger(limit int) SkipDirIf {
+	return func(dir *Node, entries []fs.DirEntry) bool {
+		return len(entries) > limit
+	}
+}
+
+// skips reports whether any of the SkipDirIf predicates skips dir, listing entries.
+func (o *Options) skips(dir *Node, entries []fs.DirEntry) bool {
+	for _, skip := range o.SkipDirIf {
+		if skip(dir, entries) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listed returns the partial Node given to the SkipDirIf predicates of the scans, which build no tree.
+func listed(directory string) *Node {
+	return &Node{Name: filepath.Base(directory), Dirname: filepath.Dir(directory), Path: directory, Type: Directory}
+}
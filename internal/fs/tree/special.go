@@ -0,0 +1,149 @@
+package tree
+
+import (
+	"cli/internal/exception"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+)
+
+// Unreadable represents the copy policy of files the user can't read.
+type Unreadable string
+
+const (
+	// UnreadableFail fails the copy on the first unreadable file.
+	UnreadableFail Unreadable = "fail"
+
+	// UnreadableSkip silently skips unreadable files.
+	UnreadableSkip Unreadable = "skip"
+
+	// UnreadableRecord skips unreadable files, recording an error on their Node and listing them in Skipped.
+	UnreadableRecord Unreadable = "record"
+)
+
+// special are the mode bits stripped from copied files unless WithPreserveSpecial.
+const special = os.ModeSetuid | os.ModeSetgid | os.ModeSticky
+
+// Valid reports whether the Unreadable policy is supported.
+func (u Unreadable) Valid() bool {
+	return u == UnreadableFail || u == UnreadableSkip || u == UnreadableRecord
+}
+
+// WithUnreadable sets the copy policy of unreadable files; it panics if policy isn't Valid.
+func WithUnreadable(policy Unreadable) Option {
+	if !(policy.Valid()) {
+		panic(fmt.Errorf("invalid unreadable policy: %q", policy))
+	}
+
+	return func(o *Options) {
+		o.Unreadable = policy
+	}
+}
+
+// WithPreserveSpecial keeps the setuid, setgid, and sticky bits of copied files, which are otherwise stripped,
+// and WithMetadata, their capabilities, which otherwise aren't copied.
+func WithPreserveSpecial() Option {
+	return func(o *Options) {
+		o.PreserveSpecial = true
+	}
+}
+
+// WithPreserveCapabilities keeps the capabilities (security.capability) of files copied WithMetadata, which
+// otherwise aren't copied, as they grant privileges as the setuid bit does.
+func WithPreserveCapabilities() Option {
+	return func(o *Options) {
+		o.PreserveCapabilities = true
+	}
+}
+
+// Skipped returns the paths of the unreadable files skipped, and recorded, by the tree's copies.
+func (n *Node) Skipped() []string {
+	return n.options.skipped
+}
+
+// mode returns the Node's permissions, along with its setuid, setgid, and sticky bits if preserved.
+func (n *Node) mode(preserve bool) os.FileMode {
+	info, e := os.Stat(n.Path)
+	if e != nil {
+		panic(e)
+	}
+
+	if preserve {
+		return info.Mode() & (os.ModePerm | special)
+	}
+
+	return info.Mode().Perm()
+}
+
+// materialize streams file's contents, copied from the root n, to target, returning whether it was written:
+// unreadable files are skipped under the UnreadableSkip and UnreadableRecord policies. A matching rule's
+// mode overrides the file's own. Special bits, and ruled modes, are applied by chmod after any chown, as
+// the umask, open(2), and chown(2) may each drop them.
+func (n *Node) materialize(file *Node, target string) bool {
+	o := n.options
+
+	source, e := file.Open()
+	if e != nil {
+		if !(errors.Is(e, fs.ErrPermission)) || o.Unreadable == UnreadableFail || o.Unreadable == "" {
+			panic(e)
+		}
+
+		o.mutex.Lock()
+		if o.Unreadable == UnreadableRecord && !(slices.Contains(o.skipped, file.Path)) {
+			file.Errors = append(file.Errors, fmt.Sprintf("copy: skipped unreadable file: %v", e))
+			o.skipped = append(o.skipped, file.Path)
+		}
+		o.mutex.Unlock()
+
+		return false
+	}
+
+	defer source.Close()
+
+	mode := file.mode(o.PreserveSpecial)
+	ruled, matched := o.ruled(n, file)
+	if matched {
+		mode = ruled
+	}
+
+	o.mutex.Lock()
+	o.undo.track(target)
+	o.mutex.Unlock()
+
+	if e := o.inject(OperationWrite, target, file.Size); e != nil {
+		panic(exception.New(exception.EWRITE, "copy", target, e))
+	}
+
+	if e := stream(source, target, mode.Perm()); e != nil {
+		panic(exception.New(exception.EWRITE, "copy", target, e))
+	}
+
+	o.chown(target)
+
+	if mode&special != 0 || matched {
+		if e := os.Chmod(target, mode); e != nil {
+			panic(e)
+		}
+	}
+
+	return true
+}
+
+// stream writes source to target, as os.WriteFile writes a buffer: created with permissions if missing, and
+// truncated otherwise.
+func stream(source io.Reader, target string, permissions os.FileMode) error {
+	f, e := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, permissions)
+	if e != nil {
+		return e
+	}
+
+	if _, e := io.Copy(f, source); e != nil {
+		f.Close()
+		return e
+	}
+
+	return f.Close()
+}
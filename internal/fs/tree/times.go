@@ -0,0 +1,23 @@
+package tree
+
+import (
+	"time"
+)
+
+// Times represents a Node's timestamps at full, nanosecond precision, see WithForensic.
+type Times struct {
+	Accessed time.Time `json:"accessed" yaml:"accessed"`
+	Modified time.Time `json:"modified" yaml:"modified"`
+	Changed  time.Time `json:"changed" yaml:"changed"`
+
+	// Born is the creation time, where the platform and file-system record it.
+	Born *time.Time `json:"born,omitempty" yaml:"born,omitempty"`
+}
+
+// WithForensic captures every Node's access, modification, change, and (where available) birth times
+// at full precision, before the walk reads the Node, along with its inode and mode, for incident-response timelines.
+func WithForensic() Option {
+	return func(o *Options) {
+		o.Forensic = true
+	}
+}
@@ -0,0 +1,34 @@
+//go:build darwin
+
+package tree
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// changed returns the inode change time (ctime) of info, if available.
+func changed(info os.FileInfo) time.Time {
+	if stat, valid := info.Sys().(*syscall.Stat_t); valid {
+		return time.Unix(stat.Ctimespec.Sec, stat.Ctimespec.Nsec)
+	}
+
+	return time.Time{}
+}
+
+// times returns the Times of info, including the birth time.
+func times(path string, info os.FileInfo) *Times {
+	if stat, valid := info.Sys().(*syscall.Stat_t); valid {
+		born := time.Unix(stat.Birthtimespec.Sec, stat.Birthtimespec.Nsec)
+
+		return &Times{
+			Accessed: time.Unix(stat.Atimespec.Sec, stat.Atimespec.Nsec),
+			Modified: time.Unix(stat.Mtimespec.Sec, stat.Mtimespec.Nsec),
+			Changed:  time.Unix(stat.Ctimespec.Sec, stat.Ctimespec.Nsec),
+			Born:     &born,
+		}
+	}
+
+	return &Times{Modified: info.ModTime()}
+}
@@ -0,0 +1,49 @@
+//go:build linux
+
+package tree
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// changed returns the inode change time (ctime) of info, if available.
+func changed(info os.FileInfo) time.Time {
+	if stat, valid := info.Sys().(*syscall.Stat_t); valid {
+		return time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec))
+	}
+
+	return time.Time{}
+}
+
+// times returns the Times of path via statx(2), falling back to info where statx is unsupported.
+func times(path string, info os.FileInfo) *Times {
+	var stat unix.Statx_t
+	if e := unix.Statx(unix.AT_FDCWD, path, unix.AT_SYMLINK_NOFOLLOW, unix.STATX_BASIC_STATS|unix.STATX_BTIME, &stat); e == nil {
+		t := &Times{
+			Accessed: time.Unix(stat.Atime.Sec, int64(stat.Atime.Nsec)),
+			Modified: time.Unix(stat.Mtime.Sec, int64(stat.Mtime.Nsec)),
+			Changed:  time.Unix(stat.Ctime.Sec, int64(stat.Ctime.Nsec)),
+		}
+
+		if stat.Mask&unix.STATX_BTIME != 0 {
+			born := time.Unix(stat.Btime.Sec, int64(stat.Btime.Nsec))
+			t.Born = &born
+		}
+
+		return t
+	}
+
+	if stat, valid := info.Sys().(*syscall.Stat_t); valid {
+		return &Times{
+			Accessed: time.Unix(int64(stat.Atim.Sec), int64(stat.Atim.Nsec)),
+			Modified: time.Unix(int64(stat.Mtim.Sec), int64(stat.Mtim.Nsec)),
+			Changed:  time.Unix(int64(stat.Ctim.Sec), int64(stat.Ctim.Nsec)),
+		}
+	}
+
+	return &Times{Modified: info.ModTime()}
+}
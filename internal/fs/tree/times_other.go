@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package tree
+
+import (
+	"os"
+	"time"
+)
+
+// changed returns the inode change time (ctime) of info; unsupported on this platform.
+func changed(info os.FileInfo) time.Time {
+	return time.Time{}
+}
+
+// times returns the Times of info; only the modification time is available on this platform.
+func times(path string, info os.FileInfo) *Times {
+	return &Times{Modified: info.ModTime()}
+}
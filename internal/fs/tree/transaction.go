@@ -0,0 +1,193 @@
+package tree
+
+import (
+	"cli/internal/exception"
+	"cli/internal/fs/remove"
+	"cli/internal/fs/workspace"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// undo represents the rollback journal of a transacted copy: the paths it created, in creation order,
+// and the files it overwrote, renamed into a backup Workspace beforehand.
+type undo struct {
+	destination string
+	created     []string
+	targets     []string
+	backups     *workspace.Workspace
+	root        *remove.Root
+}
+
+// WithNeverFollow removes the paths a failed Transact created without traversing, nor deleting through,
+// symbolic links beneath the destination, as resolved when Transact starts.
+func WithNeverFollow() Option {
+	return func(o *Options) {
+		o.NeverFollow = true
+	}
+}
+
+// Transact runs operation, a Copy, Replicate, or Replace of the Node, to destination with all-or-nothing
+// semantics: if it fails, the paths it created are removed and the files it overwrote restored, and the
+// failure is returned.
+func (n *Node) Transact(destination string, operation func(n *Node, destination string)) (e error) {
+	u := &undo{destination: destination}
+	if n.options.NeverFollow {
+		root, e := remove.Open(anchor(destination))
+		if e != nil {
+			return e
+		}
+
+		u.root = root
+	}
+
+	n.options.undo = u
+	defer func() {
+		n.options.undo = nil
+
+		if r := recover(); r != nil {
+			if exception, valid := r.(error); valid {
+				e = exception
+			} else {
+				e = fmt.Errorf("%v", r)
+			}
+
+			if failure := u.rollback(); failure != nil {
+				e = fmt.Errorf("%w; rollback failed: %v", e, failure)
+			}
+
+			return
+		}
+
+		e = u.commit()
+	}()
+
+	operation(n, destination)
+
+	return nil
+}
+
+// mkdir creates target and its missing parents, journaling those it created.
+func (o *Options) mkdir(target string, permissions os.FileMode) {
+	if o.undo != nil {
+		var missing []string
+		for path := target; ; path = filepath.Dir(path) {
+			if _, e := os.Lstat(path); !(errors.Is(e, os.ErrNotExist)) {
+				break
+			}
+
+			missing = append(missing, path)
+			if filepath.Dir(path) == path {
+				break
+			}
+		}
+
+		for i := len(missing) - 1; i >= 0; i-- {
+			o.undo.created = append(o.undo.created, missing[i])
+		}
+	}
+
+	if e := o.inject(OperationMkdir, target, 0); e != nil {
+		panic(exception.New(exception.EWRITE, "mkdir", target, e))
+	}
+
+	if e := os.MkdirAll(target, permissions); e != nil {
+		panic(exception.New(exception.EWRITE, "mkdir", target, e))
+	}
+}
+
+// track journals target before it's written: an existing file is backed up, and a missing one recorded as created.
+func (u *undo) track(target string) {
+	if u == nil {
+		return
+	}
+
+	if _, e := os.Lstat(target); errors.Is(e, os.ErrNotExist) {
+		u.created = append(u.created, target)
+		return
+	}
+
+	if u.backups == nil {
+		w, e := workspace.New(u.destination)
+		if e != nil {
+			panic(e)
+		}
+
+		u.backups = w
+	}
+
+	if e := os.Rename(target, filepath.Join(u.backups.Path, strconv.Itoa(len(u.targets)))); e != nil {
+		panic(e)
+	}
+
+	u.targets = append(u.targets, target)
+}
+
+// rollback removes the created paths, newest first, then restores the backed-up files.
+func (u *undo) rollback() error {
+	var failures []error
+	for i := len(u.created) - 1; i >= 0; i-- {
+		if e := u.remove(u.created[i]); e != nil && !(errors.Is(e, os.ErrNotExist)) {
+			failures = append(failures, e)
+		}
+	}
+
+	for i, target := range u.targets {
+		if e := os.Rename(filepath.Join(u.backups.Path, strconv.Itoa(i)), target); e != nil {
+			failures = append(failures, e)
+		}
+	}
+
+	if u.backups != nil {
+		failures = append(failures, u.backups.Close())
+	}
+
+	return errors.Join(failures...)
+}
+
+// anchor returns the directory a failed Transact removes paths beneath: destination, when it's an existing
+// directory, otherwise its nearest existing ancestor, since a copy can create destination and its parents.
+func anchor(destination string) string {
+	if info, e := os.Stat(destination); e == nil && info.IsDir() {
+		return destination
+	}
+
+	path := filepath.Dir(destination)
+	for parent := filepath.Dir(path); parent != path; path, parent = parent, filepath.Dir(parent) {
+		if _, e := os.Lstat(path); !(errors.Is(e, os.ErrNotExist)) {
+			break
+		}
+	}
+
+	return path
+}
+
+// remove deletes the created file, or empty directory, at path; with a root, through it, so that no
+// component beneath the root is followed.
+func (u *undo) remove(path string) error {
+	if u.root == nil {
+		return os.Remove(path)
+	}
+
+	info, e := os.Lstat(path)
+	if e != nil {
+		return e
+	}
+
+	if info.IsDir() {
+		return u.root.RemoveDirectory(path)
+	}
+
+	return u.root.Remove(path)
+}
+
+// commit discards the backups.
+func (u *undo) commit() error {
+	if u.backups == nil {
+		return nil
+	}
+
+	return u.backups.Close()
+}
@@ -1,14 +1,17 @@
 package tree
 
 import (
+	"cli/internal/exception"
 	"cli/internal/fs/checksum"
-	"encoding/json"
+	"cli/internal/fs/workspace"
 	"errors"
 	"fmt"
-	"gopkg.in/yaml.v3"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 )
 
 type Descriptor string
@@ -16,11 +19,12 @@ type Descriptor string
 type Exception error
 
 var (
-	ExceptionNilNode              Exception = errors.New("nil node")
-	ExceptionInvalidFileNode      Exception = errors.New("invalid file node")
-	ExceptionInvalidDirectoryNode Exception = errors.New("invalid directory node")
+	ExceptionNilNode              Exception = exception.New(exception.ENILNODE, "", "", nil)
+	ExceptionInvalidFileNode      Exception = exception.New(exception.ENOTFILE, "", "", nil)
+	ExceptionInvalidDirectoryNode Exception = exception.New(exception.ENOTDIR, "", "", nil)
 
-	ExceptionInvalidDirectory Exception = errors.New("invalid directory")
+	ExceptionInvalidDirectory Exception = exception.New(exception.ENOTDIR, "", "", nil)
+	ExceptionWalkPartial      Exception = exception.New(exception.EWALKPARTIAL, "", "", nil)
 )
 
 const (
@@ -29,41 +33,112 @@ const (
 	Symbolic  Descriptor = "SYMBOLIC"
 )
 
+// Owner represents the numeric user and group ownership of a Node.
+type Owner struct {
+	UID int `json:"uid" yaml:"uid"`
+	GID int `json:"gid" yaml:"gid"`
+}
+
+// counts represents the cached totals of a Node's subtree.
+type counts struct {
+	files       int
+	directories int
+	bytes       int64
+}
+
+// tally accumulates child, along with its subtree's totals, into c.
+func (c *counts) tally(child *Node) {
+	switch child.Type {
+	case File:
+		c.files++
+		c.bytes += child.Size
+	case Directory:
+		c.directories++
+	}
+
+	c.files += child.counts.files
+	c.directories += child.counts.directories
+	c.bytes += child.counts.bytes
+}
+
 type Node struct {
 	parent *Node            `json:"-" yaml:"-"`
 	table  map[string]*Node `json:"-" yaml:"-"`
+	lookup map[string]*Node `json:"-" yaml:"-"`
 	depth  int              `json:"-" yaml:"-"`
 
+	// position is the Node's stable index among its parent's children.
+	position int `json:"-" yaml:"-"`
+
+	options *Options `json:"-" yaml:"-"`
+	counts  counts   `json:"-" yaml:"-"`
+
 	content []byte `json:"-" yaml:"-"`
 
+	// misses is the root's cache of Searches matching nothing, see Search.
+	misses *misses `json:"-" yaml:"-"`
+
+	// derived are the Node's memoized derived fields, see Derive.
+	derived *memo `json:"-" yaml:"-"`
+
 	Path     string     `json:"path" yaml:"path"`
 	Dirname  string     `json:"dirname" yaml:"dirname"`
 	Name     string     `json:"name" yaml:"name"`
 	Type     Descriptor `json:"type" yaml:"type"`
-	Checksum *string    `json:"checksum,omitempty" yaml:"checksum,omitempty"`
-	Nodes    []Node     `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	Target   string     `json:"target" yaml:"target"`
+	Size     int64      `json:"size" yaml:"size"`
+	Modified time.Time  `json:"modified" yaml:"modified"`
+
+	// Times are the Node's full-precision timestamps, see WithForensic.
+	Times *Times `json:"times" yaml:"times"`
+
+	// Inode and Mode are the Node's inode number and ls(1)-style mode, e.g. "-rwxr-xr-x", see WithForensic.
+	Inode uint64 `json:"inode" yaml:"inode"`
+	Mode  string `json:"mode" yaml:"mode"`
+
+	Owner *Owner `json:"owner" yaml:"owner"`
+
+	// Flags are the names of the Node's file flags, e.g. macOS "hidden" or "uchg", see WithMetadata.
+	Flags []string `json:"flags" yaml:"flags"`
+
+	// Xattrs are the names of the Node's extended attributes, e.g. "com.apple.ResourceFork", see WithMetadata.
+	Xattrs []string `json:"xattrs" yaml:"xattrs"`
+
+	// Context is the Node's SELinux security context, see WithContexts.
+	Context string `json:"context" yaml:"context"`
+
+	// Capabilities are the file's capabilities in getcap(8) notation, e.g. "cap_net_bind_service=ep".
+	Capabilities string `json:"capabilities" yaml:"capabilities"`
+
+	Tags     []string `json:"tags" yaml:"tags"`
+	Checksum *string  `json:"checksum" yaml:"checksum"`
+
+	// Algorithm labels the Checksum; sampled fingerprints are labeled e.g. "sampled-sha256".
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+
+	// Digests are the additional file digests keyed by algorithm, see WithDigests.
+	Digests map[string]string `json:"digests" yaml:"digests"`
+
+	// Annotations are arbitrary key-value metadata attached by callers via Annotate.
+	Annotations map[string]string `json:"annotations" yaml:"annotations"`
+
+	// Errors are the non-fatal errors encountered while walking the Node.
+	Errors []string `json:"errors" yaml:"errors"`
+
+	Nodes []Node `json:"nodes" yaml:"nodes"`
 }
 
 func (n *Node) String() string {
 	return n.JSON()
 }
 
-func (n *Node) JSON() string {
-	buffer, e := json.MarshalIndent(n, "", "    ")
-	if e != nil {
-		panic(e)
+// Annotate attaches a key-value annotation to the Node.
+func (n *Node) Annotate(key, value string) {
+	if n.Annotations == nil {
+		n.Annotations = map[string]string{}
 	}
 
-	return string(buffer)
-}
-
-func (n *Node) YAML() string {
-	buffer, e := yaml.Marshal(n)
-	if e != nil {
-		panic(e)
-	}
-
-	return string(buffer)
+	n.Annotations[key] = value
 }
 
 func (n *Node) Root() *Node {
@@ -84,6 +159,46 @@ func (n *Node) Parent() *Node {
 	return n.parent
 }
 
+// Index returns the Node's stable position among its parent's children, which are ordered by
+// name; the root's Index is zero.
+func (n *Node) Index() int {
+	return n.position
+}
+
+// Depth returns how many directory levels the Node is below its root; the root's Depth is zero.
+func (n *Node) Depth() int {
+	return n.depth
+}
+
+// Children returns the Node's children ordered by name, identically across runs on an unchanged tree.
+func (n *Node) Children() []*Node {
+	children := make([]*Node, 0, len(n.Nodes))
+	for i := range n.Nodes {
+		child := &n.Nodes[i]
+		if live, valid := n.table[child.Path]; valid {
+			child = live
+		}
+
+		children = append(children, child)
+	}
+
+	return children
+}
+
+// Each calls fn for the Node and every descendant in depth-first, pre-order sequence, with children
+// ordered by name. Returning false from fn skips the Node's descendants.
+//
+//   - Unlike ranging over Map or Table, the iteration order of Each is deterministic.
+func (n *Node) Each(fn func(node *Node) bool) {
+	if !(fn(n)) {
+		return
+	}
+
+	for _, child := range n.Children() {
+		child.Each(fn)
+	}
+}
+
 func (n *Node) Permissions() os.FileMode {
 	info, e := os.Stat(n.Path)
 	if e != nil {
@@ -95,7 +210,7 @@ func (n *Node) Permissions() os.FileMode {
 
 func (n *Node) Files() []*Node {
 	var partials = make([]*Node, 0)
-	for _, node := range n.Table() {
+	for _, node := range n.Children() {
 		if node.Type == File {
 			partials = append(partials, node)
 		}
@@ -106,7 +221,7 @@ func (n *Node) Files() []*Node {
 
 func (n *Node) Directories() []*Node {
 	var partials = make([]*Node, 0)
-	for _, node := range n.Table() {
+	for _, node := range n.Children() {
 		if node.Type == Directory {
 			partials = append(partials, node)
 		}
@@ -115,6 +230,56 @@ func (n *Node) Directories() []*Node {
 	return partials
 }
 
+// FilesRecursive returns every Node of Type File in the current node's subtree, sorted by path.
+func (n *Node) FilesRecursive() []*Node {
+	return n.recursive(File)
+}
+
+// DirectoriesRecursive returns every Node of Type Directory in the current node's subtree, sorted by path.
+//
+//   - Parent directories always sort before their children.
+func (n *Node) DirectoriesRecursive() []*Node {
+	return n.recursive(Directory)
+}
+
+func (n *Node) recursive(descriptor Descriptor) []*Node {
+	var partials = make([]*Node, 0)
+	n.collect(descriptor, &partials)
+
+	sort.Slice(partials, func(i, j int) bool {
+		return partials[i].Path < partials[j].Path
+	})
+
+	return partials
+}
+
+func (n *Node) collect(descriptor Descriptor, partials *[]*Node) {
+	for _, node := range n.Table() {
+		if node.Type == descriptor {
+			*partials = append(*partials, node)
+		}
+
+		if node.Type == Directory {
+			node.collect(descriptor, partials)
+		}
+	}
+}
+
+// CountFiles returns the number of files in the Node's subtree, computed during the walk.
+func (n *Node) CountFiles() int {
+	return n.counts.files
+}
+
+// CountDirectories returns the number of directories in the Node's subtree, excluding itself.
+func (n *Node) CountDirectories() int {
+	return n.counts.directories
+}
+
+// CountBytes returns the total size of the files in the Node's subtree.
+func (n *Node) CountBytes() int64 {
+	return n.counts.bytes
+}
+
 // URI returns the full-system, absolute path of the Node instance.
 func (n *Node) URI() (path string) {
 	path, e := filepath.Abs(n.Path)
@@ -126,8 +291,10 @@ func (n *Node) URI() (path string) {
 }
 
 // Map returns a hash-map of all nodes from the node's absolute root.
+//
+//   - Map iteration order is random; see Each for deterministic iteration.
 func (n *Node) Map() map[string]*Node {
-	return n.Root().table
+	return n.Root().lookup
 }
 
 // Table returns the current node's hash-map of child nodes.
@@ -139,58 +306,67 @@ func (n *Node) Table() map[string]*Node {
 // substring matches.
 //
 //   - Note that the search function will only evaluate the current Node instance's table.
+//   - Matches are returned in the order of Children.
+//   - Searches matching nothing are cached until Invalidate; trees only change while they're walked.
 func (n *Node) Search(descriptor string) (nodes []*Node) {
-	table := n.Table()
-	for key, node := range table {
-		if strings.Contains(key, descriptor) {
+	misses := n.Root().misses
+
+	key := n.Path + "\x00" + descriptor
+	if misses.has(key) {
+		return
+	}
+
+	for _, node := range n.Children() {
+		if strings.Contains(node.Path, descriptor) {
 			nodes = append(nodes, node)
 		}
 	}
 
-	return
-}
-
-// Contents returns a Node of Type File's file contents.
-func (n *Node) Contents() ([]byte, error) {
-	if n == nil {
-		return nil, ExceptionNilNode
-	} else if n.Type != File {
-		return nil, ExceptionInvalidFileNode
-	} else {
-		n.read()
+	if len(nodes) == 0 {
+		misses.add(key)
 	}
 
-	return n.content, nil
+	return
 }
 
 // Copy will copy the Node instance's directories and files to the destination.
 //
 //   - Copy will not overwrite existing files.
 //   - Copy will not overwrite existing directory or file permissions.
+//   - Copy strips the setuid, setgid, and sticky bits of files, unless WithPreserveSpecial.
+//   - Copy fails on unreadable files, unless WithUnreadable skips them; as do Replicate and Replace.
+//   - Copy assigns WithChown's owner, and WithRules' modes, to the files and directories it creates;
+//     Replicate and Replace to all of them.
+//   - Copy writes only the files within WithMaxBytes' budget, listing the rest in Omitted; as do Replicate
+//     and Replace.
+//   - Copy panics with ExceptionReadOnly on trees walked WithReadOnly; as do Replicate and Replace.
 func (n *Node) Copy(destination string) {
-	directories := n.Directories()
-	files := n.Files()
+	n.mutate("copy")
 
-	for _, directory := range directories {
+	directories := n.DirectoriesRecursive()
+	files := n.FilesRecursive()
+
+	created := make([]*Node, 0, len(directories)+1)
+	for _, directory := range append([]*Node{n}, directories...) {
 		target := filepath.Join(destination, directory.Path)
-		if e := os.MkdirAll(target, directory.Permissions()); e != nil {
-			panic(e)
+		if _, exception := os.Lstat(target); errors.Is(exception, os.ErrNotExist) {
+			created = append(created, directory)
 		}
+
+		n.options.mkdir(target, directory.Permissions())
 	}
 
+	missing := make([]*Node, 0, len(files))
 	for _, file := range files {
-		target := filepath.Join(destination, file.Path)
-		if _, exception := os.Stat(target); errors.Is(exception, os.ErrNotExist) {
-			contents, e := file.Contents()
-			if e != nil {
-				panic(e)
-			}
-
-			if e := os.WriteFile(target, contents, file.Permissions()); e != nil {
-				panic(e)
-			}
+		if _, exception := os.Stat(filepath.Join(destination, file.Path)); errors.Is(exception, os.ErrNotExist) {
+			missing = append(missing, file)
 		}
 	}
+
+	written := n.write(destination, n.options.budget(missing))
+
+	n.settle(destination, created)
+	n.preserve(destination, written, directories)
 }
 
 // Replicate will copy the Node instance's directories and files to the destination.
@@ -198,72 +374,143 @@ func (n *Node) Copy(destination string) {
 //   - Replicate will overwrite existing files.
 //   - Replicate will not overwrite existing directory or file permissions.
 func (n *Node) Replicate(destination string) {
-	directories := n.Directories()
-	files := n.Files()
+	n.mutate("replicate")
+
+	directories := n.DirectoriesRecursive()
+	files := n.FilesRecursive()
+
+	n.options.mkdir(filepath.Join(destination, n.Path), n.Permissions())
 
 	for _, directory := range directories {
 		target := filepath.Join(destination, directory.Path)
-		if e := os.MkdirAll(target, directory.Permissions()); e != nil {
-			panic(e)
-		}
+		n.options.mkdir(target, directory.Permissions())
 	}
 
-	for _, file := range files {
-		target := filepath.Join(destination, file.Path)
-		contents, e := file.Contents()
-		if e != nil {
-			panic(e)
-		}
+	written := n.write(destination, n.options.budget(files))
 
-		if e := os.WriteFile(target, contents, file.Permissions()); e != nil {
-			panic(e)
-		}
-	}
+	n.settle(destination, append([]*Node{n}, directories...))
+	n.preserve(destination, written, directories)
 }
 
 // Replace will copy the Node instance's directories and files to the destination.
 //
 //   - Replace will overwrite existing files.
 //   - Replace will overwrite existing directory and file permissions.
+//   - Replace is atomic: content is staged alongside the destination, then renamed into place.
+//   - Replace fails with ExceptionImmutable if the destination holds immutable or append-only paths,
+//     unless WithHandleImmutable clears the flags, reapplying them once replaced.
 func (n *Node) Replace(destination string) {
-	if exists(destination) {
-		if e := os.RemoveAll(destination); e != nil {
+	n.mutate("replace")
+
+	locked, e := locks(destination)
+	if e != nil {
+		panic(e)
+	}
+
+	if e := n.options.unlock(destination, locked); e != nil {
+		panic(e)
+	}
+
+	defer func() {
+		if e := relock(locked); e != nil {
 			panic(e)
 		}
+	}()
+
+	w, e := workspace.New(destination)
+	if e != nil {
+		panic(e)
 	}
 
-	directories := n.Directories()
-	files := n.Files()
+	defer w.Close()
+
+	if e := os.Chmod(w.Path, n.Permissions()); e != nil {
+		panic(e)
+	}
+
+	directories := n.DirectoriesRecursive()
+	files := n.FilesRecursive()
+
+	n.options.mkdir(filepath.Join(w.Path, n.Path), n.Permissions())
 
 	for _, directory := range directories {
-		target := filepath.Join(destination, directory.Path)
-		if e := os.MkdirAll(target, directory.Permissions()); e != nil {
-			panic(e)
-		}
+		target := filepath.Join(w.Path, directory.Path)
+		n.options.mkdir(target, directory.Permissions())
 	}
 
-	for _, file := range files {
-		target := filepath.Join(destination, file.Path)
-		contents, e := file.Contents()
-		if e != nil {
-			panic(e)
-		}
+	written := n.write(w.Path, n.options.budget(files))
 
-		if e := os.WriteFile(target, contents, file.Permissions()); e != nil {
-			panic(e)
-		}
+	n.options.chown(w.Path)
+	n.settle(w.Path, append([]*Node{n}, directories...))
+	n.preserve(w.Path, written, directories)
+
+	if e := w.Commit(); e != nil {
+		panic(e)
 	}
 }
 
-// read will read-in the Node file-contents if of Type File.
-func (n *Node) read() {
-	if n != nil && n.Type == File && n.content == nil {
-		buffer, e := os.ReadFile(n.URI())
+// hash calculates the Node's checksum, sampling files larger than the configured threshold, and
+// tree-hashing files larger than the parallel threshold. Additional digests are calculated in the same
+// read pass as full checksums, or in a second pass alongside tree digests. Files the user can't read,
+// including those WithFaults denies, record an error rather than failing the walk. WithoutHashing, nothing
+// is read.
+func (n *Node) hash() {
+	if n.options.Unhashed {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if e, valid := r.(error); valid && errors.Is(e, fs.ErrPermission) {
+				n.Errors = append(n.Errors, e.Error())
+				return
+			}
+
+			panic(r)
+		}
+	}()
+
+	if e := n.options.inject(OperationRead, n.URI(), n.Size); e != nil {
+		panic(exception.New(exception.EREAD, "checksum", n.URI(), e))
+	}
+
+	algorithm := n.options.Algorithm
+	if n.options.SampleThreshold > 0 && n.Size > n.options.SampleThreshold {
+		n.Checksum = checksum.Sample(n.URI(), algorithm, n.options.SampleSize)
+		n.Algorithm = algorithm.Sampled()
+	} else if n.options.ParallelThreshold > 0 && n.Size > n.options.ParallelThreshold {
+		n.Checksum = checksum.Tree(n.URI(), algorithm, n.options.ChunkSize, n.options.Concurrency)
+		n.Algorithm = algorithm.Tree(n.options.ChunkSize)
+
+		if len(n.options.Digests) > 0 {
+			digests, e := checksum.Multi(n.URI(), n.options.Digests...)
+			if e != nil {
+				n.Errors = append(n.Errors, e.Error())
+				return
+			}
+
+			n.Digests = make(map[string]string, len(digests))
+			for extra, digest := range digests {
+				n.Digests[string(extra)] = digest
+			}
+		}
+	} else if len(n.options.Digests) == 0 {
+		n.Checksum = checksum.Hash(n.URI(), algorithm)
+		n.Algorithm = string(algorithm)
+	} else {
+		digests, e := checksum.Multi(n.URI(), append([]checksum.Algorithm{algorithm}, n.options.Digests...)...)
 		if e != nil {
-			panic(e)
+			n.Errors = append(n.Errors, e.Error())
+			return
 		}
 
-		n.content = buffer
+		sum := digests[algorithm]
+		n.Checksum = &sum
+		n.Algorithm = string(algorithm)
+		n.Digests = make(map[string]string, len(n.options.Digests))
+		for _, extra := range n.options.Digests {
+			n.Digests[string(extra)] = digests[extra]
+		}
 	}
 }
 
@@ -271,15 +518,28 @@ func (n *Node) add(child *Node) {
 	child.parent = n
 	child.depth = n.depth + 1
 	child.table = map[string]*Node{}
+	child.derived = &memo{}
+	child.options = n.options
+	child.options.Progress.At(child.Path)
+	child.Tags = child.options.tags(child.Name, child.Path)
+	child.capture()
 
 	if child.Type == Directory {
-		child.walk()
-	} else if child.Type == File {
-		child.Checksum = checksum.SHA256(child.URI())
+		if child.options.MaxDepth == 0 || child.depth < child.options.MaxDepth {
+			child.walk()
+		}
+
+		if child.options.Filter != nil && child.counts.files == 0 {
+			child.drop()
+			return
+		}
+	} else if child.Type == File && child.options.Concurrency == nil {
+		child.hash()
+		child.options.Progress.Advance(1, child.Size)
 	}
 
-	// update root table
-	rt := n.Root().table
+	// update root index
+	rt := n.Root().lookup
 	if _, valid := rt[child.Path]; !(valid) {
 		rt[child.Path] = child
 	}
@@ -290,13 +550,31 @@ func (n *Node) add(child *Node) {
 		nt[child.Path] = child
 	}
 
+	n.counts.tally(child)
+
+	child.position = len(n.Nodes)
 	n.Nodes = append(n.Nodes, *child)
 }
 
+// drop removes the Node's subtree, holding no files, from the root index; the Node itself was never indexed.
+func (n *Node) drop() {
+	rt := n.Root().lookup
+	for _, child := range n.Children() {
+		child.Each(func(node *Node) bool {
+			delete(rt, node.Path)
+			return true
+		})
+	}
+}
+
 func (n *Node) walk() {
-	entries, e := os.ReadDir(n.Path)
+	entries, e := n.options.list(n.Path)
 	if e != nil {
-		fmt.Printf("error reading %s: %s\n", n.Path, e.Error())
+		n.Errors = append(n.Errors, e.Error())
+		return
+	}
+
+	if n.options.skips(n, entries) {
 		return
 	}
 
@@ -305,6 +583,10 @@ func (n *Node) walk() {
 		path := filepath.Join(n.Path, name)
 		dirname := filepath.Dir(path)
 
+		if n.options.excluded(name, path) || n.options.dumpless(path, entry) {
+			continue
+		}
+
 		var child = &Node{
 			Name:    name,
 			Dirname: dirname,
@@ -312,21 +594,55 @@ func (n *Node) walk() {
 			Nodes:   make([]Node, 0),
 		}
 
+		info, e := n.options.stat(path, entry)
+		if e == nil {
+			child.Owner = owner(info)
+			child.Modified = info.ModTime()
+
+			if n.options.Forensic {
+				child.Times = times(path, info)
+				child.Inode = inumber(info)
+				child.Mode = symbolic(info.Mode())
+			}
+
+			if info.Mode().IsRegular() {
+				child.Size = info.Size()
+			}
+		} else {
+			child.Errors = append(child.Errors, e.Error())
+		}
+
 		if (entry.Type() & os.ModeSymlink) == os.ModeSymlink {
 			child.Type = Symbolic
-			// dereference, e := os.Readlink(filepath.Join(n.Path, entry.Name()))
-			// if e != nil {
-			// 	fmt.Printf("error reading link: %s\n", e.Error())
-			// } else {
-			// 	child.Path = dereference
-			// }
+
+			if dereference, e := os.Readlink(path); e != nil {
+				child.Errors = append(child.Errors, e.Error())
+			} else {
+				child.Target = dereference
+			}
 		} else if entry.IsDir() {
 			child.Type = Directory
 		} else {
 			child.Type = File
+
+			if !(n.options.keeps(n, child)) {
+				continue
+			}
+
+			if n.options.MaxFiles > 0 && n.options.files >= n.options.MaxFiles {
+				continue
+			}
+
+			n.options.files++
 		}
 
 		n.add(child)
+
+		if child.Type == File && n.options.Concurrency != nil && !(n.options.Unhashed) {
+			n.options.pending = append(n.options.pending, deferred{file: child, before: info})
+		} else if child.Type == File {
+			n.options.verify(path, info)
+		}
 	}
 }
 
@@ -344,26 +660,88 @@ func exists(path string) bool {
 	return false
 }
 
-func New(path string) *Node {
+// New walks the directory at path and returns the root Node of its tree.
+//
+//   - New panics if path isn't a directory; see Walk for an error-returning alternative.
+func New(path string, settings ...Option) *Node {
+	root, e := Walk(path, settings...)
+	if e != nil && !(errors.Is(e, ExceptionWalkPartial)) {
+		panic(e)
+	}
+
+	return root
+}
+
+// Walk walks the directory at path and returns the root Node of its tree. If any node
+// recorded non-fatal Errors, the complete tree is returned along with an EWALKPARTIAL error.
+func Walk(path string, settings ...Option) (*Node, error) {
 	descriptor, e := os.Stat(path)
-	if e != nil || !(descriptor.IsDir()) {
-		panic(ExceptionInvalidDirectory)
+	if e != nil {
+		return nil, exception.New(exception.ENOTDIR, "walk", path, e)
+	} else if !(descriptor.IsDir()) {
+		return nil, exception.New(exception.ENOTDIR, "walk", path, nil)
 	}
 
 	dirname := filepath.Dir(descriptor.Name())
 	root := &Node{
 		table:  map[string]*Node{},
+		lookup: map[string]*Node{},
+		misses: &misses{},
 		parent: nil,
 		depth:  0,
 
-		Dirname: dirname,
-		Name:    descriptor.Name(),
-		Path:    path,
-		Type:    Directory,
-		Nodes:   make([]Node, 0),
+		derived: &memo{},
+
+		options: options(settings...),
+
+		Dirname:  dirname,
+		Name:     descriptor.Name(),
+		Path:     path,
+		Type:     Directory,
+		Owner:    owner(descriptor),
+		Modified: descriptor.ModTime(),
+		Nodes:    make([]Node, 0),
+	}
+
+	if root.options.Forensic {
+		root.Times = times(path, descriptor)
+		root.Inode = inumber(descriptor)
+		root.Mode = symbolic(descriptor.Mode())
 	}
 
 	root.walk()
 
-	return root
+	// the walk indexes the nodes it built, while parents hold copies of them in Nodes; link indexes those
+	// copies instead, so that hashing deferred WithConcurrency lands on Nodes too
+	root.link()
+	for i, d := range root.options.pending {
+		root.options.pending[i].file = root.lookup[d.file.Path]
+	}
+
+	root.options.digest()
+
+	if e := root.options.violated(path); e != nil {
+		return root, e
+	}
+
+	if partial := root.partial(); partial > 0 {
+		return root, exception.New(exception.EWALKPARTIAL, "walk", path, fmt.Errorf("%d node(s) recorded errors", partial))
+	}
+
+	return root, nil
+}
+
+// partial returns how many nodes of the tree recorded errors.
+func (n *Node) partial() (count int) {
+	if len(n.Errors) > 0 {
+		count++
+	}
+
+	for _, node := range n.Map() {
+		if len(node.Errors) > 0 {
+			count++
+		}
+	}
+
+	return
 }
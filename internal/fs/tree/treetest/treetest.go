@@ -0,0 +1,214 @@
+// Package treetest provides a deterministic file-system double for testing users of package tree: a
+// fixture tree, with failures injected into its walks, hashes, and copies, so that error handling is
+// testable without root or real broken disks.
+package treetest
+
+import (
+	"cli/internal/fs/tree"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// Epoch is the modification time of every fixture file and directory.
+var Epoch = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// rule represents a fault injected into the operations matching a pattern.
+type rule struct {
+	operations []tree.Operation
+	pattern    string
+	e          error
+	delay      time.Duration
+}
+
+// FS represents a fixture tree at Root and the faults injected into its operations. Faults are matched
+// against an operation's path, relative to Root or to a copy's destination, or its base name. FS is safe
+// for concurrent use.
+type FS struct {
+	Root string
+
+	mutex    sync.Mutex
+	rules    []rule
+	capacity int64
+	written  int64
+	calls    []string
+}
+
+// New returns the FS of a fixture tree in a temporary directory of tb, removed once the test completes.
+// Files maps slash-separated paths to their contents; paths ending in "/" are directories. Files have
+// mode 0644, directories 0755, and every one Epoch as its modification time.
+func New(tb testing.TB, files map[string]string) *FS {
+	tb.Helper()
+
+	f := &FS{Root: filepath.Join(tb.TempDir(), "root"), capacity: -1}
+	if e := os.Mkdir(f.Root, 0o755); e != nil {
+		tb.Fatal(e)
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		target := filepath.Join(f.Root, filepath.FromSlash(path))
+		if e := os.MkdirAll(filepath.Dir(target), 0o755); e != nil {
+			tb.Fatal(e)
+		}
+
+		if strings.HasSuffix(path, "/") {
+			if e := os.MkdirAll(target, 0o755); e != nil {
+				tb.Fatal(e)
+			}
+		} else if e := os.WriteFile(target, []byte(files[path]), 0o644); e != nil {
+			tb.Fatal(e)
+		} else if e := os.Chtimes(target, Epoch, Epoch); e != nil {
+			tb.Fatal(e)
+		}
+	}
+
+	var directories []string
+	filepath.WalkDir(f.Root, func(path string, entry fs.DirEntry, e error) error {
+		if e == nil && entry.IsDir() {
+			directories = append(directories, path)
+		}
+
+		return nil
+	})
+
+	// deepest first, as setting a directory's children touches it
+	for i := len(directories) - 1; i >= 0; i-- {
+		if e := os.Chtimes(directories[i], Epoch, Epoch); e != nil {
+			tb.Fatal(e)
+		}
+	}
+
+	return f
+}
+
+// Option returns the tree.Option injecting the FS's faults, for the walks of Root.
+func (f *FS) Option() tree.Option {
+	return tree.WithFaults(f)
+}
+
+// Fail fails the operations ops (all of them if none are given) on paths matching pattern with e.
+func (f *FS) Fail(pattern string, e error, ops ...tree.Operation) *FS {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.rules = append(f.rules, rule{operations: ops, pattern: pattern, e: e})
+
+	return f
+}
+
+// Deny fails listing and reading paths matching pattern with EACCES, as for a user without permission.
+func (f *FS) Deny(pattern string) *FS {
+	return f.Fail(pattern, syscall.EACCES, tree.OperationList, tree.OperationRead)
+}
+
+// Slow delays every read of paths matching pattern by delay.
+func (f *FS) Slow(pattern string, delay time.Duration) *FS {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.rules = append(f.rules, rule{operations: []tree.Operation{tree.OperationRead}, pattern: pattern, delay: delay})
+
+	return f
+}
+
+// Full fails writes with ENOSPC once they'd exceed capacity bytes in total, as for a full destination.
+func (f *FS) Full(capacity int64) *FS {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.capacity, f.written = capacity, 0
+
+	return f
+}
+
+// Calls returns every intercepted operation, in order, as "operation path" lines.
+func (f *FS) Calls() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return append([]string{}, f.calls...)
+}
+
+func (f *FS) Inject(op tree.Operation, path string, bytes int64) error {
+	f.mutex.Lock()
+
+	f.calls = append(f.calls, string(op)+" "+path)
+
+	var delay time.Duration
+	var failure error
+	for _, r := range f.rules {
+		if !(r.applies(op)) || !(f.match(r.pattern, path)) {
+			continue
+		}
+
+		delay += r.delay
+		if failure == nil && r.e != nil {
+			failure = r.e
+		}
+	}
+
+	if failure == nil && op == tree.OperationWrite && f.capacity >= 0 {
+		if f.written+bytes > f.capacity {
+			failure = syscall.ENOSPC
+		} else {
+			f.written += bytes
+		}
+	}
+
+	f.mutex.Unlock()
+
+	time.Sleep(delay)
+
+	if failure != nil {
+		return &fs.PathError{Op: string(op), Path: path, Err: failure}
+	}
+
+	return nil
+}
+
+// applies reports whether the rule intercepts op.
+func (r rule) applies(op tree.Operation) bool {
+	if len(r.operations) == 0 {
+		return true
+	}
+
+	for _, operation := range r.operations {
+		if operation == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match reports whether pattern matches path, relative to Root or, for paths outside it, to the copied
+// Root's position within a destination, or path's base name.
+func (f *FS) match(pattern, path string) bool {
+	candidates := []string{filepath.Base(path)}
+	if relative, e := filepath.Rel(f.Root, path); e == nil && !(strings.HasPrefix(relative, "..")) {
+		candidates = append(candidates, filepath.ToSlash(relative))
+	} else if _, suffix, found := strings.Cut(filepath.ToSlash(path), filepath.ToSlash(f.Root)+"/"); found {
+		candidates = append(candidates, suffix)
+	}
+
+	for _, candidate := range candidates {
+		if matched, _ := filepath.Match(pattern, candidate); matched {
+			return true
+		}
+	}
+
+	return false
+}
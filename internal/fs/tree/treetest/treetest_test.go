@@ -0,0 +1,215 @@
+package treetest_test
+
+import (
+	"cli/internal/exception"
+	"cli/internal/fs/concurrency"
+	"cli/internal/fs/tree"
+	"cli/internal/fs/tree/treetest"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+var fixture = map[string]string{
+	"README.md":        "# fixture\n",
+	"src/main.go":      "package main\n",
+	"src/util/util.go": "package util\n",
+	"secrets/key.pem":  "-----BEGIN-----\n",
+}
+
+// code returns a sentinel of code, matching any typed exception of it with errors.Is.
+func code(c exception.Code) error {
+	return exception.New(c, "", "", nil)
+}
+
+// recovered returns the error fn panicked with, if any.
+func recovered(fn func()) (e error) {
+	defer func() {
+		if r := recover(); r != nil {
+			e = r.(error)
+		}
+	}()
+
+	fn()
+
+	return nil
+}
+
+func TestDeniedDirectoryIsRecorded(t *testing.T) {
+	f := treetest.New(t, fixture).Deny("secrets")
+
+	n, e := tree.Walk(f.Root, f.Option())
+	if !(errors.Is(e, tree.ExceptionWalkPartial)) {
+		t.Fatalf("walk returned %v; expected EWALKPARTIAL", e)
+	}
+
+	secrets := n.Map()[filepath.Join(f.Root, "secrets")]
+	if secrets == nil || len(secrets.Errors) != 1 || !(strings.Contains(secrets.Errors[0], "permission denied")) {
+		t.Fatalf("secrets recorded %v", secrets)
+	}
+
+	if n.CountFiles() != 3 {
+		t.Fatalf("walked %d files beside the denied directory; expected 3", n.CountFiles())
+	}
+}
+
+func TestDeniedFileIsUnhashed(t *testing.T) {
+	f := treetest.New(t, fixture).Deny("key.pem")
+
+	n, e := tree.Walk(f.Root, f.Option())
+	if !(errors.Is(e, tree.ExceptionWalkPartial)) {
+		t.Fatalf("walk returned %v; expected EWALKPARTIAL", e)
+	}
+
+	key := n.Map()[filepath.Join(f.Root, "secrets", "key.pem")]
+	if key.Checksum != nil || len(key.Errors) != 1 || !(strings.Contains(key.Errors[0], "permission denied")) {
+		t.Fatalf("key.pem hashed as %v, recording %v", key.Checksum, key.Errors)
+	}
+}
+
+func TestFailedReadFailsHashing(t *testing.T) {
+	f := treetest.New(t, fixture).Fail("src/main.go", syscall.EIO, tree.OperationRead)
+
+	e := recovered(func() {
+		tree.Walk(f.Root, f.Option())
+	})
+
+	if !(errors.Is(e, code(exception.EREAD))) || !(errors.Is(e, syscall.EIO)) {
+		t.Fatalf("walk failed with %v; expected EREAD of EIO", e)
+	}
+}
+
+func TestUnhashedWalkReadsNothing(t *testing.T) {
+	for _, settings := range [][]tree.Option{nil, {tree.WithConcurrency(concurrency.Fixed(2))}} {
+		f := treetest.New(t, fixture).Fail("*", syscall.EIO, tree.OperationRead)
+
+		n, e := tree.Walk(f.Root, append(settings, f.Option(), tree.WithoutHashing())...)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		for _, file := range n.FilesRecursive() {
+			if file.Checksum != nil || file.Algorithm != "" || len(file.Errors) > 0 {
+				t.Fatalf("%s hashed as %s %v, recording %v", file.Path, file.Algorithm, file.Checksum, file.Errors)
+			}
+		}
+	}
+}
+
+func TestFailedStatIsRecorded(t *testing.T) {
+	f := treetest.New(t, fixture).Fail("util.go", syscall.ESTALE, tree.OperationStat)
+
+	n, e := tree.Walk(f.Root, f.Option())
+	if !(errors.Is(e, tree.ExceptionWalkPartial)) {
+		t.Fatalf("walk returned %v; expected EWALKPARTIAL", e)
+	}
+
+	util := n.Map()[filepath.Join(f.Root, "src", "util", "util.go")]
+	if len(util.Errors) == 0 || !(strings.Contains(util.Errors[0], "stale")) {
+		t.Fatalf("util.go recorded %v", util.Errors)
+	}
+}
+
+func TestSlowReadsDelayHashing(t *testing.T) {
+	f := treetest.New(t, fixture).Slow("src/*", 20*time.Millisecond)
+
+	start := time.Now()
+	if _, e := tree.Walk(f.Root, f.Option()); e != nil {
+		t.Fatal(e)
+	}
+
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Fatalf("walk took %v; expected the slow read's delay", elapsed)
+	}
+
+	var reads []string
+	for _, call := range f.Calls() {
+		if strings.HasPrefix(call, "read ") {
+			reads = append(reads, call)
+		}
+	}
+
+	if len(reads) != 4 {
+		t.Fatalf("intercepted reads %v; expected one per file", reads)
+	}
+}
+
+func TestFullDestinationFailsCopy(t *testing.T) {
+	for _, settings := range [][]tree.Option{nil, {tree.WithNeverFollow()}} {
+		f := treetest.New(t, fixture).Full(20)
+
+		n, e := tree.Walk(f.Root, append(settings, f.Option())...)
+		if e != nil {
+			t.Fatal(e)
+		}
+
+		// the copy creates the destination's missing parents, which rollback removes as well
+		parent := t.TempDir()
+		e = n.Transact(filepath.Join(parent, "nested", "destination"), (*tree.Node).Copy)
+		if !(errors.Is(e, code(exception.EWRITE))) || !(errors.Is(e, syscall.ENOSPC)) {
+			t.Fatalf("copy returned %v; expected EWRITE of ENOSPC", e)
+		}
+
+		if entries, _ := os.ReadDir(parent); len(entries) != 0 {
+			t.Fatalf("failed copy left %d entries behind", len(entries))
+		}
+	}
+}
+
+func TestFailedMkdirFailsCopy(t *testing.T) {
+	f := treetest.New(t, fixture).Fail("src/util", syscall.EROFS, tree.OperationMkdir)
+
+	n, e := tree.Walk(f.Root, f.Option())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	e = n.Transact(t.TempDir(), (*tree.Node).Copy)
+	if !(errors.Is(e, code(exception.EWRITE))) || !(errors.Is(e, syscall.EROFS)) {
+		t.Fatalf("copy returned %v; expected EWRITE of EROFS", e)
+	}
+}
+
+func TestDeniedReadFailsCopy(t *testing.T) {
+	f := treetest.New(t, fixture)
+
+	n, e := tree.Walk(f.Root, f.Option())
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	f.Deny("key.pem")
+
+	e = n.Transact(t.TempDir(), (*tree.Node).Copy)
+	if !(errors.Is(e, code(exception.EREAD))) || !(errors.Is(e, os.ErrPermission)) {
+		t.Fatalf("copy returned %v; expected EREAD of EACCES", e)
+	}
+}
+
+func TestDeniedReadIsSkippedByPolicy(t *testing.T) {
+	f := treetest.New(t, fixture)
+
+	n, e := tree.Walk(f.Root, f.Option(), tree.WithUnreadable(tree.UnreadableRecord))
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	f.Deny("key.pem")
+
+	destination := t.TempDir()
+	if e := n.Transact(destination, (*tree.Node).Copy); e != nil {
+		t.Fatal(e)
+	}
+
+	if _, e := os.Stat(filepath.Join(destination, f.Root, "secrets", "key.pem")); !(errors.Is(e, os.ErrNotExist)) {
+		t.Fatalf("unreadable file copied: %v", e)
+	}
+
+	if _, e := os.Stat(filepath.Join(destination, f.Root, "src", "main.go")); e != nil {
+		t.Fatal(e)
+	}
+}
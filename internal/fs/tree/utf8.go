@@ -0,0 +1,76 @@
+package tree
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Escape returns s with each byte of its invalid UTF-8 sequences written as a \xNN escape, a safe
+// representation of file names that aren't UTF-8 for documents and terminals. Valid strings are
+// returned as they are.
+func Escape(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+
+	var builder strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			fmt.Fprintf(&builder, `\x%02x`, s[i])
+		} else {
+			builder.WriteString(s[i : i+size])
+		}
+
+		i += size
+	}
+
+	return builder.String()
+}
+
+// Valid reports whether the Node's name is valid UTF-8.
+func (n *Node) Valid() bool {
+	return utf8.ValidString(n.Name)
+}
+
+// raw returns the base64-encoded raw bytes of the Node's path, dirname, name, and target that
+// aren't valid UTF-8, keyed by attribute, or nil when every one is valid.
+func (n *Node) raw() map[string]string {
+	var raw map[string]string
+	for _, f := range []field{{"path", n.Path}, {"dirname", n.Dirname}, {"name", n.Name}, {"target", n.Target}} {
+		if value := f.value.(string); !(utf8.ValidString(value)) {
+			if raw == nil {
+				raw = map[string]string{}
+			}
+
+			raw[f.key] = base64.StdEncoding.EncodeToString([]byte(value))
+		}
+	}
+
+	return raw
+}
+
+// restore replaces the escaped attributes of a decoded Node with their raw bytes.
+func (n *Node) restore(raw map[string]string) error {
+	for key, value := range raw {
+		buffer, e := base64.StdEncoding.DecodeString(value)
+		if e != nil {
+			return fmt.Errorf("raw %s of %s: %w", key, n.Path, e)
+		}
+
+		switch key {
+		case "path":
+			n.Path = string(buffer)
+		case "dirname":
+			n.Dirname = string(buffer)
+		case "name":
+			n.Name = string(buffer)
+		case "target":
+			n.Target = string(buffer)
+		}
+	}
+
+	return nil
+}
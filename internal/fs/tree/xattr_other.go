@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package tree
+
+// xattrs returns the extended attribute names of the file at path; unsupported on this platform.
+func xattrs(path string) ([]string, error) {
+	return nil, nil
+}
+
+// copyxattrs copies the extended attributes of source onto target; unsupported on this platform.
+func copyxattrs(source, target string, capabilities bool) error {
+	return nil
+}
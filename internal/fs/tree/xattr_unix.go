@@ -0,0 +1,69 @@
+//go:build linux || darwin
+
+package tree
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"sort"
+
+	"golang.org/x/sys/unix"
+)
+
+// xattrs returns the sorted extended attribute names of the file at path, without following symbolic links.
+func xattrs(path string) ([]string, error) {
+	size, e := unix.Llistxattr(path, nil)
+	if errors.Is(e, unix.ENOTSUP) {
+		return nil, nil
+	} else if e != nil || size == 0 {
+		return nil, e
+	}
+
+	buffer := make([]byte, size)
+	if size, e = unix.Llistxattr(path, buffer); e != nil {
+		return nil, e
+	}
+
+	var names []string
+	for _, name := range bytes.Split(buffer[:size], []byte{0}) {
+		if len(name) > 0 {
+			names = append(names, string(name))
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// copyxattrs copies the extended attributes of source onto target, except the security context, which
+// WithContexts governs, and unless capabilities is set, the file capabilities.
+func copyxattrs(source, target string, capabilities bool) error {
+	names, e := xattrs(source)
+	if e != nil {
+		return e
+	}
+
+	for _, name := range names {
+		if name == selinux || (name == "security.capability" && !(capabilities)) {
+			continue
+		}
+
+		size, e := unix.Lgetxattr(source, name, nil)
+		if e != nil {
+			return e
+		}
+
+		value := make([]byte, size)
+		if size, e = unix.Lgetxattr(source, name, value); e != nil {
+			return e
+		}
+
+		if e := unix.Lsetxattr(target, name, value[:size], 0); e != nil {
+			return &os.PathError{Op: "setxattr " + name, Path: target, Err: e}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package volume represents the file-system type and capacity of the volume holding a path.
+package volume
@@ -0,0 +1,103 @@
+package volume
+
+import (
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Preflight represents the capacity context of copying a tree from a source onto a destination volume.
+type Preflight struct {
+	Source      string `json:"source" yaml:"source"`
+	Destination string `json:"destination" yaml:"destination"`
+	Files       int    `json:"files" yaml:"files"`
+	Bytes       uint64 `json:"bytes" yaml:"bytes"`
+
+	// SourceVolume and DestinationVolume are nil where volume information is unsupported.
+	SourceVolume      *Volume `json:"source-volume,omitempty" yaml:"source-volume,omitempty"`
+	DestinationVolume *Volume `json:"destination-volume,omitempty" yaml:"destination-volume,omitempty"`
+}
+
+// Check returns the Preflight of copying files totalling bytes from source to destination.
+func Check(source, destination string, files int, bytes uint64) (*Preflight, error) {
+	p := &Preflight{Source: source, Destination: destination, Files: files, Bytes: bytes}
+
+	var e error
+	if p.SourceVolume, e = Of(source); e != nil && !(errors.Is(e, ExceptionUnsupported)) {
+		return nil, e
+	}
+
+	if p.DestinationVolume, e = Of(destination); e != nil && !(errors.Is(e, ExceptionUnsupported)) {
+		return nil, e
+	}
+
+	return p, nil
+}
+
+// Volatile reports whether the destination volume is volatile.
+func (p *Preflight) Volatile() bool {
+	return p.DestinationVolume != nil && p.DestinationVolume.Volatile()
+}
+
+// Sufficient reports whether the copy fits the destination: within its available space, or half
+// of it for volatile volumes. Unknown destination volumes are assumed sufficient.
+func (p *Preflight) Sufficient() bool {
+	if p.DestinationVolume == nil {
+		return true
+	}
+
+	limit := p.DestinationVolume.Available
+	if p.Volatile() {
+		limit /= 2
+	}
+
+	return p.Bytes <= limit
+}
+
+func (p *Preflight) JSON() string {
+	buffer, e := json.MarshalIndent(p, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (p *Preflight) YAML() string {
+	buffer, e := yaml.Marshal(p)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing preflight report to w.
+func (p *Preflight) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\n", i18n.T(i18n.PreflightCopy), i18n.T(i18n.PreflightFiles, p.Files, render.Bytes(int64(p.Bytes))))
+
+	for _, side := range []struct {
+		label i18n.Message
+		path  string
+		v     *Volume
+	}{{i18n.PreflightSource, p.Source, p.SourceVolume}, {i18n.PreflightDestination, p.Destination, p.DestinationVolume}} {
+		if side.v == nil {
+			fmt.Fprintf(t, "%s\t%s\n", i18n.T(side.label), i18n.T(i18n.PreflightUnknown, side.path))
+			continue
+		}
+
+		fmt.Fprintf(t, "%s\t%s\n", i18n.T(side.label), i18n.T(i18n.PreflightVolume, side.path, side.v.Type,
+			render.Bytes(int64(side.v.Available)), render.Bytes(int64(side.v.Total)), side.v.FreeInodes, side.v.Inodes))
+	}
+
+	fmt.Fprintf(t, "%s\t%t\n", i18n.T(i18n.PreflightSufficient), p.Sufficient())
+
+	t.Flush()
+}
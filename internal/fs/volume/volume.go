@@ -0,0 +1,59 @@
+package volume
+
+import (
+	"cli/internal/exception"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+var ExceptionUnsupported = exception.New(exception.EUNSUPPORTED, "", "", nil)
+
+// Volume represents the file-system type and capacity of a mounted volume.
+type Volume struct {
+	Type       string `json:"type" yaml:"type"`
+	Total      uint64 `json:"total" yaml:"total"`
+	Free       uint64 `json:"free" yaml:"free"`
+	Available  uint64 `json:"available" yaml:"available"`
+	Inodes     uint64 `json:"inodes,omitempty" yaml:"inodes,omitempty"`
+	FreeInodes uint64 `json:"free-inodes,omitempty" yaml:"free-inodes,omitempty"`
+}
+
+// volatile are the file-system types backed by memory or a container's writable layer.
+var volatile = map[string]bool{
+	"tmpfs":   true,
+	"ramfs":   true,
+	"overlay": true,
+}
+
+// Volatile reports whether the volume is backed by memory (tmpfs, ramfs) or an overlay
+// upper layer, typically of limited capacity and discarded with its container.
+func (v *Volume) Volatile() bool {
+	return volatile[v.Type]
+}
+
+// Of returns the Volume holding path. Destinations that don't exist yet resolve to their
+// nearest existing ancestor.
+func Of(path string) (*Volume, error) {
+	absolute, e := filepath.Abs(path)
+	if e != nil {
+		return nil, e
+	}
+
+	for {
+		if _, e := os.Stat(absolute); e == nil {
+			break
+		} else if !(errors.Is(e, os.ErrNotExist)) {
+			return nil, e
+		}
+
+		parent := filepath.Dir(absolute)
+		if parent == absolute {
+			break
+		}
+
+		absolute = parent
+	}
+
+	return statfs(absolute)
+}
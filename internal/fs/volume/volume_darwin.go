@@ -0,0 +1,34 @@
+//go:build darwin
+
+package volume
+
+import (
+	"syscall"
+)
+
+func statfs(path string) (*Volume, error) {
+	var stat syscall.Statfs_t
+	if e := syscall.Statfs(path, &stat); e != nil {
+		return nil, e
+	}
+
+	name := make([]byte, 0, len(stat.Fstypename))
+	for _, c := range stat.Fstypename {
+		if c == 0 {
+			break
+		}
+
+		name = append(name, byte(c))
+	}
+
+	size := uint64(stat.Bsize)
+
+	return &Volume{
+		Type:       string(name),
+		Total:      stat.Blocks * size,
+		Free:       stat.Bfree * size,
+		Available:  stat.Bavail * size,
+		Inodes:     stat.Files,
+		FreeInodes: stat.Ffree,
+	}, nil
+}
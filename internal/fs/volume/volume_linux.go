@@ -0,0 +1,52 @@
+//go:build linux
+
+package volume
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// types maps statfs(2) magic numbers to file-system type names.
+var types = map[int64]string{
+	0x01021994: "tmpfs",
+	0x858458f6: "ramfs",
+	0x794c7630: "overlay",
+	0xef53:     "ext4",
+	0x58465342: "xfs",
+	0x9123683e: "btrfs",
+	0x2fc12fc1: "zfs",
+	0x6969:     "nfs",
+	0xff534d42: "cifs",
+	0x65735546: "fuse",
+	0x73717368: "squashfs",
+	0x4d44:     "vfat",
+	0x5346544e: "ntfs",
+	0x9fa0:     "proc",
+}
+
+func statfs(path string) (*Volume, error) {
+	var stat syscall.Statfs_t
+	if e := syscall.Statfs(path, &stat); e != nil {
+		return nil, e
+	}
+
+	// magic numbers are 32 bits, but Type is a signed int32 on 32-bit platforms, sign-extending the likes of cifs
+	magic := int64(uint32(stat.Type))
+
+	name, valid := types[magic]
+	if !(valid) {
+		name = fmt.Sprintf("0x%x", magic)
+	}
+
+	size := uint64(stat.Bsize)
+
+	return &Volume{
+		Type:       name,
+		Total:      uint64(stat.Blocks) * size,
+		Free:       uint64(stat.Bfree) * size,
+		Available:  uint64(stat.Bavail) * size,
+		Inodes:     uint64(stat.Files),
+		FreeInodes: uint64(stat.Ffree),
+	}, nil
+}
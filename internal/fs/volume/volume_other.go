@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package volume
+
+import "cli/internal/exception"
+
+func statfs(path string) (*Volume, error) {
+	return nil, exception.New(exception.EUNSUPPORTED, "statfs", path, nil)
+}
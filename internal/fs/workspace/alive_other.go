@@ -0,0 +1,8 @@
+//go:build !unix
+
+package workspace
+
+// alive reports true: without process probing, staging directories are only removed once Stale.
+func alive(pid int) bool {
+	return true
+}
@@ -0,0 +1,15 @@
+//go:build unix
+
+package workspace
+
+import (
+	"errors"
+	"syscall"
+)
+
+// alive reports whether the process with the given pid is still running.
+func alive(pid int) bool {
+	e := syscall.Kill(pid, 0)
+
+	return e == nil || errors.Is(e, syscall.EPERM)
+}
@@ -0,0 +1,4 @@
+// Package workspace represents temporary staging directories created on the same
+// file-system as their destination, so staged trees can be committed by renaming them into place,
+// atomically exchanged with the destination where the platform supports it.
+package workspace
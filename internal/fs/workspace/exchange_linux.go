@@ -0,0 +1,24 @@
+//go:build linux
+
+package workspace
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// exchange atomically swaps the paths a and b with renameat2(RENAME_EXCHANGE); errors.ErrUnsupported if the
+// kernel or file system can't.
+func exchange(a, b string) error {
+	e := unix.Renameat2(unix.AT_FDCWD, a, unix.AT_FDCWD, b, unix.RENAME_EXCHANGE)
+	switch {
+	case e == nil:
+		return nil
+	case errors.Is(e, unix.ENOSYS), errors.Is(e, unix.EINVAL):
+		return errors.ErrUnsupported
+	}
+
+	return &os.LinkError{Op: "exchange", Old: a, New: b, Err: e}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package workspace
+
+import "errors"
+
+// exchange swaps the paths a and b; unsupported on this platform.
+func exchange(a, b string) error {
+	return errors.ErrUnsupported
+}
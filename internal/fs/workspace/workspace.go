@@ -0,0 +1,223 @@
+package workspace
+
+import (
+	"cli/internal/exception"
+	"cli/internal/fs/remove"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefix is the name prefix of every staging directory.
+const Prefix = ".cli-staging-"
+
+// owner is the name of the file recording the staging directory's creating process.
+const owner = ".owner"
+
+// Stale is the age after which the janitor removes staging directories regardless of their owner.
+var Stale = 24 * time.Hour
+
+// NeverFollow removes staging and replaced directories without traversing symbolic links beneath their parent.
+var NeverFollow = false
+
+var ExceptionCommitted = exception.New(exception.ECOMMITTED, "", "", nil)
+
+// Workspace represents a staging directory for a single destination.
+type Workspace struct {
+	// Path is the staging directory; content staged under it is moved to the destination on Commit.
+	Path string
+
+	destination string
+	committed   bool
+}
+
+var (
+	mutex  sync.Mutex
+	active = map[*Workspace]struct{}{}
+)
+
+// New creates a Workspace staging directory alongside destination, removing stale
+// staging directories left behind by crashed processes beforehand.
+func New(destination string) (*Workspace, error) {
+	absolute, e := filepath.Abs(destination)
+	if e != nil {
+		return nil, e
+	}
+
+	parent := filepath.Dir(absolute)
+	if e := os.MkdirAll(parent, 0o755); e != nil {
+		return nil, e
+	}
+
+	if _, e := Janitor(parent); e != nil {
+		return nil, e
+	}
+
+	path, e := os.MkdirTemp(parent, Prefix+filepath.Base(absolute)+"-")
+	if e != nil {
+		return nil, e
+	}
+
+	if e := os.WriteFile(filepath.Join(path, owner), []byte(strconv.Itoa(os.Getpid())), 0o600); e != nil {
+		removeAll(path)
+		return nil, e
+	}
+
+	w := &Workspace{Path: path, destination: absolute}
+
+	mutex.Lock()
+	active[w] = struct{}{}
+	mutex.Unlock()
+
+	return w, nil
+}
+
+// Commit replaces the destination with the staged content. On Linux, an existing destination is atomically
+// exchanged with the staging directory; elsewhere, or on file systems without renameat2(RENAME_EXCHANGE), it's
+// renamed aside before the staging directory is renamed into place, briefly leaving no destination.
+func (w *Workspace) Commit() error {
+	if w.committed {
+		return exception.New(exception.ECOMMITTED, "commit", w.destination, nil)
+	}
+
+	if e := os.Remove(filepath.Join(w.Path, owner)); e != nil {
+		return e
+	}
+
+	var trash string
+	if _, e := os.Lstat(w.destination); e == nil {
+		// the staging directory now holds the replaced destination
+		if e := exchange(w.Path, w.destination); e == nil {
+			w.committed = true
+			w.release()
+
+			return removeAll(w.Path)
+		} else if !(errors.Is(e, errors.ErrUnsupported)) {
+			return e
+		}
+
+		trash = w.Path + ".trash"
+		if e := os.Rename(w.destination, trash); e != nil {
+			return e
+		}
+	}
+
+	if e := os.Rename(w.Path, w.destination); e != nil {
+		if trash != "" {
+			os.Rename(trash, w.destination)
+		}
+
+		return e
+	}
+
+	w.committed = true
+	w.release()
+
+	if trash != "" {
+		return removeAll(trash)
+	}
+
+	return nil
+}
+
+// Close removes the staging directory, unless it was committed.
+func (w *Workspace) Close() error {
+	w.release()
+
+	if w.committed {
+		return nil
+	}
+
+	return removeAll(w.Path)
+}
+
+func (w *Workspace) release() {
+	mutex.Lock()
+	delete(active, w)
+	mutex.Unlock()
+}
+
+// Release closes every open Workspace; it's intended for exit and signal handlers.
+func Release() {
+	mutex.Lock()
+	workspaces := make([]*Workspace, 0, len(active))
+	for w := range active {
+		workspaces = append(workspaces, w)
+	}
+	mutex.Unlock()
+
+	for _, w := range workspaces {
+		w.Close()
+	}
+}
+
+// Janitor removes the stale staging directories in directory: those whose creating process
+// is no longer alive, or which are older than Stale. It returns the removed paths.
+func Janitor(directory string) (removed []string, e error) {
+	entries, e := os.ReadDir(directory)
+	if e != nil {
+		return nil, e
+	}
+
+	for _, entry := range entries {
+		if !(entry.IsDir()) || !(strings.HasPrefix(entry.Name(), Prefix)) {
+			continue
+		}
+
+		path := filepath.Join(directory, entry.Name())
+		if !(stale(path)) {
+			continue
+		}
+
+		if e := removeAll(path); e != nil {
+			return removed, fmt.Errorf("unable to remove stale workspace %s: %w", path, e)
+		}
+
+		removed = append(removed, path)
+	}
+
+	return removed, nil
+}
+
+func stale(path string) bool {
+	info, e := os.Stat(path)
+	if e != nil {
+		return false
+	}
+
+	if time.Since(info.ModTime()) > Stale {
+		return true
+	}
+
+	buffer, e := os.ReadFile(filepath.Join(path, owner))
+	if e != nil {
+		// the owner file is written immediately after creation; give the creator a moment.
+		return time.Since(info.ModTime()) > time.Minute
+	}
+
+	pid, e := strconv.Atoi(strings.TrimSpace(string(buffer)))
+	if e != nil {
+		return true
+	}
+
+	return pid != os.Getpid() && !(alive(pid))
+}
+
+// removeAll removes path and everything beneath it, honoring NeverFollow.
+func removeAll(path string) error {
+	if !(NeverFollow) {
+		return os.RemoveAll(path)
+	}
+
+	root, e := remove.Open(filepath.Dir(path))
+	if e != nil {
+		return e
+	}
+
+	return root.RemoveAll(path)
+}
@@ -0,0 +1,42 @@
+package workspace_test
+
+import (
+	"cli/internal/fs/workspace"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommitReplacesDestination(t *testing.T) {
+	parent := t.TempDir()
+	destination := filepath.Join(parent, "destination")
+	if e := os.MkdirAll(filepath.Join(destination, "old"), 0o755); e != nil {
+		t.Fatal(e)
+	}
+
+	w, e := workspace.New(destination)
+	if e != nil {
+		t.Fatal(e)
+	}
+
+	if e := os.WriteFile(filepath.Join(w.Path, "new"), []byte("staged"), 0o644); e != nil {
+		t.Fatal(e)
+	}
+
+	if e := w.Commit(); e != nil {
+		t.Fatal(e)
+	}
+
+	entries, e := os.ReadDir(destination)
+	if e != nil || len(entries) != 1 || entries[0].Name() != "new" {
+		t.Fatalf("destination holds %v (%v); expected only the staged file", entries, e)
+	}
+
+	if entries, _ := os.ReadDir(parent); len(entries) != 1 {
+		t.Fatalf("commit left %d entries beside the destination", len(entries)-1)
+	}
+
+	if e := w.Commit(); e == nil {
+		t.Fatal("committed twice")
+	}
+}
@@ -0,0 +1,2 @@
+// Package i18n represents the message catalog of human-facing output.
+package i18n
@@ -0,0 +1,519 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Language represents a supported catalog language.
+type Language string
+
+const (
+	English Language = "en"
+	Spanish Language = "es"
+	German  Language = "de"
+)
+
+// Message represents a catalog key of a human-facing string.
+type Message string
+
+const (
+	ErrorExecution        Message = "error.execution"
+	ConfigValid           Message = "config.valid"
+	ConfigIssues          Message = "config.issues"
+	SnapshotIdentical     Message = "snapshot.identical"
+	ChecksumVerified      Message = "checksum.verified"
+	CopyVolatile          Message = "copy.volatile"
+	CopyForce             Message = "copy.force"
+	CopySkipped           Message = "copy.skipped"
+	CopyOmitted           Message = "copy.omitted"
+	ExplainUnknownProfile Message = "explain.unknown-profile"
+	ExplainInvalidHasher  Message = "explain.invalid-hasher"
+	ExplainImmutable      Message = "explain.immutable"
+	ExplainSymlink        Message = "explain.symlink"
+	ExplainMissingKey     Message = "explain.missing-key"
+	ReportFiles           Message = "report.files"
+	ReportBytes           Message = "report.bytes"
+	ReportDirectories     Message = "report.directories"
+	ReportID              Message = "report.id"
+	ReportUser            Message = "report.user"
+	ReportGroup           Message = "report.group"
+	ReportUnowned         Message = "report.unowned"
+	ReportSummary         Message = "report.summary"
+	ReportListed          Message = "report.listed"
+	ReportRoot            Message = "report.root"
+	ReportSample          Message = "report.sample"
+	ReportUnsampled       Message = "report.unsampled"
+	ReportSampled         Message = "report.sampled"
+	ReportInterval        Message = "report.interval"
+	GroupingExtension     Message = "grouping.extension"
+	GroupingDirectory     Message = "grouping.directory"
+	GroupingTag           Message = "grouping.tag"
+	GroupingOwner         Message = "grouping.owner"
+	GroupNoExtension      Message = "group.no-extension"
+	GroupUntagged         Message = "group.untagged"
+	GroupUnknownOwner     Message = "group.unknown-owner"
+	DiffAdded             Message = "diff.added"
+	DiffRemoved           Message = "diff.removed"
+	DiffModified          Message = "diff.modified"
+	DiffTotals            Message = "diff.totals"
+	DiffCollapsed         Message = "diff.collapsed"
+	BrowseHelp            Message = "browse.help"
+	ChecklistHelp         Message = "checklist.help"
+	ChecklistStatus       Message = "checklist.status"
+	ActivityHour          Message = "activity.hour"
+	ActivityDay           Message = "activity.day"
+	ActivityWeek          Message = "activity.week"
+	ActivityMonth         Message = "activity.month"
+	ActivityOlder         Message = "activity.older"
+	ActivityUnknown       Message = "activity.unknown"
+	ReportPath            Message = "report.path"
+	LintRule              Message = "lint.rule"
+	LintDetail            Message = "lint.detail"
+	LintRawName           Message = "lint.raw-name"
+	ReportSize            Message = "report.size"
+	ReportChecksum        Message = "report.checksum"
+	DuplicatesDuplicate   Message = "duplicates.duplicate"
+	DuplicatesReclaimable Message = "duplicates.reclaimable"
+	DuplicatesClone       Message = "duplicates.clone"
+	DuplicatesShares      Message = "duplicates.shares"
+	DuplicatesTotals      Message = "duplicates.totals"
+	FanoutDestination     Message = "fanout.destination"
+	FanoutStatus          Message = "fanout.status"
+	FanoutCopied          Message = "fanout.copied"
+	FanoutRolledBack      Message = "fanout.rolled-back"
+	FanoutTotals          Message = "fanout.totals"
+	ChunksDeduplication   Message = "chunks.deduplication"
+	ChunksStored          Message = "chunks.stored"
+	ChunksSavings         Message = "chunks.savings"
+	ChunksNone            Message = "chunks.none"
+	ChunksWholeFile       Message = "chunks.whole-file"
+	ChunksTotals          Message = "chunks.totals"
+	ReportUnreadable      Message = "report.unreadable"
+	WarmTotals            Message = "warm.totals"
+	WarmSkipped           Message = "warm.skipped"
+	WarmError             Message = "warm.error"
+	PruneWouldDelete      Message = "prune.would-delete"
+	PruneDeleted          Message = "prune.deleted"
+	PruneFailed           Message = "prune.failed"
+	PruneKept             Message = "prune.kept"
+	PruneDryRun           Message = "prune.dry-run"
+	PruneTotals           Message = "prune.totals"
+	HeatmapSummary        Message = "heatmap.summary"
+	HeatmapTransient      Message = "heatmap.transient"
+	HeatmapChanges        Message = "heatmap.changes"
+	HeatmapClass          Message = "heatmap.class"
+	HeatmapLast           Message = "heatmap.last"
+	HeatmapSince          Message = "heatmap.since"
+	PermissionsMode       Message = "permissions.mode"
+	PermissionsDrift      Message = "permissions.drift"
+	PermissionsBaseline   Message = "permissions.baseline"
+	PermissionsActual     Message = "permissions.actual"
+	PermissionsSummary    Message = "permissions.summary"
+	RepoProject           Message = "repo.project"
+	RepoState             Message = "repo.state"
+	RepoRevision          Message = "repo.revision"
+	RepoAt                Message = "repo.at"
+	RepoUnexpected        Message = "repo.unexpected"
+	ExportStore           Message = "export.store"
+	ExportReused          Message = "export.reused"
+	PreflightCopy         Message = "preflight.copy"
+	PreflightSource       Message = "preflight.source"
+	PreflightFiles        Message = "preflight.files"
+	PreflightUnknown      Message = "preflight.unknown"
+	PreflightVolume       Message = "preflight.volume"
+	PreflightSufficient   Message = "preflight.sufficient"
+	PreflightDestination  Message = "preflight.destination"
+	UsageSummary          Message = "usage.summary"
+	ConcurrencyFixed      Message = "concurrency.fixed"
+	ConcurrencyAdaptive   Message = "concurrency.adaptive"
+	ConcurrencyReport     Message = "concurrency.report"
+)
+
+var catalog = map[Language]map[Message]string{
+	English: {
+		ErrorExecution:        "Whoops. There was an error while executing your CLI '%s'",
+		ConfigValid:           "%s: valid",
+		ConfigIssues:          "%d configuration issue(s) found",
+		SnapshotIdentical:     "identical configuration: %s",
+		ChecksumVerified:      "%s: OK",
+		CopyVolatile:          "warning: destination %s is on %s with %s available; copying %s",
+		CopyForce:             "%s of %s would exhaust the destination; use --force to copy anyways",
+		CopySkipped:           "warning: skipped unreadable file %s",
+		CopyOmitted:           "warning: omitted %s, beyond the %s budget",
+		ExplainUnknownProfile: "The selected profile is not defined in the configuration file; check --profile, CLI_PROFILE, and the file's profiles section.",
+		ExplainInvalidHasher:  "The hasher is not supported; choose one of md5, sha1, sha256, sha512, or blake3.",
+		ExplainImmutable:      "The destination holds immutable or append-only paths; clear them with `chattr -i -a` (`chflags nouchg nouappnd` on macOS), or retry with --handle-immutable to clear and reapply them.",
+		ExplainSymlink:        "A symbolic link was found in a path being deleted under --never-follow; inspect it, as it may have been planted to redirect the deletion.",
+		ExplainMissingKey:     "No HMAC key was given; pass --key-file, or set the CLI_HMAC_KEY environment variable.",
+		ReportFiles:           "files",
+		ReportBytes:           "bytes",
+		ReportDirectories:     "directories",
+		ReportID:              "id",
+		ReportUser:            "user",
+		ReportGroup:           "group",
+		ReportUnowned:         "%d file(s) without captured ownership",
+		ReportSummary:         "%s: %d files, %d directories, %s",
+		ReportListed:          "listed",
+		ReportRoot:            "root",
+		ReportSample:          "sampled",
+		ReportUnsampled:       "%d directories, too few to sample",
+		ReportSampled:         "%d directories, %d of %d frontier subtrees sampled (%.0f%%)",
+		ReportInterval:        "~%v (%.0f%% CI %v - %v)",
+		GroupingExtension:     "extension",
+		GroupingDirectory:     "directory",
+		GroupingTag:           "tag",
+		GroupingOwner:         "owner",
+		GroupNoExtension:      "(none)",
+		GroupUntagged:         "(untagged)",
+		GroupUnknownOwner:     "(unknown)",
+		DiffAdded:             "added",
+		DiffRemoved:           "removed",
+		DiffModified:          "modified",
+		DiffTotals:            "%d added, %d removed, %d modified",
+		DiffCollapsed:         "[%d changed]",
+		BrowseHelp:            "j/k move  space toggle  l/h expand/collapse  n/N next/previous change  a/c expand all/changes  q quit",
+		ChecklistHelp:         "j/k move  space toggle  l/h expand/collapse  a/x all/none  enter confirm  q cancel",
+		ChecklistStatus:       "%d of %d files, %s selected",
+		ActivityHour:          "hour",
+		ActivityDay:           "day",
+		ActivityWeek:          "week",
+		ActivityMonth:         "month",
+		ActivityOlder:         "older",
+		ActivityUnknown:       "unknown",
+		ReportPath:            "path",
+		LintRule:              "rule",
+		LintDetail:            "detail",
+		LintRawName:           "raw name %s",
+		ReportSize:            "size",
+		ReportChecksum:        "checksum",
+		DuplicatesDuplicate:   "duplicate",
+		DuplicatesReclaimable: "reclaimable",
+		DuplicatesClone:       "clone",
+		DuplicatesShares:      "shares storage with",
+		DuplicatesTotals:      "%s reclaimable by deduplicating, %s already shared by clones and hard links",
+		FanoutDestination:     "destination",
+		FanoutStatus:          "status",
+		FanoutCopied:          "copied",
+		FanoutRolledBack:      "rolled back: %s",
+		FanoutTotals:          "%s (%d files, %s) copied to %d of %d destination(s)",
+		ChunksDeduplication:   "deduplication",
+		ChunksStored:          "stored",
+		ChunksSavings:         "savings",
+		ChunksNone:            "none",
+		ChunksWholeFile:       "whole-file",
+		ChunksTotals:          "%d files in %d chunks, %d unique; deduplication ratio %.2f",
+		ReportUnreadable:      "skipped %s: unreadable",
+		WarmTotals:            "%s: warmed %d files, %s in %.2fs (%s/s)",
+		WarmSkipped:           "skipped",
+		WarmError:             "error",
+		PruneWouldDelete:      "would delete",
+		PruneDeleted:          "deleted",
+		PruneFailed:           "failed: %s",
+		PruneKept:             "kept",
+		PruneDryRun:           "%d file(s), %s would be deleted; re-run with --execute to delete them",
+		PruneTotals:           "%d of %d file(s) deleted",
+		HeatmapSummary:        "%s: %d cycles, %d files with mismatches, buckets of %s",
+		HeatmapTransient:      "transient",
+		HeatmapChanges:        "changes",
+		HeatmapClass:          "class",
+		HeatmapLast:           "last",
+		HeatmapSince:          "heat since %s",
+		PermissionsMode:       "mode",
+		PermissionsDrift:      "drift",
+		PermissionsBaseline:   "baseline",
+		PermissionsActual:     "actual",
+		PermissionsSummary:    "%s: %d checked, %d change(s) from the baseline of %s",
+		RepoProject:           "project",
+		RepoState:             "state",
+		RepoRevision:          "revision",
+		RepoAt:                "%s (at %s)",
+		RepoUnexpected:        "unexpected directory %s",
+		ExportStore:           "store",
+		ExportReused:          "(reused)",
+		PreflightCopy:         "copy",
+		PreflightSource:       "source",
+		PreflightFiles:        "%d files, %s",
+		PreflightUnknown:      "%s (volume unknown)",
+		PreflightVolume:       "%s (%s, %s of %s available, %d of %d inodes free)",
+		PreflightSufficient:   "sufficient",
+		PreflightDestination:  "destination",
+		UsageSummary:          "%s: wall %.2fs, cpu %.2fs user %.2fs system, peak rss %s, %d files (%.1f/s), %s (%s/s)",
+		ConcurrencyFixed:      "fixed",
+		ConcurrencyAdaptive:   "adaptive %d-%d, from %d, lowest %d, peak %d, %d adjustments",
+		ConcurrencyReport:     "%d worker(s) (%s), %d operations at %.2fms mean latency",
+	},
+	Spanish: {
+		ErrorExecution:        "Vaya. Ocurrió un error al ejecutar la CLI '%s'",
+		ConfigValid:           "%s: válido",
+		ConfigIssues:          "se encontraron %d problema(s) de configuración",
+		SnapshotIdentical:     "configuración idéntica: %s",
+		ChecksumVerified:      "%s: correcto",
+		CopyVolatile:          "advertencia: el destino %s está en %s con %s disponibles; copiando %s",
+		CopyForce:             "%s de %s agotaría el destino; use --force para copiar de todos modos",
+		CopySkipped:           "advertencia: se omitió el archivo ilegible %s",
+		CopyOmitted:           "advertencia: se omitió %s, fuera del presupuesto de %s",
+		ExplainUnknownProfile: "El perfil seleccionado no está definido en el archivo de configuración; revise --profile, CLI_PROFILE y la sección profiles del archivo.",
+		ExplainInvalidHasher:  "El algoritmo de hash no es compatible; elija md5, sha1, sha256, sha512 o blake3.",
+		ExplainImmutable:      "El destino contiene rutas inmutables o de solo anexado; elimínelas con `chattr -i -a` (`chflags nouchg nouappnd` en macOS), o reintente con --handle-immutable para quitarlas y reaplicarlas.",
+		ExplainSymlink:        "Se encontró un enlace simbólico en una ruta que se estaba eliminando con --never-follow; revíselo, ya que podría haberse colocado para desviar la eliminación.",
+		ExplainMissingKey:     "No se proporcionó una clave HMAC; use --key-file o defina la variable de entorno CLI_HMAC_KEY.",
+		ReportFiles:           "archivos",
+		ReportBytes:           "bytes",
+		ReportDirectories:     "directorios",
+		ReportID:              "id",
+		ReportUser:            "usuario",
+		ReportGroup:           "grupo",
+		ReportUnowned:         "%d archivo(s) sin propietario capturado",
+		ReportSummary:         "%s: %d archivos, %d directorios, %s",
+		ReportListed:          "listados",
+		ReportRoot:            "raíz",
+		ReportSample:          "muestreados",
+		ReportUnsampled:       "%d directorios, demasiado pocos para muestrear",
+		ReportSampled:         "%d directorios, %d de %d subárboles de la frontera muestreados (%.0f%%)",
+		ReportInterval:        "~%v (IC del %.0f%% %v - %v)",
+		GroupingExtension:     "extensión",
+		GroupingDirectory:     "directorio",
+		GroupingTag:           "etiqueta",
+		GroupingOwner:         "propietario",
+		GroupNoExtension:      "(ninguna)",
+		GroupUntagged:         "(sin etiqueta)",
+		GroupUnknownOwner:     "(desconocido)",
+		DiffAdded:             "añadidos",
+		DiffRemoved:           "eliminados",
+		DiffModified:          "modificados",
+		DiffTotals:            "%d añadidos, %d eliminados, %d modificados",
+		DiffCollapsed:         "[%d cambiados]",
+		BrowseHelp:            "j/k mover  espacio alternar  l/h expandir/contraer  n/N cambio siguiente/anterior  a/c expandir todo/cambios  q salir",
+		ChecklistHelp:         "j/k mover  espacio alternar  l/h expandir/contraer  a/x todo/nada  intro confirmar  q cancelar",
+		ChecklistStatus:       "%d de %d archivos, %s seleccionados",
+		ActivityHour:          "hora",
+		ActivityDay:           "día",
+		ActivityWeek:          "semana",
+		ActivityMonth:         "mes",
+		ActivityOlder:         "más antiguo",
+		ActivityUnknown:       "desconocido",
+		ReportPath:            "ruta",
+		LintRule:              "regla",
+		LintDetail:            "detalle",
+		LintRawName:           "nombre sin procesar %s",
+		ReportSize:            "tamaño",
+		ReportChecksum:        "suma de verificación",
+		DuplicatesDuplicate:   "duplicado",
+		DuplicatesReclaimable: "recuperable",
+		DuplicatesClone:       "clon",
+		DuplicatesShares:      "comparte almacenamiento con",
+		DuplicatesTotals:      "%s recuperables al deduplicar, %s ya compartidos por clones y enlaces físicos",
+		FanoutDestination:     "destino",
+		FanoutStatus:          "estado",
+		FanoutCopied:          "copiado",
+		FanoutRolledBack:      "revertido: %s",
+		FanoutTotals:          "%s (%d archivos, %s) copiado a %d de %d destino(s)",
+		ChunksDeduplication:   "deduplicación",
+		ChunksStored:          "almacenado",
+		ChunksSavings:         "ahorro",
+		ChunksNone:            "ninguna",
+		ChunksWholeFile:       "archivo completo",
+		ChunksTotals:          "%d archivos en %d fragmentos, %d únicos; ratio de deduplicación %.2f",
+		ReportUnreadable:      "se omitió %s: ilegible",
+		WarmTotals:            "%s: se precargaron %d archivos, %s en %.2fs (%s/s)",
+		WarmSkipped:           "omitido",
+		WarmError:             "error",
+		PruneWouldDelete:      "se eliminaría",
+		PruneDeleted:          "eliminado",
+		PruneFailed:           "falló: %s",
+		PruneKept:             "conservado",
+		PruneDryRun:           "se eliminarían %d archivo(s), %s; vuelva a ejecutar con --execute para eliminarlos",
+		PruneTotals:           "%d de %d archivo(s) eliminados",
+		HeatmapSummary:        "%s: %d ciclos, %d archivos con discrepancias, intervalos de %s",
+		HeatmapTransient:      "transitorias",
+		HeatmapChanges:        "cambios",
+		HeatmapClass:          "clase",
+		HeatmapLast:           "última",
+		HeatmapSince:          "actividad desde %s",
+		PermissionsMode:       "modo",
+		PermissionsDrift:      "desviación",
+		PermissionsBaseline:   "referencia",
+		PermissionsActual:     "actual",
+		PermissionsSummary:    "%s: %d comprobados, %d cambio(s) respecto a la referencia de %s",
+		RepoProject:           "proyecto",
+		RepoState:             "estado",
+		RepoRevision:          "revisión",
+		RepoAt:                "%s (en %s)",
+		RepoUnexpected:        "directorio inesperado %s",
+		ExportStore:           "almacén",
+		ExportReused:          "(reutilizado)",
+		PreflightCopy:         "copia",
+		PreflightSource:       "origen",
+		PreflightFiles:        "%d archivos, %s",
+		PreflightUnknown:      "%s (volumen desconocido)",
+		PreflightVolume:       "%s (%s, %s de %s disponibles, %d de %d inodos libres)",
+		PreflightSufficient:   "suficiente",
+		PreflightDestination:  "destino",
+		UsageSummary:          "%s: reloj %.2fs, cpu %.2fs usuario %.2fs sistema, rss máximo %s, %d archivos (%.1f/s), %s (%s/s)",
+		ConcurrencyFixed:      "fijo",
+		ConcurrencyAdaptive:   "adaptativo %d-%d, desde %d, mínimo %d, máximo %d, %d ajustes",
+		ConcurrencyReport:     "%d trabajador(es) (%s), %d operaciones con %.2fms de latencia media",
+	},
+	German: {
+		ErrorExecution:        "Hoppla. Beim Ausführen der CLI ist ein Fehler aufgetreten '%s'",
+		ConfigValid:           "%s: gültig",
+		ConfigIssues:          "%d Konfigurationsproblem(e) gefunden",
+		SnapshotIdentical:     "identische Konfiguration: %s",
+		ChecksumVerified:      "%s: in Ordnung",
+		CopyVolatile:          "Warnung: Ziel %s liegt auf %s mit %s verfügbar; kopiere %s",
+		CopyForce:             "%s von %s würde das Ziel erschöpfen; verwenden Sie --force, um trotzdem zu kopieren",
+		CopySkipped:           "Warnung: nicht lesbare Datei %s übersprungen",
+		CopyOmitted:           "Warnung: %s ausgelassen, außerhalb des Budgets von %s",
+		ExplainUnknownProfile: "Das gewählte Profil ist in der Konfigurationsdatei nicht definiert; prüfen Sie --profile, CLI_PROFILE und den Abschnitt profiles der Datei.",
+		ExplainInvalidHasher:  "Der Hash-Algorithmus wird nicht unterstützt; wählen Sie md5, sha1, sha256, sha512 oder blake3.",
+		ExplainImmutable:      "Das Ziel enthält unveränderliche oder Nur-Anhängen-Pfade; entfernen Sie die Attribute mit `chattr -i -a` (`chflags nouchg nouappnd` unter macOS), oder wiederholen Sie den Vorgang mit --handle-immutable, um sie zu entfernen und erneut anzuwenden.",
+		ExplainSymlink:        "In einem unter --never-follow zu löschenden Pfad wurde ein symbolischer Link gefunden; prüfen Sie ihn, da er platziert worden sein könnte, um das Löschen umzulenken.",
+		ExplainMissingKey:     "Es wurde kein HMAC-Schlüssel angegeben; verwenden Sie --key-file oder setzen Sie die Umgebungsvariable CLI_HMAC_KEY.",
+		ReportFiles:           "Dateien",
+		ReportBytes:           "Bytes",
+		ReportDirectories:     "Verzeichnisse",
+		ReportID:              "ID",
+		ReportUser:            "Benutzer",
+		ReportGroup:           "Gruppe",
+		ReportUnowned:         "%d Datei(en) ohne erfassten Besitzer",
+		ReportSummary:         "%s: %d Dateien, %d Verzeichnisse, %s",
+		ReportListed:          "gelistet",
+		ReportRoot:            "Wurzel",
+		ReportSample:          "Stichprobe",
+		ReportUnsampled:       "%d Verzeichnisse, zu wenige für eine Stichprobe",
+		ReportSampled:         "%d Verzeichnisse, %d von %d Teilbäumen der Grenze als Stichprobe (%.0f%%)",
+		ReportInterval:        "~%v (%.0f%%-KI %v - %v)",
+		GroupingExtension:     "Erweiterung",
+		GroupingDirectory:     "Verzeichnis",
+		GroupingTag:           "Tag",
+		GroupingOwner:         "Besitzer",
+		GroupNoExtension:      "(keine)",
+		GroupUntagged:         "(ohne Tag)",
+		GroupUnknownOwner:     "(unbekannt)",
+		DiffAdded:             "hinzugefügt",
+		DiffRemoved:           "entfernt",
+		DiffModified:          "geändert",
+		DiffTotals:            "%d hinzugefügt, %d entfernt, %d geändert",
+		DiffCollapsed:         "[%d geändert]",
+		BrowseHelp:            "j/k bewegen  Leertaste umschalten  l/h auf-/zuklappen  n/N nächste/vorherige Änderung  a/c alles/Änderungen aufklappen  q beenden",
+		ChecklistHelp:         "j/k bewegen  Leertaste umschalten  l/h auf-/zuklappen  a/x alle/keine  Eingabe bestätigen  q abbrechen",
+		ChecklistStatus:       "%d von %d Dateien, %s ausgewählt",
+		ActivityHour:          "Stunde",
+		ActivityDay:           "Tag",
+		ActivityWeek:          "Woche",
+		ActivityMonth:         "Monat",
+		ActivityOlder:         "älter",
+		ActivityUnknown:       "unbekannt",
+		ReportPath:            "Pfad",
+		LintRule:              "Regel",
+		LintDetail:            "Detail",
+		LintRawName:           "Rohname %s",
+		ReportSize:            "Größe",
+		ReportChecksum:        "Prüfsumme",
+		DuplicatesDuplicate:   "Duplikat",
+		DuplicatesReclaimable: "rückgewinnbar",
+		DuplicatesClone:       "Klon",
+		DuplicatesShares:      "teilt Speicher mit",
+		DuplicatesTotals:      "%s durch Deduplizierung rückgewinnbar, %s bereits durch Klone und harte Links geteilt",
+		FanoutDestination:     "Ziel",
+		FanoutStatus:          "Status",
+		FanoutCopied:          "kopiert",
+		FanoutRolledBack:      "zurückgerollt: %s",
+		FanoutTotals:          "%s (%d Dateien, %s) in %d von %d Ziel(e) kopiert",
+		ChunksDeduplication:   "Deduplizierung",
+		ChunksStored:          "gespeichert",
+		ChunksSavings:         "Ersparnis",
+		ChunksNone:            "keine",
+		ChunksWholeFile:       "ganze Datei",
+		ChunksTotals:          "%d Dateien in %d Blöcken, %d eindeutig; Deduplizierungsrate %.2f",
+		ReportUnreadable:      "%s übersprungen: nicht lesbar",
+		WarmTotals:            "%s: %d Dateien vorgeladen, %s in %.2fs (%s/s)",
+		WarmSkipped:           "übersprungen",
+		WarmError:             "Fehler",
+		PruneWouldDelete:      "würde gelöscht",
+		PruneDeleted:          "gelöscht",
+		PruneFailed:           "fehlgeschlagen: %s",
+		PruneKept:             "behalten",
+		PruneDryRun:           "%d Datei(en), %s würden gelöscht; erneut mit --execute ausführen, um sie zu löschen",
+		PruneTotals:           "%d von %d Datei(en) gelöscht",
+		HeatmapSummary:        "%s: %d Zyklen, %d Dateien mit Abweichungen, Intervalle von %s",
+		HeatmapTransient:      "vorübergehend",
+		HeatmapChanges:        "Änderungen",
+		HeatmapClass:          "Klasse",
+		HeatmapLast:           "zuletzt",
+		HeatmapSince:          "Aktivität seit %s",
+		PermissionsMode:       "Modus",
+		PermissionsDrift:      "Abweichung",
+		PermissionsBaseline:   "Basis",
+		PermissionsActual:     "tatsächlich",
+		PermissionsSummary:    "%s: %d geprüft, %d Änderung(en) gegenüber der Basis von %s",
+		RepoProject:           "Projekt",
+		RepoState:             "Zustand",
+		RepoRevision:          "Revision",
+		RepoAt:                "%s (bei %s)",
+		RepoUnexpected:        "unerwartetes Verzeichnis %s",
+		ExportStore:           "Store",
+		ExportReused:          "(wiederverwendet)",
+		PreflightCopy:         "Kopie",
+		PreflightSource:       "Quelle",
+		PreflightFiles:        "%d Dateien, %s",
+		PreflightUnknown:      "%s (Volume unbekannt)",
+		PreflightVolume:       "%s (%s, %s von %s verfügbar, %d von %d Inodes frei)",
+		PreflightSufficient:   "ausreichend",
+		PreflightDestination:  "Ziel",
+		UsageSummary:          "%s: Laufzeit %.2fs, CPU %.2fs Benutzer %.2fs System, maximaler RSS %s, %d Dateien (%.1f/s), %s (%s/s)",
+		ConcurrencyFixed:      "fest",
+		ConcurrencyAdaptive:   "adaptiv %d-%d, ab %d, niedrigste %d, höchste %d, %d Anpassungen",
+		ConcurrencyReport:     "%d Worker (%s), %d Operationen mit %.2fms mittlerer Latenz",
+	},
+}
+
+var language = Parse(Environment())
+
+// Environment returns the locale of the environment, honoring LC_ALL, LC_MESSAGES, then LANG.
+func Environment() string {
+	for _, variable := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(variable); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Languages returns all supported catalog languages.
+func Languages() []Language {
+	return []Language{English, Spanish, German}
+}
+
+// Parse returns the catalog Language of a locale such as "de_DE.UTF-8", falling back to English.
+func Parse(locale string) Language {
+	locale = strings.ToLower(locale)
+	if index := strings.IndexAny(locale, "_.-@"); index >= 0 {
+		locale = locale[:index]
+	}
+
+	if _, valid := catalog[Language(locale)]; valid {
+		return Language(locale)
+	}
+
+	return English
+}
+
+// Set selects the catalog Language used by T.
+func Set(l Language) {
+	language = l
+}
+
+// T returns the formatted human-facing string of message in the selected Language,
+// falling back to English for untranslated messages.
+func T(message Message, arguments ...any) string {
+	format, valid := catalog[language][message]
+	if !(valid) {
+		format = catalog[English][message]
+	}
+
+	return fmt.Sprintf(format, arguments...)
+}
@@ -0,0 +1,110 @@
+package permissions
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry represents the permissions of a file or directory: its ls(1)-style mode, e.g. "-rw-r-----",
+// and numeric owner. Path is relative to the baseline's root, whose own Entry is ".", and escaped by
+// tree.Escape.
+type Entry struct {
+	Path  string          `json:"path" yaml:"path"`
+	Type  tree.Descriptor `json:"type" yaml:"type"`
+	Mode  string          `json:"mode" yaml:"mode"`
+	Owner *tree.Owner     `json:"owner,omitempty" yaml:"owner,omitempty"`
+}
+
+// Baseline represents the permissions of every file and directory of a tree at a point in time; content
+// isn't recorded.
+type Baseline struct {
+	Root     string    `json:"root" yaml:"root"`
+	Captured time.Time `json:"captured" yaml:"captured"`
+	Entries  []Entry   `json:"entries" yaml:"entries"`
+}
+
+// Capture returns the Baseline of t, in path order. t must be walked tree.WithForensic, capturing modes.
+func Capture(t *tree.Node, now time.Time) *Baseline {
+	b := &Baseline{Root: t.Path, Captured: now, Entries: []Entry{}}
+	t.Each(func(n *tree.Node) bool {
+		b.Entries = append(b.Entries, entry(t, n))
+		return true
+	})
+
+	return b
+}
+
+// entry returns the Entry of n, beneath root.
+func entry(root, n *tree.Node) Entry {
+	path, e := filepath.Rel(root.Path, n.Path)
+	if e != nil {
+		path = n.Path
+	}
+
+	return Entry{Path: tree.Escape(filepath.ToSlash(path)), Type: n.Type, Mode: n.Mode, Owner: n.Owner}
+}
+
+// Read reads the JSON or YAML Baseline document at path.
+func Read(path string) (*Baseline, error) {
+	buffer, e := os.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+
+	b := &Baseline{}
+	if e := yaml.Unmarshal(buffer, b); e != nil {
+		return nil, fmt.Errorf("invalid baseline %s: %w", path, e)
+	} else if len(b.Entries) == 0 {
+		return nil, fmt.Errorf("invalid baseline %s: missing entries", path)
+	}
+
+	return b, nil
+}
+
+func (b *Baseline) JSON() string {
+	buffer, e := json.MarshalIndent(b, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (b *Baseline) YAML() string {
+	buffer, e := yaml.Marshal(b)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing baseline table to w.
+func (b *Baseline) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\t%s\n", i18n.T(i18n.PermissionsMode), i18n.T(i18n.GroupingOwner), i18n.T(i18n.ReportPath))
+	for _, entry := range b.Entries {
+		fmt.Fprintf(t, "%s\t%s\t%s\n", entry.Mode, owner(entry.Owner), entry.Path)
+	}
+
+	t.Flush()
+}
+
+// owner returns the "uid:gid" notation of o, or "-" if it's unknown.
+func owner(o *tree.Owner) string {
+	if o == nil {
+		return "-"
+	}
+
+	return fmt.Sprintf("%d:%d", o.UID, o.GID)
+}
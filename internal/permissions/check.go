@@ -0,0 +1,167 @@
+package permissions
+
+import (
+	"cli/internal/exception"
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kind represents how an Entry drifted from its baseline.
+type Kind string
+
+const (
+	KindMode    Kind = "mode"
+	KindOwner   Kind = "owner"
+	KindType    Kind = "type"
+	KindAdded   Kind = "added"
+	KindRemoved Kind = "removed"
+)
+
+// Change represents an Entry's drift from its baseline; Expected and Actual are empty for added and
+// removed entries respectively.
+type Change struct {
+	Path     string `json:"path" yaml:"path"`
+	Kind     Kind   `json:"kind" yaml:"kind"`
+	Expected string `json:"expected,omitempty" yaml:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty" yaml:"actual,omitempty"`
+
+	// Widened marks a mode granting permissions, or setuid, setgid, or sticky bits, the baseline didn't.
+	Widened bool `json:"widened,omitempty" yaml:"widened,omitempty"`
+}
+
+// Drift represents the permissions drift of a tree from a Baseline, in path order.
+type Drift struct {
+	Root     string   `json:"root" yaml:"root"`
+	Baseline string   `json:"baseline" yaml:"baseline"`
+	Checked  int      `json:"checked" yaml:"checked"`
+	Changes  []Change `json:"changes" yaml:"changes"`
+}
+
+// Check compares the modes, owners, and types of t, walked tree.WithForensic, with the Baseline; contents
+// are ignored. Entries of either missing from the other are reported as added or removed.
+func (b *Baseline) Check(t *tree.Node) *Drift {
+	d := &Drift{Root: t.Path, Baseline: b.Root, Changes: []Change{}}
+
+	expected := make(map[string]Entry, len(b.Entries))
+	for _, entry := range b.Entries {
+		expected[entry.Path] = entry
+	}
+
+	t.Each(func(n *tree.Node) bool {
+		actual := entry(t, n)
+		d.Checked++
+
+		baseline, valid := expected[actual.Path]
+		if !(valid) {
+			d.Changes = append(d.Changes, Change{Path: actual.Path, Kind: KindAdded, Actual: actual.Mode + " " + owner(actual.Owner)})
+			return true
+		}
+
+		delete(expected, actual.Path)
+
+		if baseline.Type != actual.Type {
+			d.Changes = append(d.Changes, Change{Path: actual.Path, Kind: KindType, Expected: string(baseline.Type), Actual: string(actual.Type)})
+			return true
+		}
+
+		if baseline.Mode != actual.Mode {
+			d.Changes = append(d.Changes, Change{Path: actual.Path, Kind: KindMode, Expected: baseline.Mode, Actual: actual.Mode, Widened: widened(baseline.Mode, actual.Mode)})
+		}
+
+		if owner(baseline.Owner) != owner(actual.Owner) {
+			d.Changes = append(d.Changes, Change{Path: actual.Path, Kind: KindOwner, Expected: owner(baseline.Owner), Actual: owner(actual.Owner)})
+		}
+
+		return true
+	})
+
+	for path, entry := range expected {
+		d.Changes = append(d.Changes, Change{Path: path, Kind: KindRemoved, Expected: entry.Mode + " " + owner(entry.Owner)})
+	}
+
+	sort.SliceStable(d.Changes, func(i, j int) bool {
+		return d.Changes[i].Path < d.Changes[j].Path
+	})
+
+	return d
+}
+
+// widened reports whether the ls(1)-style mode actual grants any permission, or special bit, expected doesn't.
+func widened(expected, actual string) bool {
+	for i := 1; i < min(len(expected), len(actual)); i++ {
+		if actual[i] != '-' && expected[i] == '-' {
+			return true
+		} else if strings.IndexByte("sStT", actual[i]) >= 0 && strings.IndexByte("sStT", expected[i]) < 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Err returns an EDRIFT error if anything drifted from the baseline.
+func (d *Drift) Err() error {
+	if len(d.Changes) == 0 {
+		return nil
+	}
+
+	return exception.New(exception.EDRIFT, "check", d.Root, fmt.Errorf("%d change(s) from the baseline of %s", len(d.Changes), d.Baseline))
+}
+
+func (d *Drift) JSON() string {
+	buffer, e := json.MarshalIndent(d, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (d *Drift) YAML() string {
+	buffer, e := yaml.Marshal(d)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing drift table to w; widened modes are marked "mode!".
+func (d *Drift) Text(w io.Writer, style render.Style) {
+	fmt.Fprintln(w, i18n.T(i18n.PermissionsSummary, d.Root, d.Checked, len(d.Changes), d.Baseline))
+	if len(d.Changes) == 0 {
+		return
+	}
+
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", i18n.T(i18n.PermissionsDrift), i18n.T(i18n.ReportPath), i18n.T(i18n.PermissionsBaseline), i18n.T(i18n.PermissionsActual))
+	for _, change := range d.Changes {
+		kind := string(change.Kind)
+		if change.Widened {
+			kind += "!"
+		}
+
+		fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", kind, change.Path, dash(change.Expected), dash(change.Actual))
+	}
+
+	t.Flush()
+}
+
+// dash returns value, or "-" if it's empty.
+func dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
@@ -0,0 +1,2 @@
+// Package permissions represents baselines of a tree's modes and owners, and their drift, for hardening checks.
+package permissions
@@ -0,0 +1,2 @@
+// Package progress represents progress reporting of long-running tree operations.
+package progress
@@ -0,0 +1,260 @@
+package progress
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Totals represents the expected amount of work, typically from a pre-scan.
+type Totals struct {
+	Files int   `json:"files"`
+	Bytes int64 `json:"bytes"`
+}
+
+// Mode represents how progress is drawn.
+type Mode string
+
+const (
+	// ModeNone disables progress reporting.
+	ModeNone Mode = "none"
+
+	// ModeBar redraws a single animated status line: a spinner, or a bar with percentage and ETA when totals are known.
+	ModeBar Mode = "bar"
+
+	// ModeLines prints periodic, line-oriented status messages for screen readers and dumb terminals.
+	ModeLines Mode = "lines"
+
+	// ModeJSON prints periodic, machine-parsable Records as JSON lines, for wrapping UIs to render their own progress.
+	ModeJSON Mode = "json"
+)
+
+var (
+	spinner = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+	// interval is the minimum duration between redraws, per Mode.
+	interval = map[Mode]time.Duration{
+		ModeBar:   100 * time.Millisecond,
+		ModeLines: 2 * time.Second,
+		ModeJSON:  time.Second,
+	}
+)
+
+// Progress represents the progress of a single phase of work. A nil *Progress is valid and reports nothing.
+type Progress struct {
+	mutex sync.Mutex
+
+	w    io.Writer
+	mode Mode
+
+	phase   string
+	path    string
+	totals  *Totals
+	files   int
+	bytes   int64
+	started time.Time
+	drawn   time.Time
+	frame   int
+}
+
+// New returns a Progress drawn to w in the given Mode; ModeNone returns nil.
+func New(w io.Writer, mode Mode) *Progress {
+	if mode == ModeNone || mode == "" {
+		return nil
+	}
+
+	return &Progress{w: w, mode: mode}
+}
+
+// Start begins a phase of work; totals may be nil when no pre-scan was performed.
+func (p *Progress) Start(phase string, totals *Totals) {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.phase, p.path, p.totals = phase, "", totals
+	p.files, p.bytes = 0, 0
+	p.started = time.Now()
+	p.drawn = time.Time{}
+
+	if p.mode == ModeJSON {
+		p.drawn = p.started
+		p.draw(false)
+	}
+}
+
+// Advance records completed work, redrawing at most once per the Mode's interval.
+func (p *Progress) Advance(files int, bytes int64) {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.files += files
+	p.bytes += bytes
+
+	if now := time.Now(); now.Sub(p.drawn) >= interval[p.mode] {
+		p.drawn = now
+		p.draw(false)
+	}
+}
+
+// At records path as the phase's current position; it isn't drawn, but is reported by State.
+func (p *Progress) At(path string) {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	p.path = path
+	p.mutex.Unlock()
+}
+
+// State represents a point-in-time copy of a phase's progress.
+type State struct {
+	Phase   string    `json:"phase"`
+	Path    string    `json:"path,omitempty"`
+	Files   int       `json:"files"`
+	Bytes   int64     `json:"bytes"`
+	Totals  *Totals   `json:"totals,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+// State returns the phase's current State; a nil *Progress returns nil.
+func (p *Progress) State() *State {
+	if p == nil {
+		return nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return &State{Phase: p.phase, Path: p.path, Files: p.files, Bytes: p.bytes, Totals: p.totals, Started: p.started}
+}
+
+// Finish draws the final status of the phase.
+func (p *Progress) Finish() {
+	if p == nil {
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.draw(true)
+}
+
+// percent returns the completed percentage of the phase, or -1 when totals are unknown.
+func (p *Progress) percent() float64 {
+	if p.totals == nil {
+		return -1
+	}
+
+	if p.totals.Bytes > 0 {
+		return 100 * float64(p.bytes) / float64(p.totals.Bytes)
+	} else if p.totals.Files > 0 {
+		return 100 * float64(p.files) / float64(p.totals.Files)
+	}
+
+	return 100
+}
+
+// eta estimates the remaining duration of the phase from its throughput so far.
+func (p *Progress) eta() time.Duration {
+	percent := p.percent()
+	if percent <= 0 || percent >= 100 {
+		return 0
+	}
+
+	elapsed := time.Since(p.started)
+
+	return time.Duration(float64(elapsed) * (100 - percent) / percent).Round(time.Second)
+}
+
+func (p *Progress) status() string {
+	status := fmt.Sprintf("%s: %d", p.phase, p.files)
+	if p.totals != nil {
+		status += fmt.Sprintf("/%d", p.totals.Files)
+	}
+
+	status += " files"
+
+	if percent := p.percent(); percent >= 0 {
+		status += fmt.Sprintf(" (%.0f%%)", percent)
+		if eta := p.eta(); eta > 0 {
+			status += fmt.Sprintf(", ETA %s", eta)
+		}
+	}
+
+	return status
+}
+
+// Record represents a ModeJSON progress line. Totals, the percentage, and the ETA are null until known.
+type Record struct {
+	Phase      string   `json:"phase"`
+	Done       int      `json:"done"`
+	Total      *int     `json:"total"`
+	Bytes      int64    `json:"bytes"`
+	TotalBytes *int64   `json:"total-bytes"`
+	Percent    *float64 `json:"percent"`
+	ETA        *float64 `json:"eta"`
+	Elapsed    float64  `json:"elapsed"`
+	Final      bool     `json:"final"`
+}
+
+func (p *Progress) record(final bool) *Record {
+	r := &Record{Phase: p.phase, Done: p.files, Bytes: p.bytes, Elapsed: time.Since(p.started).Seconds(), Final: final}
+	if p.totals != nil {
+		r.Total, r.TotalBytes = &p.totals.Files, &p.totals.Bytes
+
+		percent := p.percent()
+		r.Percent = &percent
+
+		if percent > 0 {
+			eta := p.eta().Seconds()
+			r.ETA = &eta
+		}
+	}
+
+	return r
+}
+
+func (p *Progress) draw(final bool) {
+	switch p.mode {
+	case ModeJSON:
+		buffer, e := json.Marshal(p.record(final))
+		if e != nil {
+			panic(e)
+		}
+
+		fmt.Fprintf(p.w, "%s\n", buffer)
+	case ModeLines:
+		fmt.Fprintln(p.w, p.status())
+	case ModeBar:
+		prefix := spinner[p.frame%len(spinner)]
+		p.frame++
+
+		if percent := p.percent(); percent >= 0 {
+			const width = 20
+			filled := int(percent / 100 * width)
+			if filled > width {
+				filled = width
+			}
+
+			prefix = "[" + strings.Repeat("#", filled) + strings.Repeat(".", width-filled) + "]"
+		}
+
+		fmt.Fprintf(p.w, "\r\x1b[K%s %s", prefix, p.status())
+		if final {
+			fmt.Fprintln(p.w)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package prune represents retention-policy-driven cleanup: selecting a tree's files by age, size, and glob,
+// and journaling their deletion.
+package prune
@@ -0,0 +1,68 @@
+package prune
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// Action represents a journaled step of a prune.
+type Action string
+
+const (
+	ActionPlanned Action = "planned"
+	ActionDeleted Action = "deleted"
+	ActionFailed  Action = "failed"
+)
+
+// Record represents a single journal line.
+type Record struct {
+	Time     time.Time `json:"time"`
+	Action   Action    `json:"action"`
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	Checksum string    `json:"checksum,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Journal represents the append-only, line-delimited JSON record of a prune's deletions: every file
+// is journaled as planned before any is deleted, so an interrupted prune can be reconciled.
+type Journal struct {
+	f *os.File
+}
+
+// OpenJournal opens, or creates, the journal at path for appending.
+func OpenJournal(path string) (*Journal, error) {
+	f, e := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if e != nil {
+		return nil, e
+	}
+
+	return &Journal{f: f}, nil
+}
+
+// Write appends r to the journal, syncing it to disk; a nil Journal discards it.
+func (j *Journal) Write(r Record) error {
+	if j == nil {
+		return nil
+	}
+
+	buffer, e := json.Marshal(r)
+	if e != nil {
+		return e
+	}
+
+	if _, e := j.f.Write(append(buffer, '\n')); e != nil {
+		return e
+	}
+
+	return j.f.Sync()
+}
+
+func (j *Journal) Close() error {
+	if j == nil {
+		return nil
+	}
+
+	return j.f.Close()
+}
@@ -0,0 +1,105 @@
+package prune
+
+import (
+	"cli/internal/fs/tree"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy represents the retention policy of a prune. A file is selected when it matches every
+// non-zero condition.
+type Policy struct {
+	// OlderThan selects files last modified longer ago than it.
+	OlderThan time.Duration
+
+	// LargerThan selects files larger than it, in bytes.
+	LargerThan int64
+
+	// Match selects files whose name, or path relative to the root, matches any of the glob patterns.
+	Match []string
+}
+
+// ParseAge parses a non-negative Go duration, additionally accepting days and weeks, e.g. "30d" or "2w".
+func ParseAge(value string) (time.Duration, error) {
+	age, e := time.ParseDuration(value)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if digits, valid := strings.CutSuffix(value, suffix); valid {
+			n, failure := strconv.ParseFloat(digits, 64)
+			if failure != nil {
+				return 0, fmt.Errorf("invalid age: %q", value)
+			}
+
+			age, e = time.Duration(n*float64(unit)), nil
+		}
+	}
+
+	if e != nil {
+		return 0, e
+	}
+
+	if age < 0 {
+		return 0, fmt.Errorf("invalid age: %q is negative", value)
+	}
+
+	return age, nil
+}
+
+// Validate reports the first of the policy's match patterns that isn't a valid glob.
+func (p *Policy) Validate() error {
+	for _, pattern := range p.Match {
+		if _, e := filepath.Match(pattern, ""); e != nil {
+			return fmt.Errorf("bad glob %q: %w", pattern, e)
+		}
+	}
+
+	return nil
+}
+
+// Empty reports whether the policy has no conditions, and would select every file.
+func (p *Policy) Empty() bool {
+	return p.OlderThan == 0 && p.LargerThan == 0 && len(p.Match) == 0
+}
+
+// Select returns the files of root the policy selects at now, in path order.
+func (p *Policy) Select(root *tree.Node, now time.Time) []*tree.Node {
+	var selected []*tree.Node
+	for _, file := range root.FilesRecursive() {
+		if p.selects(root, file, now) {
+			selected = append(selected, file)
+		}
+	}
+
+	return selected
+}
+
+func (p *Policy) selects(root, file *tree.Node, now time.Time) bool {
+	if p.OlderThan > 0 && !(file.Modified.Before(now.Add(-p.OlderThan))) {
+		return false
+	}
+
+	if p.LargerThan > 0 && file.Size <= p.LargerThan {
+		return false
+	}
+
+	if len(p.Match) > 0 {
+		relative, _ := filepath.Rel(root.Path, file.Path)
+
+		matched := false
+		for _, pattern := range p.Match {
+			if m, _ := filepath.Match(pattern, file.Name); m {
+				matched = true
+			} else if m, _ := filepath.Match(pattern, relative); m {
+				matched = true
+			}
+		}
+
+		if !(matched) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,176 @@
+package prune
+
+import (
+	"cli/internal/exception"
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry represents a file selected by a prune.
+type Entry struct {
+	Path     string    `json:"path" yaml:"path"`
+	Size     int64     `json:"size" yaml:"size"`
+	Modified time.Time `json:"modified" yaml:"modified"`
+	Deleted  bool      `json:"deleted" yaml:"deleted"`
+	Error    string    `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Prune represents the plan, or the outcome, of pruning a tree.
+type Prune struct {
+	Root    string  `json:"root" yaml:"root"`
+	DryRun  bool    `json:"dry-run" yaml:"dry-run"`
+	Files   int     `json:"files" yaml:"files"`
+	Bytes   int64   `json:"bytes" yaml:"bytes"`
+	Entries []Entry `json:"entries" yaml:"entries"`
+
+	root     *tree.Node
+	selected []*tree.Node
+}
+
+// Plan returns the dry-run Prune of the files of root the policy selects at now.
+func Plan(root *tree.Node, policy *Policy, now time.Time) *Prune {
+	p := &Prune{Root: root.Path, DryRun: true, Entries: make([]Entry, 0), root: root, selected: policy.Select(root, now)}
+	for _, file := range p.selected {
+		p.Files++
+		p.Bytes += file.Size
+		p.Entries = append(p.Entries, Entry{Path: file.Path, Size: file.Size, Modified: file.Modified})
+	}
+
+	return p
+}
+
+// Execute deletes the planned files via remove, journaling each as planned before deleting any.
+// Failed deletions are recorded on their Entry rather than stopping the prune. Trees walked under the
+// read-only assertion are refused.
+func (p *Prune) Execute(journal *Journal, remove func(path string) error) error {
+	if p.root.ReadOnly() {
+		return exception.New(exception.EREADONLY, "prune", p.Root, errors.New("refusing to prune a read-only tree"))
+	}
+
+	p.DryRun = false
+
+	for _, file := range p.selected {
+		if e := journal.Write(record(ActionPlanned, file, nil)); e != nil {
+			return e
+		}
+	}
+
+	for i, file := range p.selected {
+		e := remove(file.Path)
+		if e == nil {
+			p.Entries[i].Deleted = true
+			e = journal.Write(record(ActionDeleted, file, nil))
+		} else {
+			p.Entries[i].Error = e.Error()
+			e = journal.Write(record(ActionFailed, file, e))
+		}
+
+		if e != nil {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func record(action Action, file *tree.Node, failure error) Record {
+	r := Record{Time: time.Now().UTC(), Action: action, Path: file.Path, Size: file.Size}
+	if file.Checksum != nil {
+		r.Checksum = file.Algorithm + ":" + *file.Checksum
+	}
+
+	if failure != nil {
+		r.Error = failure.Error()
+	}
+
+	return r
+}
+
+// Deleted returns how many of the planned files were deleted.
+func (p *Prune) Deleted() (count int) {
+	for _, entry := range p.Entries {
+		if entry.Deleted {
+			count++
+		}
+	}
+
+	return
+}
+
+// Audit appends a line describing the prune, its invoking user, and its arguments, to the audit log at path.
+func (p *Prune) Audit(path string, arguments []string) error {
+	f, e := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if e != nil {
+		return e
+	}
+
+	defer f.Close()
+
+	name := "unknown"
+	if u, e := user.Current(); e == nil {
+		name = u.Username
+	}
+
+	host, _ := os.Hostname()
+
+	_, e = fmt.Fprintf(f, "%s user=%s host=%s root=%q dry-run=%t planned=%d deleted=%d bytes=%d args=%q\n",
+		time.Now().UTC().Format(time.RFC3339), name, host, p.Root, p.DryRun, p.Files, p.Deleted(), p.Bytes, strings.Join(arguments, " "))
+
+	return e
+}
+
+func (p *Prune) JSON() string {
+	buffer, e := json.MarshalIndent(p, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (p *Prune) YAML() string {
+	buffer, e := yaml.Marshal(p)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing plan, or outcome, to w.
+func (p *Prune) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, entry := range p.Entries {
+		status := i18n.T(i18n.PruneWouldDelete)
+		switch {
+		case entry.Deleted:
+			status = i18n.T(i18n.PruneDeleted)
+		case entry.Error != "":
+			status = i18n.T(i18n.PruneFailed, entry.Error)
+		case !(p.DryRun):
+			status = i18n.T(i18n.PruneKept)
+		}
+
+		fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", status, render.Bytes(entry.Size), entry.Modified.Format(time.RFC3339), entry.Path)
+	}
+
+	t.Flush()
+
+	if p.DryRun {
+		fmt.Fprintln(w, i18n.T(i18n.PruneDryRun, p.Files, render.Bytes(p.Bytes)))
+	} else {
+		fmt.Fprintln(w, i18n.T(i18n.PruneTotals, p.Deleted(), p.Files))
+	}
+}
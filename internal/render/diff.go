@@ -0,0 +1,67 @@
+package render
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	colorAdded    = "32"
+	colorRemoved  = "31"
+	colorModified = "33"
+	colorSelected = "7"
+)
+
+// Added highlights text as added content.
+func (s Style) Added(text string) string {
+	return s.paint(colorAdded, text)
+}
+
+// Removed highlights text as removed content.
+func (s Style) Removed(text string) string {
+	return s.paint(colorRemoved, text)
+}
+
+// Modified highlights text as modified content.
+func (s Style) Modified(text string) string {
+	return s.paint(colorModified, text)
+}
+
+// Selected highlights text as the selected line of an interactive view, in reverse video.
+func (s Style) Selected(text string) string {
+	return s.paint(colorSelected, text)
+}
+
+// Width returns the columns of terminal f, falling back to $COLUMNS, then 120.
+func Width(f *os.File) int {
+	if columns, _, e := Size(f); e == nil && columns > 0 {
+		return columns
+	}
+
+	if columns, e := strconv.Atoi(os.Getenv("COLUMNS")); e == nil && columns > 0 {
+		return columns
+	}
+
+	return 120
+}
+
+// Fit truncates or pads text with spaces to exactly width runes.
+func (s Style) Fit(text string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	length := utf8.RuneCountInString(text)
+	if length <= width {
+		return text + strings.Repeat(" ", width-length)
+	}
+
+	ellipsis := "…"
+	if s.Plain {
+		ellipsis = "~"
+	}
+
+	return string([]rune(text)[:width-1]) + ellipsis
+}
@@ -0,0 +1,2 @@
+// Package render represents human-facing, terminal renderings of file-system trees.
+package render
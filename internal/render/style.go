@@ -0,0 +1,50 @@
+package render
+
+import (
+	"os"
+)
+
+// Style represents the terminal capabilities used when rendering human-facing output.
+type Style struct {
+	// Plain disables box-drawing characters, colors, and animations in favor of
+	// simple indented ASCII and line-oriented status messages.
+	Plain bool
+
+	// Color enables ANSI colors.
+	Color bool
+}
+
+// Detect returns the Style of the given output file: colors are enabled only for
+// terminals, and never when NO_COLOR is set or TERM is "dumb".
+func Detect(f *os.File, plain bool) Style {
+	if plain || os.Getenv("TERM") == "dumb" {
+		return Style{Plain: true}
+	}
+
+	_, disabled := os.LookupEnv("NO_COLOR")
+
+	return Style{Color: Terminal(f) && !(disabled)}
+}
+
+// Animated reports whether progress animations may be drawn.
+func (s Style) Animated(f *os.File) bool {
+	return !(s.Plain) && Terminal(f)
+}
+
+func (s Style) paint(code, text string) string {
+	if s.Plain || !(s.Color) {
+		return text
+	}
+
+	return "\x1b[" + code + "m" + text + "\x1b[0m"
+}
+
+// Terminal reports whether f is a character device, i.e. an interactive terminal.
+func Terminal(f *os.File) bool {
+	info, e := f.Stat()
+	if e != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,18 @@
+//go:build !(linux || darwin || freebsd)
+
+package render
+
+import (
+	"errors"
+	"os"
+)
+
+// Raw returns errors.ErrUnsupported; raw mode is only supported on Linux, macOS, and FreeBSD.
+func Raw(f *os.File) (func(), error) {
+	return nil, errors.ErrUnsupported
+}
+
+// Size returns errors.ErrUnsupported; terminal sizes are only reported on Linux, macOS, and FreeBSD.
+func Size(f *os.File) (int, int, error) {
+	return 0, 0, errors.ErrUnsupported
+}
@@ -0,0 +1,46 @@
+//go:build linux || darwin || freebsd
+
+package render
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// Raw puts terminal f into raw mode, reading input unbuffered, unechoed, and without signals,
+// returning a function restoring its previous mode.
+func Raw(f *os.File) (func(), error) {
+	fd := int(f.Fd())
+
+	previous, e := unix.IoctlGetTermios(fd, getTermios)
+	if e != nil {
+		return nil, e
+	}
+
+	raw := *previous
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+
+	if e := unix.IoctlSetTermios(fd, setTermios, &raw); e != nil {
+		return nil, e
+	}
+
+	return func() {
+		_ = unix.IoctlSetTermios(fd, setTermios, previous)
+	}, nil
+}
+
+// Size returns the columns and rows of terminal f.
+func Size(f *os.File) (int, int, error) {
+	size, e := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if e != nil {
+		return 0, 0, e
+	}
+
+	return int(size.Col), int(size.Row), nil
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"cli/internal/fs/tree"
+	"fmt"
+	"io"
+)
+
+const (
+	colorDirectory = "1;34"
+	colorSymbolic  = "1;36"
+)
+
+// Tree writes a human-facing rendering of the node and its descendants to w.
+func Tree(w io.Writer, n *tree.Node, style Style) {
+	fmt.Fprintln(w, style.name(n))
+
+	branches(w, n.Children(), "", style)
+}
+
+func branches(w io.Writer, nodes []*tree.Node, prefix string, style Style) {
+	for i, node := range nodes {
+		last := i == len(nodes)-1
+
+		var connector, indent string
+		switch {
+		case style.Plain:
+			connector, indent = "  ", "  "
+		case last:
+			connector, indent = "└── ", "    "
+		default:
+			connector, indent = "├── ", "│   "
+		}
+
+		fmt.Fprintln(w, prefix+connector+style.name(node))
+
+		branches(w, node.Children(), prefix+indent, style)
+	}
+}
+
+func (s Style) name(n *tree.Node) string {
+	name := tree.Escape(n.Name)
+
+	switch n.Type {
+	case tree.Directory:
+		if s.Plain {
+			return name + "/"
+		}
+
+		return s.paint(colorDirectory, name)
+	case tree.Symbolic:
+		if s.Plain {
+			return name + "@"
+		}
+
+		return s.paint(colorSymbolic, name)
+	}
+
+	return name
+}
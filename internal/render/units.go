@@ -0,0 +1,58 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Bytes returns a human-facing, binary-prefixed representation of a byte count.
+func Bytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	divisor, exponent := int64(unit), 0
+	for quotient := n / unit; quotient >= unit; quotient /= unit {
+		divisor *= unit
+		exponent++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(divisor), "KMGTPE"[exponent])
+}
+
+// ParseBytes parses a byte count with an optional decimal (KB, MB, ...) or binary (KiB, MiB, ...) unit, e.g. "2GB".
+func ParseBytes(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+
+	digits := strings.TrimRightFunc(value, unicode.IsLetter)
+	unit := strings.ToUpper(strings.TrimSpace(value[len(digits):]))
+
+	n, e := strconv.ParseFloat(strings.TrimSpace(digits), 64)
+	if e != nil || n < 0 {
+		return 0, fmt.Errorf("invalid byte count: %q", value)
+	}
+
+	multipliers := map[string]float64{"": 1, "B": 1}
+	for i, prefix := range "KMGTPE" {
+		multipliers[string(prefix)] = math.Pow(1000, float64(i+1))
+		multipliers[string(prefix)+"B"] = math.Pow(1000, float64(i+1))
+		multipliers[string(prefix)+"IB"] = math.Pow(1024, float64(i+1))
+	}
+
+	multiplier, valid := multipliers[unit]
+	if !(valid) {
+		return 0, fmt.Errorf("invalid byte count unit: %q", value)
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63, itself out of range
+	bytes := n * multiplier
+	if math.IsInf(bytes, 0) || math.IsNaN(bytes) || bytes >= math.MaxInt64 {
+		return 0, fmt.Errorf("byte count out of range: %q", value)
+	}
+
+	return int64(bytes), nil
+}
@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"cli/internal/fs/tree"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// within reports whether relative, a slash-separated path, is path or beneath it.
+func within(relative, path string) bool {
+	return relative == path || strings.HasPrefix(relative, path+"/")
+}
+
+// Options returns the walk options restricting a walk of the checkout at root to the manifest's project paths.
+func (m *Manifest) Options(root string) []tree.Option {
+	projects := m.Resolved()
+
+	return []tree.Option{
+		tree.WithExcludes(".repo", ".git"),
+		tree.WithFilter(func(n *tree.Node) bool {
+			relative, e := filepath.Rel(root, n.Path)
+			if e != nil {
+				return false
+			}
+
+			for _, project := range projects {
+				if within(filepath.ToSlash(relative), filepath.ToSlash(project.Path)) {
+					return true
+				}
+			}
+
+			return false
+		}),
+	}
+}
+
+// Annotate annotates each project's directory of t, a walk of the checkout, with the project's name ("repo.project"),
+// remote ("repo.remote"), and revision ("repo.revision").
+func (m *Manifest) Annotate(t *tree.Node) {
+	for _, project := range m.Resolved() {
+		n := t.At(project.Path)
+		if n == nil {
+			continue
+		}
+
+		n.Annotate("repo.project", project.Name)
+		if project.Remote != "" {
+			n.Annotate("repo.remote", project.Remote)
+		}
+
+		if project.Revision != "" {
+			n.Annotate("repo.revision", project.Revision)
+		}
+	}
+}
+
+// Discover returns the manifest of the git checkouts under root, each a project named by its path and pinned
+// to its checked out commit. Checkouts nested within checkouts are discovered too.
+func Discover(root string) (*Manifest, error) {
+	m := &Manifest{}
+
+	e := filepath.WalkDir(root, func(path string, entry fs.DirEntry, e error) error {
+		if e != nil {
+			return e
+		} else if !(entry.IsDir()) {
+			return nil
+		} else if name := entry.Name(); path != root && (name == ".git" || name == ".repo") {
+			return filepath.SkipDir
+		}
+
+		if _, e := os.Lstat(filepath.Join(path, ".git")); e != nil {
+			return nil
+		}
+
+		relative, e := filepath.Rel(root, path)
+		if e != nil || relative == "." {
+			return nil
+		}
+
+		project := Project{Name: filepath.ToSlash(relative)}
+		if current, e := head(path); e == nil {
+			project.Revision = current
+		}
+
+		m.Projects = append(m.Projects, project)
+
+		return nil
+	})
+
+	sort.Slice(m.Projects, func(i, j int) bool {
+		return m.Projects[i].Name < m.Projects[j].Name
+	})
+
+	return m, e
+}
@@ -0,0 +1,3 @@
+// Package repo represents Google repo tool manifests: the layout of a checkout assembled from several
+// sub-repositories, read to snapshot and verify checkouts, and written from discovered git checkouts.
+package repo
@@ -0,0 +1,83 @@
+package repo
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitdir returns the git directory of the checkout at directory: its .git directory, or the directory a .git
+// file ("gitdir: PATH") points to, as repo and worktrees use.
+func gitdir(directory string) (string, error) {
+	path := filepath.Join(directory, ".git")
+
+	info, e := os.Stat(path)
+	if e != nil {
+		return "", e
+	} else if info.IsDir() {
+		return path, nil
+	}
+
+	buffer, e := os.ReadFile(path)
+	if e != nil {
+		return "", e
+	}
+
+	target, valid := strings.CutPrefix(strings.TrimSpace(string(buffer)), "gitdir: ")
+	if !(valid) {
+		return "", errors.New("invalid .git file: " + path)
+	} else if !(filepath.IsAbs(target)) {
+		target = filepath.Join(directory, target)
+	}
+
+	return target, nil
+}
+
+// head returns the commit checked out at directory, resolving a symbolic HEAD through loose, then packed, refs.
+func head(directory string) (string, error) {
+	git, e := gitdir(directory)
+	if e != nil {
+		return "", e
+	}
+
+	buffer, e := os.ReadFile(filepath.Join(git, "HEAD"))
+	if e != nil {
+		return "", e
+	}
+
+	reference, symbolic := strings.CutPrefix(strings.TrimSpace(string(buffer)), "ref: ")
+	if !(symbolic) {
+		return reference, nil
+	}
+
+	if buffer, e := os.ReadFile(filepath.Join(git, filepath.FromSlash(reference))); e == nil {
+		return strings.TrimSpace(string(buffer)), nil
+	}
+
+	f, e := os.Open(filepath.Join(git, "packed-refs"))
+	if e != nil {
+		return "", e
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if commit, name, valid := strings.Cut(scanner.Text(), " "); valid && name == reference {
+			return commit, nil
+		}
+	}
+
+	return "", errors.New("unresolved reference: " + reference)
+}
+
+// commit reports whether revision is a full commit hash, rather than a branch or tag name.
+func commit(revision string) bool {
+	if len(revision) != 40 && len(revision) != 64 {
+		return false
+	}
+
+	return strings.Trim(strings.ToLower(revision), "0123456789abcdef") == ""
+}
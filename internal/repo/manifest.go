@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Remote represents a manifest's named fetch location.
+type Remote struct {
+	Name     string `xml:"name,attr"`
+	Fetch    string `xml:"fetch,attr"`
+	Revision string `xml:"revision,attr,omitempty"`
+}
+
+// Default represents a manifest's defaults of its projects' remote and revision.
+type Default struct {
+	Remote   string `xml:"remote,attr,omitempty"`
+	Revision string `xml:"revision,attr,omitempty"`
+}
+
+// Project represents a sub-repository checked out at Path, relative to the checkout's root.
+type Project struct {
+	Name     string `xml:"name,attr"`
+	Path     string `xml:"path,attr,omitempty"`
+	Remote   string `xml:"remote,attr,omitempty"`
+	Revision string `xml:"revision,attr,omitempty"`
+	Groups   string `xml:"groups,attr,omitempty"`
+}
+
+// Include represents the inclusion of another manifest file, relative to the including manifest.
+type Include struct {
+	Name string `xml:"name,attr"`
+}
+
+// Manifest represents a repo manifest document.
+type Manifest struct {
+	XMLName  xml.Name  `xml:"manifest"`
+	Remotes  []Remote  `xml:"remote"`
+	Default  *Default  `xml:"default"`
+	Includes []Include `xml:"include"`
+	Projects []Project `xml:"project"`
+}
+
+// Read reads the manifest at path, merging the manifests it includes.
+func Read(path string) (*Manifest, error) {
+	return read(path, map[string]bool{})
+}
+
+func read(path string, seen map[string]bool) (*Manifest, error) {
+	if seen[path] {
+		return nil, fmt.Errorf("invalid manifest %s: included recursively", path)
+	}
+
+	seen[path] = true
+
+	buffer, e := os.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+
+	m := &Manifest{}
+	if e := xml.Unmarshal(buffer, m); e != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", path, e)
+	}
+
+	for _, include := range m.Includes {
+		included, e := read(filepath.Join(filepath.Dir(path), include.Name), seen)
+		if e != nil {
+			return nil, e
+		}
+
+		m.Remotes = append(m.Remotes, included.Remotes...)
+		m.Projects = append(m.Projects, included.Projects...)
+		if m.Default == nil {
+			m.Default = included.Default
+		}
+	}
+
+	m.Includes = nil
+
+	return m, nil
+}
+
+// Resolved returns the manifest's projects, ordered by path, with their path, remote, and revision defaults applied:
+// a project's path defaults to its name, its remote to the default remote, and its revision to the remote's, then
+// the default revision.
+func (m *Manifest) Resolved() []Project {
+	remotes := map[string]Remote{}
+	for _, remote := range m.Remotes {
+		remotes[remote.Name] = remote
+	}
+
+	defaults := Default{}
+	if m.Default != nil {
+		defaults = *m.Default
+	}
+
+	projects := make([]Project, 0, len(m.Projects))
+	for _, project := range m.Projects {
+		if project.Path == "" {
+			project.Path = project.Name
+		}
+
+		project.Path = filepath.Clean(project.Path)
+
+		if project.Remote == "" {
+			project.Remote = defaults.Remote
+		}
+
+		if project.Revision == "" {
+			project.Revision = remotes[project.Remote].Revision
+		}
+
+		if project.Revision == "" {
+			project.Revision = defaults.Revision
+		}
+
+		projects = append(projects, project)
+	}
+
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Path < projects[j].Path
+	})
+
+	return projects
+}
+
+// XML returns the manifest document.
+func (m *Manifest) XML() string {
+	buffer, e := xml.MarshalIndent(m, "", "  ")
+	if e != nil {
+		panic(e)
+	}
+
+	return xml.Header + string(buffer) + "\n"
+}
@@ -0,0 +1,143 @@
+package repo
+
+import (
+	"cli/internal/exception"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// State represents how a project's checkout matches the manifest.
+type State string
+
+const (
+	Present     State = "present"
+	Missing     State = "missing"
+	NotDir      State = "not-a-directory"
+	NotCheckout State = "not-a-checkout"
+	Mismatched  State = "revision-mismatch"
+)
+
+// Status represents a single project's checkout State.
+type Status struct {
+	Name     string `json:"name" yaml:"name"`
+	Path     string `json:"path" yaml:"path"`
+	State    State  `json:"state" yaml:"state"`
+	Revision string `json:"revision,omitempty" yaml:"revision,omitempty"`
+
+	// Head is the checked out commit, when the manifest pins the project to a commit.
+	Head string `json:"head,omitempty" yaml:"head,omitempty"`
+}
+
+// Verification represents the verification of a checkout against a manifest's expected layout.
+type Verification struct {
+	Root     string   `json:"root" yaml:"root"`
+	Projects []Status `json:"projects" yaml:"projects"`
+
+	// Unexpected are the top-level directories of the checkout holding no project, besides .repo.
+	Unexpected []string `json:"unexpected,omitempty" yaml:"unexpected,omitempty"`
+}
+
+// Verify verifies that every project of m is checked out under root: its path is a directory, a git checkout,
+// and, when the manifest pins a commit, checked out at it. Branch and tag revisions aren't resolved.
+func Verify(m *Manifest, root string) *Verification {
+	v := &Verification{Root: root, Projects: make([]Status, 0)}
+
+	tops := map[string]bool{".repo": true}
+	for _, project := range m.Resolved() {
+		tops[strings.SplitN(filepath.ToSlash(project.Path), "/", 2)[0]] = true
+
+		status := Status{Name: project.Name, Path: project.Path, Revision: project.Revision, State: Present}
+
+		directory := filepath.Join(root, project.Path)
+		if info, e := os.Stat(directory); e != nil {
+			status.State = Missing
+		} else if !(info.IsDir()) {
+			status.State = NotDir
+		} else if current, e := head(directory); e != nil {
+			status.State = NotCheckout
+		} else if commit(project.Revision) {
+			status.Head = current
+			if !(strings.EqualFold(current, project.Revision)) {
+				status.State = Mismatched
+			}
+		}
+
+		v.Projects = append(v.Projects, status)
+	}
+
+	if entries, e := os.ReadDir(root); e == nil {
+		for _, entry := range entries {
+			if entry.IsDir() && !(tops[entry.Name()]) {
+				v.Unexpected = append(v.Unexpected, entry.Name())
+			}
+		}
+	}
+
+	sort.Strings(v.Unexpected)
+
+	return v
+}
+
+// Err returns an ELAYOUT error if any project isn't Present, or the checkout holds unexpected directories.
+func (v *Verification) Err() error {
+	failed := 0
+	for _, status := range v.Projects {
+		if status.State != Present {
+			failed++
+		}
+	}
+
+	if failed == 0 && len(v.Unexpected) == 0 {
+		return nil
+	}
+
+	return exception.New(exception.ELAYOUT, "verify", v.Root, fmt.Errorf("%d project(s) not checked out as expected, %d unexpected directories", failed, len(v.Unexpected)))
+}
+
+func (v *Verification) JSON() string {
+	buffer, e := json.MarshalIndent(v, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (v *Verification) YAML() string {
+	buffer, e := yaml.Marshal(v)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing project status table, then the unexpected directories, to w.
+func (v *Verification) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", i18n.T(i18n.ReportPath), i18n.T(i18n.RepoProject), i18n.T(i18n.RepoState), i18n.T(i18n.RepoRevision))
+	for _, status := range v.Projects {
+		state := string(status.State)
+		if status.State == Mismatched {
+			state = i18n.T(i18n.RepoAt, state, status.Head)
+		}
+
+		fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", status.Path, status.Name, state, status.Revision)
+	}
+
+	t.Flush()
+
+	for _, directory := range v.Unexpected {
+		fmt.Fprintln(w, i18n.T(i18n.RepoUnexpected, directory))
+	}
+}
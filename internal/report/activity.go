@@ -0,0 +1,144 @@
+package report
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Buckets are the modification-age boundaries of the activity report, in ascending order.
+var Buckets = []struct {
+	Name string
+	Age  time.Duration
+}{
+	{"hour", time.Hour},
+	{"day", 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour},
+}
+
+// Heat represents the number of files of a top-level directory, bucketed by modification age.
+type Heat struct {
+	Path    string `json:"path" yaml:"path"`
+	Hour    int    `json:"hour" yaml:"hour"`
+	Day     int    `json:"day" yaml:"day"`
+	Week    int    `json:"week" yaml:"week"`
+	Month   int    `json:"month" yaml:"month"`
+	Older   int    `json:"older" yaml:"older"`
+	Unknown int    `json:"unknown,omitempty" yaml:"unknown,omitempty"`
+}
+
+// Activity represents the change heatmap of a tree, relative to the Reference time.
+type Activity struct {
+	Root      string    `json:"root" yaml:"root"`
+	Reference time.Time `json:"reference" yaml:"reference"`
+	Entries   []Heat    `json:"entries" yaml:"entries"`
+}
+
+// Heatmap buckets the files of root by modification time relative to reference (last hour, day, week,
+// month, or older) per top-level directory; files directly beneath root are reported as ".".
+func Heatmap(root *tree.Node, reference time.Time) *Activity {
+	table := map[string]*Heat{}
+
+	for _, n := range root.FilesRecursive() {
+		key := top(root, n)
+
+		heat, valid := table[key]
+		if !(valid) {
+			heat = &Heat{Path: key}
+			table[key] = heat
+		}
+
+		heat.add(reference, n.Modified)
+	}
+
+	a := &Activity{Root: root.Path, Reference: reference, Entries: make([]Heat, 0, len(table))}
+	for _, heat := range table {
+		a.Entries = append(a.Entries, *heat)
+	}
+
+	sort.Slice(a.Entries, func(i, j int) bool {
+		return a.Entries[i].Path < a.Entries[j].Path
+	})
+
+	return a
+}
+
+// top returns the top-level directory of n beneath root, or "." for root's own files.
+func top(root, n *tree.Node) string {
+	relative, e := filepath.Rel(root.Path, n.Path)
+	if e != nil {
+		return "."
+	}
+
+	directory, _, nested := strings.Cut(filepath.ToSlash(relative), "/")
+	if !(nested) {
+		return "."
+	}
+
+	return directory
+}
+
+// add counts a file modified at modified into its bucket.
+func (h *Heat) add(reference, modified time.Time) {
+	if modified.IsZero() {
+		h.Unknown++
+		return
+	}
+
+	counters := []*int{&h.Hour, &h.Day, &h.Week, &h.Month}
+
+	age := reference.Sub(modified)
+	for i, bucket := range Buckets {
+		if age <= bucket.Age {
+			*counters[i]++
+			return
+		}
+	}
+
+	h.Older++
+}
+
+func (a *Activity) JSON() string {
+	buffer, e := json.MarshalIndent(a, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (a *Activity) YAML() string {
+	buffer, e := yaml.Marshal(a)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing heatmap table to w.
+func (a *Activity) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+
+	for _, header := range []i18n.Message{i18n.ActivityHour, i18n.ActivityDay, i18n.ActivityWeek, i18n.ActivityMonth, i18n.ActivityOlder, i18n.ActivityUnknown} {
+		fmt.Fprintf(t, "%s\t", i18n.T(header))
+	}
+
+	fmt.Fprintf(t, "  %s\n", i18n.T(i18n.GroupingDirectory))
+	for _, entry := range a.Entries {
+		fmt.Fprintf(t, "%d\t%d\t%d\t%d\t%d\t%d\t  %s\n", entry.Hour, entry.Day, entry.Week, entry.Month, entry.Older, entry.Unknown, entry.Path)
+	}
+
+	t.Flush()
+}
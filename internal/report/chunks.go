@@ -0,0 +1,153 @@
+package report
+
+import (
+	"cli/internal/cdc"
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Chunking represents the content-defined-chunking deduplication estimate of a tree: how much of its content a
+// chunk-deduplicating backend would store once, compared with whole-file deduplication.
+type Chunking struct {
+	Root      string `json:"root" yaml:"root"`
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+	Min       int    `json:"min" yaml:"min"`
+	Average   int    `json:"average" yaml:"average"`
+	Max       int    `json:"max" yaml:"max"`
+
+	Files int   `json:"files" yaml:"files"`
+	Bytes int64 `json:"bytes" yaml:"bytes"`
+
+	// Chunks and Unique are the total and distinct chunk counts; Stored is the bytes of the distinct chunks.
+	Chunks int   `json:"chunks" yaml:"chunks"`
+	Unique int   `json:"unique" yaml:"unique"`
+	Stored int64 `json:"stored" yaml:"stored"`
+
+	// Whole is the bytes stored by whole-file deduplication, for comparison.
+	Whole int64 `json:"whole" yaml:"whole"`
+
+	// Ratio is Bytes over Stored; Savings is the fraction of Bytes chunk deduplication doesn't store.
+	Ratio   float64 `json:"ratio" yaml:"ratio"`
+	Savings float64 `json:"savings" yaml:"savings"`
+
+	// Skipped are the files that couldn't be read.
+	Skipped []string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+// Chunk estimates the deduplication of root's files by splitting their content with c, and counting each
+// distinct chunk's bytes once. Chunks are identified by a truncated SHA-256 of their content.
+func Chunk(root *tree.Node, c *cdc.Chunker) *Chunking {
+	report := &Chunking{Root: root.Path, Algorithm: "fastcdc", Min: c.Min, Average: c.Average, Max: c.Max}
+
+	chunks := map[[16]byte]struct{}{}
+	files := map[[sha256.Size]byte]struct{}{}
+
+	for _, n := range root.FilesRecursive() {
+		f, e := os.Open(n.Path)
+		if e != nil {
+			report.Skipped = append(report.Skipped, n.Path)
+			continue
+		}
+
+		whole := sha256.New()
+		var size int64
+		e = c.Split(f, func(chunk []byte) {
+			whole.Write(chunk)
+			size += int64(len(chunk))
+
+			report.Chunks++
+
+			var id [16]byte
+			sum := sha256.Sum256(chunk)
+			copy(id[:], sum[:])
+			if _, seen := chunks[id]; !(seen) {
+				chunks[id] = struct{}{}
+				report.Stored += int64(len(chunk))
+			}
+		})
+
+		f.Close()
+
+		if e != nil {
+			report.Skipped = append(report.Skipped, n.Path)
+			continue
+		}
+
+		report.Files++
+		report.Bytes += size
+
+		var id [sha256.Size]byte
+		whole.Sum(id[:0])
+		if _, seen := files[id]; !(seen) {
+			files[id] = struct{}{}
+			report.Whole += size
+		}
+	}
+
+	report.Unique = len(chunks)
+
+	if report.Stored > 0 {
+		report.Ratio = float64(report.Bytes) / float64(report.Stored)
+	}
+
+	if report.Bytes > 0 {
+		report.Savings = 1 - float64(report.Stored)/float64(report.Bytes)
+	}
+
+	sort.Strings(report.Skipped)
+
+	return report
+}
+
+func (c *Chunking) JSON() string {
+	buffer, e := json.MarshalIndent(c, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (c *Chunking) YAML() string {
+	buffer, e := yaml.Marshal(c)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing deduplication estimate table to w.
+func (c *Chunking) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\t%s\n", i18n.T(i18n.ChunksDeduplication), i18n.T(i18n.ChunksStored), i18n.T(i18n.ChunksSavings))
+	fmt.Fprintf(t, "%s\t%s\t%s\n", i18n.T(i18n.ChunksNone), render.Bytes(c.Bytes), percent(0, c.Bytes))
+	fmt.Fprintf(t, "%s\t%s\t%s\n", i18n.T(i18n.ChunksWholeFile), render.Bytes(c.Whole), percent(c.Bytes-c.Whole, c.Bytes))
+	fmt.Fprintf(t, "%s (%s-%s-%s)\t%s\t%s\n", c.Algorithm, render.Bytes(int64(c.Min)), render.Bytes(int64(c.Average)), render.Bytes(int64(c.Max)), render.Bytes(c.Stored), percent(c.Bytes-c.Stored, c.Bytes))
+	t.Flush()
+
+	fmt.Fprintf(w, "\n%s\n", i18n.T(i18n.ChunksTotals, c.Files, c.Chunks, c.Unique, c.Ratio))
+
+	for _, path := range c.Skipped {
+		fmt.Fprintln(w, i18n.T(i18n.ReportUnreadable, path))
+	}
+}
+
+// percent formats part as a percentage of whole.
+func percent(part, whole int64) string {
+	if whole == 0 {
+		return "0.0%"
+	}
+
+	return fmt.Sprintf("%.1f%%", 100*float64(part)/float64(whole))
+}
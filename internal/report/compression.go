@@ -0,0 +1,231 @@
+package report
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/klauspost/compress/zstd"
+	"gopkg.in/yaml.v3"
+)
+
+// Levels returns the zstd compression levels a Compression estimate may compare.
+func Levels() []string {
+	return []string{"fastest", "default", "better", "best"}
+}
+
+// Compressible represents the estimated zstd-compressed size of a group of files, per compression level.
+type Compressible struct {
+	Key     string `json:"key" yaml:"key"`
+	Files   int    `json:"files" yaml:"files"`
+	Bytes   int64  `json:"bytes" yaml:"bytes"`
+	Sampled int64  `json:"sampled" yaml:"sampled"`
+
+	// Estimated maps each level to the group's estimated compressed bytes.
+	Estimated map[string]int64 `json:"estimated" yaml:"estimated"`
+}
+
+// Compression represents the sampled compressibility estimate of a tree: the sampled blocks of every file
+// are compressed with zstd, and each file's compressed size is extrapolated from its blocks' ratio.
+type Compression struct {
+	Root      string         `json:"root" yaml:"root"`
+	Levels    []string       `json:"levels" yaml:"levels"`
+	BlockSize int64          `json:"block-size" yaml:"block-size"`
+	Blocks    int            `json:"blocks" yaml:"blocks"`
+	Grouping  Grouping       `json:"grouping,omitempty" yaml:"grouping,omitempty"`
+	Total     Compressible   `json:"total" yaml:"total"`
+	Groups    []Compressible `json:"groups,omitempty" yaml:"groups,omitempty"`
+
+	// Skipped are the files that couldn't be read.
+	Skipped []string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+}
+
+// offsets returns the offsets of up to blocks evenly spaced, block-aligned blocks of a size-byte file.
+func offsets(size, block int64, blocks int) []int64 {
+	count := (size + block - 1) / block
+	if count <= int64(blocks) {
+		all := make([]int64, count)
+		for i := range all {
+			all[i] = int64(i) * block
+		}
+
+		return all
+	}
+
+	sampled := make([]int64, blocks)
+	for i := range sampled {
+		sampled[i] = int64(i) * count / int64(blocks) * block
+	}
+
+	return sampled
+}
+
+// Compress estimates the compressed size of root's files at each of levels, compressing up to blocks evenly
+// spaced blocks of block size bytes from every file, and groups the estimates by grouping.
+func Compress(root *tree.Node, grouping Grouping, levels []string, block int64, blocks int) (*Compression, error) {
+	if block <= 0 || blocks <= 0 {
+		return nil, fmt.Errorf("invalid sampling: expected a positive block size (%d) and block count (%d)", block, blocks)
+	}
+
+	encoders := make([]*zstd.Encoder, len(levels))
+	for i, level := range levels {
+		valid, l := zstd.EncoderLevelFromString(level)
+		if !(valid) {
+			return nil, fmt.Errorf("unsupported compression level %q (expected one of %v)", level, Levels())
+		}
+
+		encoder, e := zstd.NewWriter(nil, zstd.WithEncoderLevel(l), zstd.WithEncoderConcurrency(1))
+		if e != nil {
+			return nil, e
+		}
+
+		defer encoder.Close()
+
+		encoders[i] = encoder
+	}
+
+	c := &Compression{Root: root.Path, Levels: levels, BlockSize: block, Blocks: blocks, Grouping: grouping}
+	c.Total = Compressible{Key: "total", Estimated: map[string]int64{}}
+
+	table := map[string]*Compressible{}
+
+	buffer := make([]byte, block)
+	var compressed []byte
+	for _, n := range root.FilesRecursive() {
+		f, e := os.Open(n.Path)
+		if e != nil {
+			c.Skipped = append(c.Skipped, n.Path)
+			continue
+		}
+
+		var sampled int64
+		sizes := make([]int64, len(levels))
+		for _, offset := range offsets(n.Size, block, blocks) {
+			length, e := f.ReadAt(buffer, offset)
+			if e != nil && !(errors.Is(e, io.EOF)) {
+				break
+			}
+
+			sampled += int64(length)
+			for i, encoder := range encoders {
+				compressed = encoder.EncodeAll(buffer[:length], compressed[:0])
+				sizes[i] += int64(len(compressed))
+			}
+		}
+
+		f.Close()
+
+		estimated := map[string]int64{}
+		for i, level := range levels {
+			estimated[level] = n.Size
+			if sampled > 0 {
+				estimated[level] = int64(float64(sizes[i]) / float64(sampled) * float64(n.Size))
+			}
+		}
+
+		c.Total.add(n.Size, sampled, estimated)
+
+		if grouping == GroupNone {
+			continue
+		}
+
+		for _, key := range grouping.Keys(root, n) {
+			group, valid := table[key]
+			if !(valid) {
+				group = &Compressible{Key: key, Estimated: map[string]int64{}}
+				table[key] = group
+			}
+
+			group.add(n.Size, sampled, estimated)
+		}
+	}
+
+	for _, group := range table {
+		c.Groups = append(c.Groups, *group)
+	}
+
+	sort.Slice(c.Groups, func(i, j int) bool {
+		if c.Groups[i].Bytes != c.Groups[j].Bytes {
+			return c.Groups[i].Bytes > c.Groups[j].Bytes
+		}
+
+		return c.Groups[i].Key < c.Groups[j].Key
+	})
+
+	sort.Strings(c.Skipped)
+
+	return c, nil
+}
+
+func (g *Compressible) add(size, sampled int64, estimated map[string]int64) {
+	g.Files++
+	g.Bytes += size
+	g.Sampled += sampled
+	for level, bytes := range estimated {
+		g.Estimated[level] += bytes
+	}
+}
+
+func (c *Compression) JSON() string {
+	buffer, e := json.MarshalIndent(c, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (c *Compression) YAML() string {
+	buffer, e := yaml.Marshal(c)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing compressibility table, one size-and-ratio column per level, to w.
+func (c *Compression) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	key := c.Grouping.Title()
+	if c.Grouping == GroupNone {
+		key = i18n.T(i18n.ReportRoot)
+	}
+
+	fmt.Fprintf(t, "%s\t%s\t%s\t%s\t%s\n", key, i18n.T(i18n.ReportFiles), i18n.T(i18n.ReportBytes), i18n.T(i18n.ReportSample), strings.Join(c.Levels, "\t"))
+
+	row := func(g Compressible) {
+		fmt.Fprintf(t, "%s\t%d\t%s\t%s", Label(g.Key), g.Files, render.Bytes(g.Bytes), render.Bytes(g.Sampled))
+		for _, level := range c.Levels {
+			ratio := 1.0
+			if g.Estimated[level] > 0 {
+				ratio = float64(g.Bytes) / float64(g.Estimated[level])
+			}
+
+			fmt.Fprintf(t, "\t%s (%.2fx)", render.Bytes(g.Estimated[level]), ratio)
+		}
+
+		fmt.Fprintln(t)
+	}
+
+	for _, group := range c.Groups {
+		row(group)
+	}
+
+	row(c.Total)
+
+	t.Flush()
+
+	for _, path := range c.Skipped {
+		fmt.Fprintln(w, i18n.T(i18n.ReportUnreadable, path))
+	}
+}
@@ -0,0 +1,2 @@
+// Package report represents human- and machine-facing reports computed from file-system trees.
+package report
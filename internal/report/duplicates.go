@@ -0,0 +1,185 @@
+package report
+
+import (
+	"cli/internal/fs/extent"
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duplicate represents files of identical content. Files hold distinct storage, and deduplicating them reclaims
+// Reclaimable bytes; Clones maps each of Files to the paths already sharing its storage (reflinked clones,
+// or hard links), which deduplicating would save nothing on.
+type Duplicate struct {
+	Algorithm   string              `json:"algorithm" yaml:"algorithm"`
+	Checksum    string              `json:"checksum" yaml:"checksum"`
+	Size        int64               `json:"size" yaml:"size"`
+	Files       []string            `json:"files" yaml:"files"`
+	Clones      map[string][]string `json:"clones,omitempty" yaml:"clones,omitempty"`
+	Reclaimable int64               `json:"reclaimable" yaml:"reclaimable"`
+}
+
+// Duplicates represents the duplicate-content report of a tree.
+type Duplicates struct {
+	Root   string      `json:"root" yaml:"root"`
+	Groups []Duplicate `json:"groups" yaml:"groups"`
+
+	// Reclaimable is the bytes deduplicating the true duplicates would reclaim.
+	Reclaimable int64 `json:"reclaimable" yaml:"reclaimable"`
+
+	// Shared is the bytes already deduplicated by clones and hard links.
+	Shared int64 `json:"shared" yaml:"shared"`
+}
+
+// storage represents a file's identity on disk, compared to tell clones from duplicates.
+type storage struct {
+	info    os.FileInfo
+	extents []extent.Extent
+}
+
+func identify(path string) *storage {
+	s := &storage{}
+	s.info, _ = os.Lstat(path)
+	s.extents, _ = extent.Of(path)
+
+	return s
+}
+
+// shares reports whether two files share storage: they're the same file, or map the same physical extents.
+func (s *storage) shares(other *storage) bool {
+	if s.info != nil && other.info != nil && os.SameFile(s.info, other.info) {
+		return true
+	}
+
+	return extent.Same(s.extents, other.extents)
+}
+
+// Dedupe groups the non-empty files of root by checksum, separating clones sharing storage, whose
+// extents are identical where the file-system reports them (btrfs, XFS, APFS), from true duplicates.
+// Sampled fingerprints don't establish identical content, so sampled files are skipped.
+func Dedupe(root *tree.Node) *Duplicates {
+	type key struct {
+		algorithm, checksum string
+		size                int64
+	}
+
+	candidates := map[key][]string{}
+	for _, n := range root.FilesRecursive() {
+		if n.Checksum == nil || n.Size == 0 || strings.HasPrefix(n.Algorithm, "sampled-") {
+			continue
+		}
+
+		k := key{n.Algorithm, *n.Checksum, n.Size}
+		candidates[k] = append(candidates[k], n.Path)
+	}
+
+	d := &Duplicates{Root: root.Path, Groups: make([]Duplicate, 0)}
+	for k, paths := range candidates {
+		if len(paths) < 2 {
+			continue
+		}
+
+		sort.Strings(paths)
+
+		group := Duplicate{Algorithm: k.algorithm, Checksum: k.checksum, Size: k.size}
+
+		var distinct []*storage
+		for _, path := range paths {
+			s := identify(path)
+
+			shared := false
+			for i, other := range distinct {
+				if s.shares(other) {
+					if group.Clones == nil {
+						group.Clones = map[string][]string{}
+					}
+
+					group.Clones[group.Files[i]] = append(group.Clones[group.Files[i]], path)
+					shared = true
+					break
+				}
+			}
+
+			if shared {
+				d.Shared += k.size
+			} else {
+				distinct = append(distinct, s)
+				group.Files = append(group.Files, path)
+			}
+		}
+
+		group.Reclaimable = k.size * int64(len(group.Files)-1)
+		d.Reclaimable += group.Reclaimable
+		d.Groups = append(d.Groups, group)
+	}
+
+	sort.Slice(d.Groups, func(i, j int) bool {
+		if d.Groups[i].Reclaimable != d.Groups[j].Reclaimable {
+			return d.Groups[i].Reclaimable > d.Groups[j].Reclaimable
+		}
+
+		return d.Groups[i].Files[0] < d.Groups[j].Files[0]
+	})
+
+	return d
+}
+
+func (d *Duplicates) JSON() string {
+	buffer, e := json.MarshalIndent(d, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (d *Duplicates) YAML() string {
+	buffer, e := yaml.Marshal(d)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing duplicates, then clones, tables to w.
+func (d *Duplicates) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", i18n.T(i18n.DuplicatesDuplicate), i18n.T(i18n.ReportSize), i18n.T(i18n.DuplicatesReclaimable), i18n.T(i18n.ReportChecksum))
+	for _, group := range d.Groups {
+		if len(group.Files) < 2 {
+			continue
+		}
+
+		for _, path := range group.Files {
+			fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", path, render.Bytes(group.Size), render.Bytes(group.Reclaimable), group.Checksum)
+		}
+	}
+
+	t.Flush()
+
+	fmt.Fprintln(w)
+
+	t = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\t%s\n", i18n.T(i18n.DuplicatesClone), i18n.T(i18n.ReportSize), i18n.T(i18n.DuplicatesShares))
+	for _, group := range d.Groups {
+		for _, path := range group.Files {
+			for _, clone := range group.Clones[path] {
+				fmt.Fprintf(t, "%s\t%s\t%s\n", clone, render.Bytes(group.Size), path)
+			}
+		}
+	}
+
+	t.Flush()
+
+	fmt.Fprintf(w, "\n%s\n", i18n.T(i18n.DuplicatesTotals, render.Bytes(d.Reclaimable), render.Bytes(d.Shared)))
+}
@@ -0,0 +1,118 @@
+package report
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Confidence is the confidence level of an Estimate's intervals.
+const Confidence = 0.95
+
+// z is the standard normal quantile of the Confidence level.
+const z = 1.959964
+
+// Interval represents an estimated value along with its confidence interval.
+type Interval struct {
+	Value int64 `json:"value" yaml:"value"`
+	Low   int64 `json:"low" yaml:"low"`
+	High  int64 `json:"high" yaml:"high"`
+}
+
+// Estimate represents the statistical estimate of a tree's totals from a sampling walk: Listed directories
+// were visited, Sampled of the Frontier's subtrees among them.
+type Estimate struct {
+	Root        string   `json:"root" yaml:"root"`
+	Fraction    float64  `json:"fraction" yaml:"fraction"`
+	Confidence  float64  `json:"confidence" yaml:"confidence"`
+	Listed      int      `json:"listed" yaml:"listed"`
+	Frontier    int      `json:"frontier" yaml:"frontier"`
+	Sampled     int      `json:"sampled" yaml:"sampled"`
+	Directories Interval `json:"directories" yaml:"directories"`
+	Files       Interval `json:"files" yaml:"files"`
+	Bytes       Interval `json:"bytes" yaml:"bytes"`
+}
+
+// Estimated computes the Horvitz-Thompson estimates of the totals of s: the exact totals above its frontier,
+// plus those of the sampled subtrees scaled up by the sampling fraction, with normal-approximation confidence
+// intervals. Lower bounds never fall below the totals actually observed; a tree walked fully is exact.
+func Estimated(root string, s *tree.Sample) *Estimate {
+	directories := make([]float64, len(s.Clusters))
+	files := make([]float64, len(s.Clusters))
+	bytes := make([]float64, len(s.Clusters))
+	for i, cluster := range s.Clusters {
+		directories[i], files[i], bytes[i] = float64(cluster.Directories), float64(cluster.Files), float64(cluster.Bytes)
+	}
+
+	return &Estimate{
+		Root:        root,
+		Fraction:    s.Fraction,
+		Confidence:  Confidence,
+		Listed:      s.Listed,
+		Frontier:    s.Frontier,
+		Sampled:     len(s.Clusters),
+		Directories: estimated(float64(s.Exact.Directories), directories, s.Fraction),
+		Files:       estimated(float64(s.Exact.Files), files, s.Fraction),
+		Bytes:       estimated(float64(s.Exact.Bytes), bytes, s.Fraction),
+	}
+}
+
+// estimated returns the Interval of a total observed exactly as exact, plus clusters sampled with probability p.
+func estimated(exact float64, clusters []float64, p float64) Interval {
+	var observed, squares float64
+	for _, value := range clusters {
+		observed += value
+		squares += value * value
+	}
+
+	sum := observed / p
+	deviation := math.Sqrt((1 - p) / (p * p) * squares)
+
+	return Interval{
+		Value: int64(math.Round(exact + sum)),
+		Low:   int64(math.Round(exact + math.Max(observed, sum-z*deviation))),
+		High:  int64(math.Round(exact + sum + z*deviation)),
+	}
+}
+
+func (est *Estimate) JSON() string {
+	buffer, e := json.MarshalIndent(est, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (est *Estimate) YAML() string {
+	buffer, e := yaml.Marshal(est)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing estimate to w.
+func (est *Estimate) Text(w io.Writer, style render.Style) {
+	confidence := est.Confidence * 100
+
+	listed := i18n.T(i18n.ReportUnsampled, est.Listed)
+	if est.Frontier > 0 {
+		listed = i18n.T(i18n.ReportSampled, est.Listed, est.Sampled, est.Frontier, est.Fraction*100)
+	}
+
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\n", i18n.T(i18n.ReportListed), listed)
+	fmt.Fprintf(t, "%s\t%s\n", i18n.T(i18n.ReportDirectories), i18n.T(i18n.ReportInterval, est.Directories.Value, confidence, est.Directories.Low, est.Directories.High))
+	fmt.Fprintf(t, "%s\t%s\n", i18n.T(i18n.ReportFiles), i18n.T(i18n.ReportInterval, est.Files.Value, confidence, est.Files.Low, est.Files.High))
+	fmt.Fprintf(t, "%s\t%s\n", i18n.T(i18n.ReportBytes), i18n.T(i18n.ReportInterval, render.Bytes(est.Bytes.Value), confidence, render.Bytes(est.Bytes.Low), render.Bytes(est.Bytes.High)))
+	t.Flush()
+}
@@ -0,0 +1,85 @@
+package report
+
+import (
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Outcome represents the result of a fan-out copy to a single destination. Failed destinations were rolled back.
+type Outcome struct {
+	Destination string `json:"destination" yaml:"destination"`
+	Copied      bool   `json:"copied" yaml:"copied"`
+	Error       string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Fanout represents the consolidated report of copying a tree to several destinations.
+type Fanout struct {
+	Source       string    `json:"source" yaml:"source"`
+	Mode         string    `json:"mode" yaml:"mode"`
+	Files        int       `json:"files" yaml:"files"`
+	Bytes        int64     `json:"bytes" yaml:"bytes"`
+	Destinations []Outcome `json:"destinations" yaml:"destinations"`
+}
+
+// Add records the outcome of copying to destination; a nil failure means it was copied.
+func (f *Fanout) Add(destination string, failure error) {
+	outcome := Outcome{Destination: destination, Copied: failure == nil}
+	if failure != nil {
+		outcome.Error = failure.Error()
+	}
+
+	f.Destinations = append(f.Destinations, outcome)
+}
+
+// Failed returns how many destinations weren't copied.
+func (f *Fanout) Failed() (count int) {
+	for _, outcome := range f.Destinations {
+		if !(outcome.Copied) {
+			count++
+		}
+	}
+
+	return
+}
+
+func (f *Fanout) JSON() string {
+	buffer, e := json.MarshalIndent(f, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (f *Fanout) YAML() string {
+	buffer, e := yaml.Marshal(f)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing per-destination outcomes to w.
+func (f *Fanout) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\n", i18n.T(i18n.FanoutDestination), i18n.T(i18n.FanoutStatus))
+	for _, outcome := range f.Destinations {
+		status := i18n.T(i18n.FanoutCopied)
+		if !(outcome.Copied) {
+			status = i18n.T(i18n.FanoutRolledBack, outcome.Error)
+		}
+
+		fmt.Fprintf(t, "%s\t%s\n", outcome.Destination, status)
+	}
+
+	t.Flush()
+
+	fmt.Fprintln(w, i18n.T(i18n.FanoutTotals, f.Source, f.Files, render.Bytes(f.Bytes), len(f.Destinations)-f.Failed(), len(f.Destinations)))
+}
@@ -0,0 +1,213 @@
+package report
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"fmt"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Grouping represents how report rows are organized.
+type Grouping string
+
+const (
+	GroupNone      Grouping = ""
+	GroupExtension Grouping = "extension"
+	GroupDirectory Grouping = "directory"
+	GroupTag       Grouping = "tag"
+	GroupOwner     Grouping = "owner"
+)
+
+// Groupings returns all supported groupings.
+func Groupings() []Grouping {
+	return []Grouping{GroupExtension, GroupDirectory, GroupTag, GroupOwner}
+}
+
+// Valid reports whether the Grouping is supported.
+func (g Grouping) Valid() bool {
+	if g == GroupNone {
+		return true
+	}
+
+	for _, grouping := range Groupings() {
+		if g == grouping {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Group represents the aggregated totals of a single group key.
+type Group struct {
+	Key   string `json:"key" yaml:"key"`
+	Files int    `json:"files" yaml:"files"`
+	Bytes int64  `json:"bytes" yaml:"bytes"`
+}
+
+// placeholders are the translations of the keys grouping files that lack the grouped attribute; the keys
+// themselves stay untranslated, so machine-facing documents don't vary by locale.
+var placeholders = map[string]i18n.Message{
+	"(none)":     i18n.GroupNoExtension,
+	"(untagged)": i18n.GroupUntagged,
+	"(unknown)":  i18n.GroupUnknownOwner,
+}
+
+// Keys returns the group keys of node n relative to root. A file may belong to several tag groups.
+func (g Grouping) Keys(root, n *tree.Node) []string {
+	switch g {
+	case GroupExtension:
+		extension := strings.ToLower(filepath.Ext(n.Name))
+		if extension == "" {
+			extension = "(none)"
+		}
+
+		return []string{extension}
+	case GroupDirectory:
+		relative, e := filepath.Rel(root.Path, n.Path)
+		if e != nil {
+			return []string{"."}
+		}
+
+		components := strings.SplitN(filepath.ToSlash(relative), "/", 2)
+		if len(components) < 2 {
+			return []string{"."}
+		}
+
+		return []string{components[0]}
+	case GroupTag:
+		if len(n.Tags) == 0 {
+			return []string{"(untagged)"}
+		}
+
+		return n.Tags
+	case GroupOwner:
+		if n.Owner == nil {
+			return []string{"(unknown)"}
+		}
+
+		return []string{username(n.Owner.UID)}
+	}
+
+	return []string{""}
+}
+
+var (
+	usernames  = map[int]string{}
+	groupnames = map[int]string{}
+)
+
+// username resolves a UID to its user name, falling back to the numeric identifier.
+func username(uid int) string {
+	if name, valid := usernames[uid]; valid {
+		return name
+	}
+
+	name := strconv.Itoa(uid)
+	if u, e := user.LookupId(name); e == nil {
+		name = u.Username
+	}
+
+	usernames[uid] = name
+
+	return name
+}
+
+// groupname resolves a GID to its group name, falling back to the numeric identifier.
+func groupname(gid int) string {
+	if name, valid := groupnames[gid]; valid {
+		return name
+	}
+
+	name := strconv.Itoa(gid)
+	if g, e := user.LookupGroupId(name); e == nil {
+		name = g.Name
+	}
+
+	groupnames[gid] = name
+
+	return name
+}
+
+// aggregate accumulates file n into the groups table.
+func (g Grouping) aggregate(table map[string]*Group, root, n *tree.Node) {
+	for _, key := range g.Keys(root, n) {
+		group, valid := table[key]
+		if !(valid) {
+			group = &Group{Key: key}
+			table[key] = group
+		}
+
+		group.Files++
+		group.Bytes += n.Size
+	}
+}
+
+// sorted returns the groups of table ordered by descending bytes, then key.
+func sorted(table map[string]*Group) []Group {
+	groups := make([]Group, 0, len(table))
+	for _, group := range table {
+		groups = append(groups, *group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Bytes != groups[j].Bytes {
+			return groups[i].Bytes > groups[j].Bytes
+		}
+
+		return groups[i].Key < groups[j].Key
+	})
+
+	return groups
+}
+
+// Title returns the human-facing column header of the Grouping.
+func (g Grouping) Title() string {
+	switch g {
+	case GroupExtension:
+		return i18n.T(i18n.GroupingExtension)
+	case GroupDirectory:
+		return i18n.T(i18n.GroupingDirectory)
+	case GroupTag:
+		return i18n.T(i18n.GroupingTag)
+	case GroupOwner:
+		return i18n.T(i18n.GroupingOwner)
+	}
+
+	return g.String()
+}
+
+// Label returns the human-facing rendering of a group key.
+func Label(key string) string {
+	if message, valid := placeholders[key]; valid {
+		return i18n.T(message)
+	}
+
+	return key
+}
+
+func (g Grouping) String() string {
+	if g == GroupNone {
+		return "none"
+	}
+
+	return string(g)
+}
+
+// Parse returns the Grouping of value, or an error if unsupported.
+func Parse(value string) (Grouping, error) {
+	g := Grouping(value)
+	if value == "none" {
+		g = GroupNone
+	}
+
+	if !(g.Valid()) {
+		return GroupNone, fmt.Errorf("unsupported grouping %q (expected one of %v)", value, Groupings())
+	}
+
+	return g, nil
+}
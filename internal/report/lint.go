@@ -0,0 +1,127 @@
+package report
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rule represents a security lint rule.
+type Rule string
+
+const (
+	// RuleCapabilities reports files granting privileges through file capabilities.
+	RuleCapabilities Rule = "capabilities"
+
+	// RuleImmutable reports immutable paths, which can't be modified, replaced, or removed.
+	RuleImmutable Rule = "immutable"
+
+	// RuleAppendOnly reports append-only paths, which can only be appended to.
+	RuleAppendOnly Rule = "append-only"
+
+	// RuleEncoding reports names that aren't valid UTF-8, which many tools and documents mangle.
+	RuleEncoding Rule = "invalid-utf8"
+)
+
+// locking maps the Linux and macOS file flag names to the rules reporting them.
+var locking = map[string]Rule{
+	"immutable": RuleImmutable,
+	"uchg":      RuleImmutable,
+	"schg":      RuleImmutable,
+	"append":    RuleAppendOnly,
+	"uappnd":    RuleAppendOnly,
+	"sappnd":    RuleAppendOnly,
+}
+
+// Finding represents a single security lint finding. Detail is its evidence: the capabilities granted,
+// the file flag, or the hex-encoded raw name.
+type Finding struct {
+	Path   string `json:"path" yaml:"path"`
+	Rule   Rule   `json:"rule" yaml:"rule"`
+	Detail string `json:"detail,omitempty" yaml:"detail,omitempty"`
+}
+
+// Findings represents the security lint report of a tree.
+type Findings struct {
+	Root     string    `json:"root" yaml:"root"`
+	Findings []Finding `json:"findings" yaml:"findings"`
+}
+
+// Lint reports the security-relevant paths of root, in path order. File flags are only reported
+// for trees walked with tree.WithMetadata.
+func Lint(root *tree.Node) *Findings {
+	f := &Findings{Root: tree.Escape(root.Path), Findings: make([]Finding, 0)}
+
+	var nodes []*tree.Node
+	root.Each(func(n *tree.Node) bool {
+		nodes = append(nodes, n)
+		return true
+	})
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Path < nodes[j].Path
+	})
+
+	for _, n := range nodes {
+		path := tree.Escape(n.Path)
+
+		if !(n.Valid()) {
+			f.Findings = append(f.Findings, Finding{Path: path, Rule: RuleEncoding, Detail: fmt.Sprintf("%x", n.Name)})
+		}
+
+		if n.Capabilities != "" {
+			f.Findings = append(f.Findings, Finding{Path: path, Rule: RuleCapabilities, Detail: n.Capabilities})
+		}
+
+		reported := map[Rule]bool{}
+		for _, flag := range n.Flags {
+			if rule, valid := locking[flag]; valid && !(reported[rule]) {
+				f.Findings = append(f.Findings, Finding{Path: path, Rule: rule, Detail: flag})
+				reported[rule] = true
+			}
+		}
+	}
+
+	return f
+}
+
+func (f *Findings) JSON() string {
+	buffer, e := json.MarshalIndent(f, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (f *Findings) YAML() string {
+	buffer, e := yaml.Marshal(f)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing findings table to w.
+func (f *Findings) Text(w io.Writer, style render.Style) {
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\t%s\n", i18n.T(i18n.LintRule), i18n.T(i18n.ReportPath), i18n.T(i18n.LintDetail))
+	for _, finding := range f.Findings {
+		detail := finding.Detail
+		if finding.Rule == RuleEncoding {
+			detail = i18n.T(i18n.LintRawName, detail)
+		}
+
+		fmt.Fprintf(t, "%s\t%s\t%s\n", finding.Rule, finding.Path, detail)
+	}
+
+	t.Flush()
+}
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Ownership represents the aggregated files and bytes owned by a single user or group.
+type Ownership struct {
+	ID    int    `json:"id" yaml:"id"`
+	Name  string `json:"name" yaml:"name"`
+	Files int    `json:"files" yaml:"files"`
+	Bytes int64  `json:"bytes" yaml:"bytes"`
+}
+
+// Owners represents the per-user and per-group usage report of a tree.
+type Owners struct {
+	Root    string      `json:"root" yaml:"root"`
+	Users   []Ownership `json:"users" yaml:"users"`
+	Groups  []Ownership `json:"groups" yaml:"groups"`
+	Unowned int         `json:"unowned,omitempty" yaml:"unowned,omitempty"`
+}
+
+// ByOwner aggregates the files and bytes of root per UID and per GID, resolving numeric
+// identifiers to user and group names where possible. Files without captured ownership are counted as Unowned.
+func ByOwner(root *tree.Node) *Owners {
+	users, groups := map[int]*Ownership{}, map[int]*Ownership{}
+
+	o := &Owners{Root: root.Path}
+	for _, n := range root.FilesRecursive() {
+		if n.Owner == nil {
+			o.Unowned++
+			continue
+		}
+
+		tally(users, n.Owner.UID, n, username)
+		tally(groups, n.Owner.GID, n, groupname)
+	}
+
+	o.Users, o.Groups = ownerships(users), ownerships(groups)
+
+	return o
+}
+
+func tally(table map[int]*Ownership, id int, n *tree.Node, resolve func(int) string) {
+	ownership, valid := table[id]
+	if !(valid) {
+		ownership = &Ownership{ID: id, Name: resolve(id)}
+		table[id] = ownership
+	}
+
+	ownership.Files++
+	ownership.Bytes += n.Size
+}
+
+// ownerships returns the entries of table ordered by descending bytes, then identifier.
+func ownerships(table map[int]*Ownership) []Ownership {
+	partials := make([]Ownership, 0, len(table))
+	for _, ownership := range table {
+		partials = append(partials, *ownership)
+	}
+
+	sort.Slice(partials, func(i, j int) bool {
+		if partials[i].Bytes != partials[j].Bytes {
+			return partials[i].Bytes > partials[j].Bytes
+		}
+
+		return partials[i].ID < partials[j].ID
+	})
+
+	return partials
+}
+
+func (o *Owners) JSON() string {
+	buffer, e := json.MarshalIndent(o, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (o *Owners) YAML() string {
+	buffer, e := yaml.Marshal(o)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing per-user and per-group tables to w.
+func (o *Owners) Text(w io.Writer, style render.Style) {
+	for i, section := range []struct {
+		title   string
+		entries []Ownership
+	}{{i18n.T(i18n.ReportUser), o.Users}, {i18n.T(i18n.ReportGroup), o.Groups}} {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+
+		t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", section.title, i18n.T(i18n.ReportID), i18n.T(i18n.ReportFiles), i18n.T(i18n.ReportBytes))
+		for _, entry := range section.entries {
+			fmt.Fprintf(t, "%s\t%d\t%d\t%s\n", entry.Name, entry.ID, entry.Files, render.Bytes(entry.Bytes))
+		}
+
+		t.Flush()
+	}
+
+	if o.Unowned > 0 {
+		fmt.Fprintf(w, "\n%s\n", i18n.T(i18n.ReportUnowned, o.Unowned))
+	}
+}
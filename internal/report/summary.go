@@ -0,0 +1,84 @@
+package report
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Summary represents the totals of a tree, optionally broken down by a Grouping.
+type Summary struct {
+	Root        string   `json:"root" yaml:"root"`
+	Files       int      `json:"files" yaml:"files"`
+	Directories int      `json:"directories" yaml:"directories"`
+	Bytes       int64    `json:"bytes" yaml:"bytes"`
+	Grouping    Grouping `json:"grouping,omitempty" yaml:"grouping,omitempty"`
+	Groups      []Group  `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// Summarize computes the Summary of the tree rooted at root.
+func Summarize(root *tree.Node, grouping Grouping) *Summary {
+	s := &Summary{
+		Root:        root.Path,
+		Files:       root.CountFiles(),
+		Directories: root.CountDirectories(),
+		Bytes:       root.CountBytes(),
+		Grouping:    grouping,
+	}
+
+	if grouping == GroupNone {
+		return s
+	}
+
+	table := map[string]*Group{}
+	for _, n := range root.FilesRecursive() {
+		grouping.aggregate(table, root, n)
+	}
+
+	s.Groups = sorted(table)
+
+	return s
+}
+
+func (s *Summary) JSON() string {
+	buffer, e := json.MarshalIndent(s, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (s *Summary) YAML() string {
+	buffer, e := yaml.Marshal(s)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing summary to w.
+func (s *Summary) Text(w io.Writer, style render.Style) {
+	fmt.Fprintln(w, i18n.T(i18n.ReportSummary, s.Root, s.Files, s.Directories, render.Bytes(s.Bytes)))
+
+	if len(s.Groups) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\t%s\n", s.Grouping.Title(), i18n.T(i18n.ReportFiles), i18n.T(i18n.ReportBytes))
+	for _, group := range s.Groups {
+		fmt.Fprintf(t, "%s\t%d\t%s\n", Label(group.Key), group.Files, render.Bytes(group.Bytes))
+	}
+
+	t.Flush()
+}
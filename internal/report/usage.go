@@ -0,0 +1,127 @@
+package report
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Usage represents the cumulative disk usage of a directory, optionally for a single group.
+type Usage struct {
+	Path  string `json:"path" yaml:"path"`
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+	Files int    `json:"files" yaml:"files"`
+	Bytes int64  `json:"bytes" yaml:"bytes"`
+}
+
+// DiskUsage represents the du-style report of a tree.
+type DiskUsage struct {
+	Root     string   `json:"root" yaml:"root"`
+	Grouping Grouping `json:"grouping,omitempty" yaml:"grouping,omitempty"`
+	Entries  []Usage  `json:"entries" yaml:"entries"`
+}
+
+// Du computes the cumulative usage of every directory of root down to depth levels
+// (zero meaning unlimited); with a Grouping, each directory is broken down by group.
+func Du(root *tree.Node, depth int, grouping Grouping) *DiskUsage {
+	tables := map[string]map[string]*Group{}
+
+	directories := []*tree.Node{root}
+	tables[root.Path] = map[string]*Group{}
+
+	for _, n := range root.DirectoriesRecursive() {
+		if depth == 0 || level(root, n) <= depth {
+			directories = append(directories, n)
+			tables[n.Path] = map[string]*Group{}
+		}
+	}
+
+	for _, n := range root.Map() {
+		if n.Type != tree.File {
+			continue
+		}
+
+		for ancestor := n.Parent(); ancestor != nil; ancestor = ancestor.Parent() {
+			if table, valid := tables[ancestor.Path]; valid {
+				grouping.aggregate(table, root, n)
+			}
+		}
+	}
+
+	u := &DiskUsage{Root: root.Path, Grouping: grouping, Entries: make([]Usage, 0)}
+	for _, directory := range directories {
+		groups := sorted(tables[directory.Path])
+		if len(groups) == 0 {
+			groups = []Group{{Key: ""}}
+		}
+
+		for _, group := range groups {
+			u.Entries = append(u.Entries, Usage{Path: directory.Path, Group: group.Key, Files: group.Files, Bytes: group.Bytes})
+		}
+	}
+
+	return u
+}
+
+// level returns the depth of n relative to root.
+func level(root, n *tree.Node) int {
+	relative, e := filepath.Rel(root.Path, n.Path)
+	if e != nil || relative == "." {
+		return 0
+	}
+
+	return strings.Count(filepath.ToSlash(relative), "/") + 1
+}
+
+func (u *DiskUsage) JSON() string {
+	buffer, e := json.MarshalIndent(u, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (u *DiskUsage) YAML() string {
+	buffer, e := yaml.Marshal(u)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing usage report to w; grouped entries are listed beneath their directory.
+func (u *DiskUsage) Text(w io.Writer, style render.Style) {
+	if u.Grouping == GroupNone {
+		t := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+		for _, entry := range u.Entries {
+			fmt.Fprintf(t, "%s\t%d\t  %s\n", render.Bytes(entry.Bytes), entry.Files, entry.Path)
+		}
+
+		t.Flush()
+
+		return
+	}
+
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	previous := ""
+	for _, entry := range u.Entries {
+		if entry.Path != previous {
+			fmt.Fprintf(t, "%s\t\t\n", entry.Path)
+			previous = entry.Path
+		}
+
+		fmt.Fprintf(t, "    %s\t%d\t%s\n", Label(entry.Group), entry.Files, render.Bytes(entry.Bytes))
+	}
+
+	t.Flush()
+}
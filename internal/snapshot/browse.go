@@ -0,0 +1,125 @@
+package snapshot
+
+import (
+	"bytes"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"fmt"
+	"os"
+)
+
+// Browse renders the Difference side by side on terminal out, reading keys from terminal in, so changes can be
+// reviewed interactively, expanding and collapsing directories, until q, escape, or ctrl-c.
+func (d *Difference) Browse(in, out *os.File, style render.Style) error {
+	restore, e := render.Raw(in)
+	if e != nil {
+		return e
+	}
+
+	defer restore()
+
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	cursor, top := 0, 0
+	key := make([]byte, 16)
+	for {
+		width, height, e := render.Size(out)
+		if e != nil || width <= 0 || height <= 0 {
+			width, height = 120, 40
+		}
+
+		entries := d.visible()
+		page := max(height-3, 1)
+
+		cursor = min(max(cursor, 0), len(entries)-1)
+		if cursor < top {
+			top = cursor
+		} else if cursor >= top+page {
+			top = cursor - page + 1
+		}
+
+		var screen bytes.Buffer
+		screen.WriteString("\x1b[H\x1b[2J")
+		screen.WriteString(d.header(width-2, style) + "\n")
+		for i := top; i < min(top+page, len(entries)); i++ {
+			line := d.row(entries[i], width-2, style)
+			if i == cursor {
+				screen.WriteString(style.Selected(">") + " " + line + "\n")
+			} else {
+				screen.WriteString("  " + line + "\n")
+			}
+		}
+
+		screen.WriteString("\x1b[" + fmt.Sprint(height) + ";1H" + style.Fit(d.totals()+"  "+i18n.T(i18n.BrowseHelp), width-1))
+		if _, e := out.Write(screen.Bytes()); e != nil {
+			return e
+		}
+
+		n, e := in.Read(key)
+		if e != nil {
+			return e
+		}
+
+		selected := entries[cursor]
+		switch string(key[:n]) {
+		case "q", "\x1b", "\x03":
+			return nil
+		case "j", "\x1b[B", "\x1bOB":
+			cursor++
+		case "k", "\x1b[A", "\x1bOA":
+			cursor--
+		case "\x1b[6~", "\x06":
+			cursor += page
+		case "\x1b[5~", "\x02":
+			cursor -= page
+		case "g", "\x1b[H", "\x1bOH":
+			cursor = 0
+		case "G", "\x1b[F", "\x1bOF":
+			cursor = len(entries) - 1
+		case " ", "\r":
+			selected.expanded = len(selected.Children) > 0 && !(selected.expanded)
+		case "l", "\x1b[C", "\x1bOC":
+			selected.expanded = len(selected.Children) > 0
+		case "h", "\x1b[D", "\x1bOD":
+			if selected.expanded {
+				selected.expanded = false
+			} else if selected.parent != nil {
+				selected.parent.expanded = false
+				cursor = index(d.visible(), selected.parent)
+			}
+		case "n":
+			cursor = seek(entries, cursor, 1)
+		case "N":
+			cursor = seek(entries, cursor, -1)
+		case "a":
+			d.Expand(true)
+			cursor = index(d.visible(), selected)
+		case "c":
+			d.Expand(false)
+			cursor = max(index(d.visible(), selected), 0)
+		}
+	}
+}
+
+// index returns the position of e among entries, or -1.
+func index(entries []*Entry, e *Entry) int {
+	for i, candidate := range entries {
+		if candidate == e {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// seek returns the position of the next visible entry holding changes from cursor in direction, or cursor.
+func seek(entries []*Entry, cursor, direction int) int {
+	for i := cursor + direction; i >= 0 && i < len(entries); i += direction {
+		if entries[i].Change != Unchanged || (!(entries[i].expanded) && entries[i].Drift > 0) {
+			return i
+		}
+	}
+
+	return cursor
+}
@@ -0,0 +1,434 @@
+package snapshot
+
+import (
+	"cli/internal/fs/tree"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"cli/internal/report"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Change represents how an Entry differs between two snapshots.
+type Change string
+
+const (
+	Unchanged Change = "unchanged"
+	Added     Change = "added"
+	Removed   Change = "removed"
+	Modified  Change = "modified"
+)
+
+// Entry represents a path of either or both of two snapshots' trees.
+type Entry struct {
+	// Path is relative to the snapshots' roots; the roots' own Entry is ".".
+	Path   string
+	Name   string
+	Change Change
+
+	// Fields are the names of a Modified Entry's differing attributes.
+	Fields []string
+
+	// Left and Right are the Entry's nodes in the first and second snapshots; either is nil when absent.
+	Left, Right *tree.Node
+
+	Children []*Entry
+
+	// Drift counts the changed entries of the Entry's subtree, itself included.
+	Drift int
+
+	depth    int
+	parent   *Entry
+	expanded bool
+}
+
+// Difference represents the entry-by-entry comparison of two snapshots.
+type Difference struct {
+	Left  string `json:"left" yaml:"left"`
+	Right string `json:"right" yaml:"right"`
+
+	// Width is the columns of the Text rendering; zero means 120.
+	Width int `json:"-" yaml:"-"`
+
+	// Grouping, if set, totals the changed files by group; see Groups.
+	Grouping report.Grouping `json:"-" yaml:"-"`
+
+	root *Entry
+}
+
+// Group represents the changed files of a single group key.
+type Group struct {
+	Key      string `json:"key" yaml:"key"`
+	Added    int    `json:"added" yaml:"added"`
+	Removed  int    `json:"removed" yaml:"removed"`
+	Modified int    `json:"modified" yaml:"modified"`
+}
+
+// Diff compares snapshots a and b by path relative to their roots. Checksums are only compared when both
+// were calculated with the same algorithm, and opt-in attributes (mode, owner, security context, capabilities,
+// and flags) only when both snapshots captured them. Directories holding changes start expanded.
+func Diff(a, b *Snapshot) *Difference {
+	d := &Difference{Left: a.Meta.Root, Right: b.Meta.Root}
+	d.root = merge(nil, ".", ".", a.Tree, b.Tree)
+	d.Expand(false)
+
+	return d
+}
+
+func merge(parent *Entry, name, path string, left, right *tree.Node) *Entry {
+	entry := &Entry{Path: path, Name: name, Left: left, Right: right, parent: parent}
+	if parent != nil {
+		entry.depth = parent.depth + 1
+	}
+
+	switch {
+	case left == nil:
+		entry.Change = Added
+	case right == nil:
+		entry.Change = Removed
+	default:
+		entry.Fields = compare(left, right)
+		entry.Change = Unchanged
+		if len(entry.Fields) > 0 {
+			entry.Change = Modified
+		}
+	}
+
+	if entry.Change != Unchanged {
+		entry.Drift = 1
+	}
+
+	children := map[string][2]*tree.Node{}
+	for i, side := range []*tree.Node{left, right} {
+		if side == nil {
+			continue
+		}
+
+		for _, child := range side.Children() {
+			pair := children[child.Name]
+			pair[i] = child
+			children[child.Name] = pair
+		}
+	}
+
+	names := make([]string, 0, len(children))
+	for name := range children {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		pair := children[name]
+
+		child := merge(entry, name, filepath.Join(path, name), pair[0], pair[1])
+		entry.Children = append(entry.Children, child)
+		entry.Drift += child.Drift
+	}
+
+	return entry
+}
+
+// compare returns the names of the attributes differing between a and b.
+func compare(a, b *tree.Node) (fields []string) {
+	differ := func(field string, different bool) {
+		if different {
+			fields = append(fields, field)
+		}
+	}
+
+	captured := func(x, y string) bool {
+		return x != "" && y != "" && x != y
+	}
+
+	differ("type", a.Type != b.Type)
+	differ("target", a.Target != b.Target)
+	if a.Type == tree.File && b.Type == tree.File {
+		differ("size", a.Size != b.Size)
+		differ("checksum", a.Checksum != nil && b.Checksum != nil && a.Algorithm == b.Algorithm && *a.Checksum != *b.Checksum)
+	}
+
+	differ("mode", captured(a.Mode, b.Mode))
+	differ("owner", a.Owner != nil && b.Owner != nil && *a.Owner != *b.Owner)
+	differ("context", captured(a.Context, b.Context))
+	differ("capabilities", a.Capabilities != b.Capabilities)
+	differ("flags", a.Flags != nil && b.Flags != nil && !(slices.Equal(a.Flags, b.Flags)))
+
+	return
+}
+
+// Expand expands every directory holding changes, or with all, every directory, collapsing the rest.
+func (d *Difference) Expand(all bool) {
+	var expand func(e *Entry)
+	expand = func(e *Entry) {
+		e.expanded = len(e.Children) > 0 && (all || e.Drift > 0)
+		for _, child := range e.Children {
+			expand(child)
+		}
+	}
+
+	expand(d.root)
+}
+
+// Changes returns the changed entries, in path order.
+func (d *Difference) Changes() []*Entry {
+	var changes []*Entry
+
+	var collect func(e *Entry)
+	collect = func(e *Entry) {
+		if e.Change != Unchanged {
+			changes = append(changes, e)
+		}
+
+		for _, child := range e.Children {
+			collect(child)
+		}
+	}
+
+	collect(d.root)
+
+	return changes
+}
+
+// Groups returns the changed files totaled by the Difference's Grouping, ordered by descending changes,
+// then key; a file is grouped by its second snapshot's node, unless it was removed.
+func (d *Difference) Groups() []Group {
+	if d.Grouping == report.GroupNone {
+		return nil
+	}
+
+	table := map[string]*Group{}
+	for _, e := range d.Changes() {
+		root, n := d.root.Right, e.Right
+		if e.Change == Removed {
+			root, n = d.root.Left, e.Left
+		}
+
+		if n.Type != tree.File {
+			continue
+		}
+
+		for _, key := range d.Grouping.Keys(root, n) {
+			group, valid := table[key]
+			if !(valid) {
+				group = &Group{Key: key}
+				table[key] = group
+			}
+
+			switch e.Change {
+			case Added:
+				group.Added++
+			case Removed:
+				group.Removed++
+			case Modified:
+				group.Modified++
+			}
+		}
+	}
+
+	groups := make([]Group, 0, len(table))
+	for _, group := range table {
+		groups = append(groups, *group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if x, y := a.Added+a.Removed+a.Modified, b.Added+b.Removed+b.Modified; x != y {
+			return x > y
+		}
+
+		return a.Key < b.Key
+	})
+
+	return groups
+}
+
+// visible returns the entries shown by the expansion state, in display order.
+func (d *Difference) visible() []*Entry {
+	var entries []*Entry
+
+	var collect func(e *Entry)
+	collect = func(e *Entry) {
+		entries = append(entries, e)
+		if e.expanded {
+			for _, child := range e.Children {
+				collect(child)
+			}
+		}
+	}
+
+	collect(d.root)
+
+	return entries
+}
+
+// change represents a changed Entry in the JSON and YAML documents.
+type change struct {
+	Path   string          `json:"path" yaml:"path"`
+	Change Change          `json:"change" yaml:"change"`
+	Type   tree.Descriptor `json:"type" yaml:"type"`
+	Fields []string        `json:"fields,omitempty" yaml:"fields,omitempty"`
+}
+
+// document is the JSON and YAML representation of a Difference.
+type document struct {
+	Left     string          `json:"left" yaml:"left"`
+	Right    string          `json:"right" yaml:"right"`
+	Changes  []change        `json:"changes" yaml:"changes"`
+	Grouping report.Grouping `json:"grouping,omitempty" yaml:"grouping,omitempty"`
+	Groups   []Group         `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+func (d *Difference) document() document {
+	doc := document{Left: d.Left, Right: d.Right, Changes: make([]change, 0), Grouping: d.Grouping, Groups: d.Groups()}
+	for _, e := range d.Changes() {
+		c := change{Path: tree.Escape(e.Path), Change: e.Change, Fields: e.Fields}
+		if e.Right != nil {
+			c.Type = e.Right.Type
+		} else {
+			c.Type = e.Left.Type
+		}
+
+		doc.Changes = append(doc.Changes, c)
+	}
+
+	return doc
+}
+
+func (d *Difference) JSON() string {
+	buffer, e := json.MarshalIndent(d.document(), "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (d *Difference) YAML() string {
+	buffer, e := yaml.Marshal(d.document())
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the side-by-side rendering of the expanded entries, then the change totals, and those of each
+// group, to w.
+func (d *Difference) Text(w io.Writer, style render.Style) {
+	width := d.Width
+	if width <= 0 {
+		width = 120
+	}
+
+	fmt.Fprintln(w, d.header(width, style))
+	for _, e := range d.visible() {
+		fmt.Fprintln(w, d.row(e, width, style))
+	}
+
+	fmt.Fprintf(w, "\n%s\n", d.totals())
+
+	groups := d.Groups()
+	if len(groups) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+
+	t := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(t, "%s\t%s\t%s\t%s\n", d.Grouping.Title(), i18n.T(i18n.DiffAdded), i18n.T(i18n.DiffRemoved), i18n.T(i18n.DiffModified))
+	for _, group := range groups {
+		fmt.Fprintf(t, "%s\t%d\t%d\t%d\n", report.Label(group.Key), group.Added, group.Removed, group.Modified)
+	}
+
+	t.Flush()
+}
+
+// totals returns the human-facing counts of added, removed, and modified entries.
+func (d *Difference) totals() string {
+	counts := map[Change]int{}
+	for _, e := range d.Changes() {
+		counts[e.Change]++
+	}
+
+	return i18n.T(i18n.DiffTotals, counts[Added], counts[Removed], counts[Modified])
+}
+
+// column returns the width of each side of a width-column row, between which is a three-column gutter.
+func column(width int) int {
+	return max((width-3)/2, 8)
+}
+
+func (d *Difference) header(width int, style render.Style) string {
+	return style.Fit(d.Left, column(width)) + " | " + strings.TrimRight(style.Fit(d.Right, column(width)), " ")
+}
+
+// row returns the side-by-side line of entry e: each side's node, and a gutter marking the change as in sdiff(1).
+func (d *Difference) row(e *Entry, width int, style render.Style) string {
+	left := style.Fit(e.cell(e.Left, style), column(width))
+	right := strings.TrimRight(style.Fit(e.cell(e.Right, style), column(width)), " ")
+
+	switch e.Change {
+	case Added:
+		return left + " > " + style.Added(right)
+	case Removed:
+		return style.Removed(left) + " <"
+	case Modified:
+		return style.Modified(left) + " | " + style.Modified(right)
+	}
+
+	return left + "   " + right
+}
+
+// cell returns the description of n, one side of e: its name, indented by depth, and size or pending changes.
+func (e *Entry) cell(n *tree.Node, style render.Style) string {
+	if n == nil {
+		return ""
+	}
+
+	indent := ""
+	for i := 0; i < e.depth; i++ {
+		indent += "  "
+	}
+
+	glyph := "  "
+	if len(e.Children) > 0 {
+		switch {
+		case style.Plain && e.expanded:
+			glyph = "- "
+		case style.Plain:
+			glyph = "+ "
+		case e.expanded:
+			glyph = "▾ "
+		default:
+			glyph = "▸ "
+		}
+	}
+
+	name := tree.Escape(e.Name)
+	switch n.Type {
+	case tree.Directory:
+		name += "/"
+	case tree.Symbolic:
+		name += " -> " + tree.Escape(n.Target)
+	}
+
+	detail := ""
+	if n.Type == tree.File {
+		detail = "  " + render.Bytes(n.Size)
+	}
+
+	if !(e.expanded) && e.Drift > 0 && len(e.Children) > 0 {
+		detail += "  " + i18n.T(i18n.DiffCollapsed, e.Drift)
+	}
+
+	return indent + glyph + name + detail
+}
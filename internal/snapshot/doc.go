@@ -0,0 +1,2 @@
+// Package snapshot represents a point-in-time inventory document of a file-system tree.
+package snapshot
@@ -0,0 +1,115 @@
+package snapshot
+
+import (
+	"cli/internal/exception"
+	"cli/internal/fs/tree"
+	"cli/internal/fs/volume"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Version is the snapshot document schema version.
+const Version = "1"
+
+var ExceptionIncomparable = exception.New(exception.EINCOMPARABLE, "", "", nil)
+
+// Meta represents the snapshot metadata.
+type Meta struct {
+	Version string    `json:"version" yaml:"version"`
+	Created time.Time `json:"created" yaml:"created"`
+	Root    string    `json:"root" yaml:"root"`
+
+	// Configuration is the digest of the effective configuration the snapshot was produced with.
+	Configuration string `json:"configuration" yaml:"configuration"`
+
+	// ReadOnly records that the tree was walked, and verified, under the read-only assertion.
+	ReadOnly bool `json:"read-only,omitempty" yaml:"read-only,omitempty"`
+
+	// Volume is the file-system type and capacity of the volume holding Root, where supported.
+	Volume *volume.Volume `json:"volume,omitempty" yaml:"volume,omitempty"`
+}
+
+// Snapshot represents a tree along with its Meta.
+type Snapshot struct {
+	Meta Meta       `json:"meta" yaml:"meta"`
+	Tree *tree.Node `json:"tree" yaml:"tree"`
+}
+
+// New creates a Snapshot of t, recording the digest of the effective configuration.
+func New(t *tree.Node, configuration any) *Snapshot {
+	s := &Snapshot{
+		Meta: Meta{
+			Version:       Version,
+			Created:       time.Now().UTC(),
+			Root:          t.URI(),
+			Configuration: Digest(configuration),
+			ReadOnly:      t.ReadOnly(),
+		},
+		Tree: t,
+	}
+
+	if v, e := volume.Of(t.Path); e == nil {
+		s.Meta.Volume = v
+	}
+
+	return s
+}
+
+// Digest returns the sha256 digest of v's canonical JSON encoding.
+func Digest(v any) string {
+	buffer, e := json.Marshal(v)
+	if e != nil {
+		panic(e)
+	}
+
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(buffer))
+}
+
+// Read reads a JSON or YAML snapshot document from path.
+func Read(path string) (*Snapshot, error) {
+	buffer, e := os.ReadFile(path)
+	if e != nil {
+		return nil, e
+	}
+
+	s := &Snapshot{}
+	if e := yaml.Unmarshal(buffer, s); e != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %w", path, e)
+	} else if s.Tree == nil {
+		return nil, fmt.Errorf("invalid snapshot %s: missing tree", path)
+	}
+
+	return s, nil
+}
+
+// Comparable returns an error if the two snapshots were produced with different configurations.
+func (s *Snapshot) Comparable(other *Snapshot) error {
+	if s.Meta.Configuration != other.Meta.Configuration {
+		return exception.New(exception.EINCOMPARABLE, "compare", "", fmt.Errorf("configuration %s != %s", s.Meta.Configuration, other.Meta.Configuration))
+	}
+
+	return nil
+}
+
+func (s *Snapshot) JSON() string {
+	buffer, e := json.MarshalIndent(s, "", "    ")
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+func (s *Snapshot) YAML() string {
+	buffer, e := yaml.Marshal(s)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
@@ -0,0 +1,2 @@
+// Package usage represents the resource usage of a command: wall and CPU time, peak memory, and throughput.
+package usage
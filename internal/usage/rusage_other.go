@@ -0,0 +1,9 @@
+//go:build !unix
+
+package usage
+
+import "time"
+
+func rusage() (user, system time.Duration, rss int64) {
+	return 0, 0, 0
+}
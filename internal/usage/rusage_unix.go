@@ -0,0 +1,25 @@
+//go:build unix
+
+package usage
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+)
+
+// rusage returns the process's user and system CPU time, and peak resident set size in bytes.
+func rusage() (user, system time.Duration, rss int64) {
+	var ru syscall.Rusage
+	if e := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); e != nil {
+		return 0, 0, 0
+	}
+
+	// ru_maxrss is reported in bytes on darwin, and in kilobytes elsewhere.
+	rss = int64(ru.Maxrss)
+	if runtime.GOOS != "darwin" && runtime.GOOS != "ios" {
+		rss *= 1024
+	}
+
+	return time.Duration(ru.Utime.Nano()), time.Duration(ru.Stime.Nano()), rss
+}
@@ -0,0 +1,116 @@
+package usage
+
+import (
+	"cli/internal/fs/concurrency"
+	"cli/internal/i18n"
+	"cli/internal/render"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Meter accumulates the resource usage of a command from its start.
+type Meter struct {
+	start time.Time
+
+	mutex       sync.Mutex
+	files       int
+	bytes       int64
+	operations  []string
+	controllers []*concurrency.Controller
+}
+
+// Start returns a Meter measuring from now.
+func Start() *Meter {
+	return &Meter{start: time.Now()}
+}
+
+// Add records files and bytes processed by the command; a nil Meter discards them.
+func (m *Meter) Add(files int, bytes int64) {
+	if m == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	m.files += files
+	m.bytes += bytes
+	m.mutex.Unlock()
+}
+
+// Track reports the levels c chooses for operation, e.g. "hash", with the Usage, once it's run anything; a
+// nil Meter discards it.
+func (m *Meter) Track(operation string, c *concurrency.Controller) {
+	if m == nil {
+		return
+	}
+
+	m.mutex.Lock()
+	m.operations = append(m.operations, operation)
+	m.controllers = append(m.controllers, c)
+	m.mutex.Unlock()
+}
+
+// Concurrency represents the worker levels chosen for an Operation.
+type Concurrency struct {
+	Operation string `json:"operation"`
+	*concurrency.Report
+}
+
+// Usage represents a command's resource usage. CPU times and PeakRSS are zero where the platform doesn't report them.
+type Usage struct {
+	Command        string  `json:"command"`
+	Wall           float64 `json:"wall-seconds"`
+	User           float64 `json:"user-seconds"`
+	System         float64 `json:"system-seconds"`
+	PeakRSS        int64   `json:"peak-rss"`
+	Files          int     `json:"files"`
+	Bytes          int64   `json:"bytes"`
+	FilesPerSecond float64 `json:"files-per-second"`
+	BytesPerSecond float64 `json:"bytes-per-second"`
+
+	Concurrency []Concurrency `json:"concurrency,omitempty"`
+}
+
+// Usage returns the resource usage of command so far.
+func (m *Meter) Usage(command string) *Usage {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	u := &Usage{Command: command, Wall: time.Since(m.start).Seconds(), Files: m.files, Bytes: m.bytes}
+	user, system, rss := rusage()
+	u.User, u.System, u.PeakRSS = user.Seconds(), system.Seconds(), rss
+
+	if u.Wall > 0 {
+		u.FilesPerSecond = float64(u.Files) / u.Wall
+		u.BytesPerSecond = float64(u.Bytes) / u.Wall
+	}
+
+	for i, c := range m.controllers {
+		if r := c.Report(); r.Operations > 0 {
+			u.Concurrency = append(u.Concurrency, Concurrency{Operation: m.operations[i], Report: r})
+		}
+	}
+
+	return u
+}
+
+func (u *Usage) JSON() string {
+	buffer, e := json.Marshal(u)
+	if e != nil {
+		panic(e)
+	}
+
+	return string(buffer)
+}
+
+// Text writes the human-facing usage summary to w: a single line, followed by one per operation's worker levels.
+func (u *Usage) Text(w io.Writer) {
+	fmt.Fprintln(w, i18n.T(i18n.UsageSummary,
+		u.Command, u.Wall, u.User, u.System, render.Bytes(u.PeakRSS), u.Files, u.FilesPerSecond, render.Bytes(u.Bytes), render.Bytes(int64(u.BytesPerSecond))))
+
+	for _, c := range u.Concurrency {
+		fmt.Fprintf(w, "  %s: %s\n", c.Operation, c.Report)
+	}
+}
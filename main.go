@@ -1,13 +1,9 @@
 package main
 
 import (
-	"cli/internal/fs/tree"
-	"fmt"
+	root "cli/commands"
 )
 
 func main() {
-	t := tree.New("./internal")
-
-	fmt.Println(t)
-	fmt.Println(t.YAML())
+	root.Execute()
 }